@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/ManoloEsS/burrow/internal/cli"
 	"github.com/ManoloEsS/burrow/internal/config"
 	"github.com/ManoloEsS/burrow/internal/database"
 	"github.com/ManoloEsS/burrow/internal/service"
@@ -14,6 +19,10 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1:]))
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("Configuration error: %v", err)
@@ -27,28 +36,79 @@ func main() {
 
 	ui := tui.NewTui(cfg)
 
-	ui.HttpService = service.NewHttpClientService(db)
-	ui.ServerService = service.NewServerService()
+	servers := service.NewServerService(db, cfg)
+	ui.HttpService = service.NewHttpClientService(db, cfg)
+	ui.ServerService = servers
 
 	if err := ui.Initialize(); err != nil {
 		log.Fatalf("Failed to initialize UI: %v", err)
 	}
 
-	setupShutdown(db)
+	setupShutdown(db, servers)
 
 	if err := ui.Start(); err != nil {
 		log.Fatalf("Failed to start application: %v", err)
 	}
+	stopServers(servers)
+}
+
+func runCommand(args []string) int {
+	// keep stdout and stderr clean for scripts, diagnostics are reported explicitly
+	log.SetOutput(io.Discard)
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration error: %v\n", err)
+		return 1
+	}
+
+	db, err := database.NewDatabase(cfg.Database.Path, cfg.Database.ConnectionString)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not initialize database: %v\n", err)
+		return 1
+	}
+	defer func() { _ = db.Close() }()
+
+	// an interrupt aborts the request in flight so the deferred close still runs
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return cli.New(cfg, service.NewHttpClientService(db, cfg), os.Stdout, os.Stderr).Run(ctx, args)
 }
 
-func setupShutdown(db *database.Database) {
+func setupShutdown(db *database.Database, servers service.ServerService) {
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 
 	go func() {
 		<-c
+		stopServers(servers)
 		log.Println("Shutting down database safely")
 		_ = db.Close()
 		os.Exit(0)
 	}()
 }
+
+// serverShutdownWait bounds the wait for the servers on exit, a server that
+// ignores SIGTERM is killed after 5 seconds.
+const serverShutdownWait = 6 * time.Second
+
+// stopServers stops the running servers so none outlives burrow, waiting at
+// most serverShutdownWait for them to exit.
+func stopServers(servers service.ServerService) {
+	if servers.StopAllServers() != nil {
+		return
+	}
+	deadline := time.Now().Add(serverShutdownWait)
+	for time.Now().Before(deadline) {
+		active := false
+		for _, info := range servers.Servers() {
+			active = active || info.State.Active()
+		}
+		if !active {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	log.Println("Servers did not stop in time")
+}
@@ -0,0 +1,742 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/ManoloEsS/burrow/internal/importer"
+	"github.com/ManoloEsS/burrow/internal/service"
+)
+
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+const usageText = `Usage: burrow [command] [flags]
+
+Run without a command to start the terminal UI.
+
+Commands:
+  list [--collection <path>] List saved requests
+  run <name>                 Send a saved request
+  send [flags] <url>         Send an ad-hoc request
+  test [names...]            Run saved requests as a test suite (all when no names given)
+  import [--dry-run] <file>  Import a Postman v2.1 collection, Insomnia export or OpenAPI 3 spec
+  secret set <name> [value]  Encrypt a secret into the database, reading the value from stdin
+                             when it is not given
+  secret list                List secret names and where they are read from
+  secret rm <name>           Delete a secret from the database
+  help                       Show this help
+
+Flags for run, send and test:
+  --env <name>               Resolve {{placeholders}} with the given environment
+  -o, --output <format>      text or json; test also accepts junit and tap (default text)
+
+Flags for list and test:
+  --collection <path>        Only requests in the collection and its sub collections,
+                             given as a slash separated path such as payments/refunds
+
+Flags for send:
+  -X, --request <method>     HTTP method (default GET)
+  -H, --header <key:value>   Request header, repeatable
+  -p, --param <key:value>    Query parameter, repeatable
+  -d, --data <body>          Request body, or the path of the file to send with --type File
+  -t, --type <type>          Body type: Text, JSON, Form, Multipart, File or GraphQL
+                             (default Text); a GraphQL body is the query
+  --variables <json>         Variables of a GraphQL query, as a JSON object
+  -F, --form <key:value>     Form field, key:@path for a file, repeatable;
+                             sends a Multipart body unless --type is Form
+  -a, --assert <assertion>   Assertion such as "status == 200", repeatable
+  -X WS, or a ws:// URL       Open a WebSocket, send --data as a message and print every
+                             frame until the server closes it or Ctrl-C
+  --stream                   Print the body as it arrives, Server-Sent Events one by one,
+                             until the server ends it or Ctrl-C; implied by an
+                             Accept: text/event-stream header
+  -X GRPC, or a grpc:// URL  Call the gRPC method in the URL, such as
+                             grpc://localhost:50051/helloworld.Greeter/SayHello, with
+                             --data as the JSON request message; without a method the
+                             services found by server reflection are listed
+  --proto <file>             .proto file describing the gRPC service instead of server
+                             reflection, repeatable
+
+Flags for import:
+  --dry-run                  Show what would be imported without saving
+  -o, --output <format>      text or json (default text)
+
+Requests reference secrets as {{secret.<name>}}. They are read from
+BURROW_SECRET_<NAME> variables, the secrets file and the database, where
+they are encrypted with the passphrase in BURROW_PASSPHRASE.
+
+Exit status is 1 on transport errors, non-2xx responses, GraphQL errors or gRPC
+statuses other than OK without assertions, failed assertions or failed tests,
+and 2 on usage errors.
+`
+
+type CLI struct {
+	cfg         *config.Config
+	httpService service.HttpClientService
+	stdin       io.Reader
+	stdout      io.Writer
+	stderr      io.Writer
+}
+
+func New(cfg *config.Config, httpService service.HttpClientService, stdout, stderr io.Writer) *CLI {
+	return &CLI{
+		cfg:         cfg,
+		httpService: httpService,
+		stdin:       os.Stdin,
+		stdout:      stdout,
+		stderr:      stderr,
+	}
+}
+
+// Run executes the command in args, cancelling ctx aborts requests that are
+// in flight.
+func (c *CLI) Run(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		c.usage()
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "list":
+		return c.runList(args[1:])
+	case "run":
+		return c.runSaved(ctx, args[1:])
+	case "send":
+		return c.runSend(ctx, args[1:])
+	case "test":
+		return c.runTest(ctx, args[1:])
+	case "import":
+		return c.runImport(args[1:])
+	case "secret":
+		return c.runSecret(args[1:])
+	case "help", "-h", "--help":
+		_, _ = fmt.Fprint(c.stdout, usageText)
+		return exitOK
+	default:
+		_, _ = fmt.Fprintf(c.stderr, "unknown command %q\n\n", args[0])
+		c.usage()
+		return exitUsage
+	}
+}
+
+func (c *CLI) runList(args []string) int {
+	fs := c.newFlagSet("list")
+	output := outputFlag(fs)
+	collection := fs.String("collection", "", "collection path")
+
+	if _, err := parseInterspersed(fs, args); err != nil {
+		return exitUsage
+	}
+
+	reqs, err := c.httpService.GetSavedRequests()
+	if err == nil && *collection != "" {
+		reqs, err = c.collectionRequests(reqs, *collection)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+
+	if err := writeRequestList(c.stdout, reqs, *output); err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+
+	return exitOK
+}
+
+func (c *CLI) runSaved(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("run")
+	output := outputFlag(fs)
+	env := fs.String("env", "", "environment name")
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(positional) != 1 {
+		_, _ = fmt.Fprintln(c.stderr, "run requires exactly one request name")
+		return exitUsage
+	}
+
+	if err := c.activateEnvironment(*env); err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+
+	req, err := c.httpService.GetRequest(strings.ToLower(positional[0]))
+	if err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+
+	return c.send(ctx, req, *output)
+}
+
+func (c *CLI) runSend(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("send")
+	output := outputFlag(fs)
+	env := fs.String("env", "", "environment name")
+
+	method := fs.String("request", "GET", "HTTP method")
+	fs.StringVar(method, "X", "GET", "HTTP method")
+
+	var headers, params stringList
+	fs.Var(&headers, "header", "request header")
+	fs.Var(&headers, "H", "request header")
+	fs.Var(&params, "param", "query parameter")
+	fs.Var(&params, "p", "query parameter")
+
+	body := fs.String("data", "", "request body")
+	fs.StringVar(body, "d", "", "request body")
+
+	bodyType := fs.String("type", "Text", "body type")
+	fs.StringVar(bodyType, "t", "Text", "body type")
+
+	variables := fs.String("variables", "", "GraphQL variables")
+
+	var form stringList
+	fs.Var(&form, "form", "form field")
+	fs.Var(&form, "F", "form field")
+
+	var assertions stringList
+	fs.Var(&assertions, "assert", "response assertion")
+	fs.Var(&assertions, "a", "response assertion")
+
+	stream := fs.Bool("stream", false, "print the response as it arrives")
+
+	var protoFiles stringList
+	fs.Var(&protoFiles, "proto", "proto file")
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(positional) > 1 {
+		_, _ = fmt.Fprintln(c.stderr, "send accepts a single url")
+		return exitUsage
+	}
+
+	url := ""
+	if len(positional) == 1 {
+		url = positional[0]
+	}
+
+	if err := c.activateEnvironment(*env); err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+
+	if len(form) > 0 {
+		if *body != "" {
+			_, _ = fmt.Fprintln(c.stderr, "Error: --form cannot be combined with --data")
+			return exitUsage
+		}
+		if *bodyType != "Form" {
+			*bodyType = "Multipart"
+		}
+		*body = strings.Join(form, "\n")
+	}
+
+	req := domain.NewRequest()
+	err = req.BuildRequest("", *method, url, "", "", *bodyType, *body, c.cfg)
+	if err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+
+	if *variables != "" && req.GraphQL == nil {
+		_, _ = fmt.Fprintln(c.stderr, "Error: --variables requires --type GraphQL")
+		return exitUsage
+	}
+	if err := req.ParseGraphQLVariables(*variables); err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+	if err := addPairs(req.Headers, headers); err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: invalid header: %v\n", err)
+		return exitUsage
+	}
+	if err := addPairs(req.Params, params); err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: invalid param: %v\n", err)
+		return exitUsage
+	}
+	if err := req.ParseAssertions(strings.Join(assertions, "\n")); err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+	if *stream || len(protoFiles) > 0 {
+		req.Options = &domain.ClientOptions{Stream: *stream, ProtoFiles: strings.Join(protoFiles, " ")}
+	}
+	if req.IsWebSocket() {
+		msg, ok, err := req.WSMessage()
+		if err != nil {
+			_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+			return exitUsage
+		}
+		if ok {
+			req.Messages = append(req.Messages, msg)
+		}
+	}
+
+	return c.send(ctx, req, *output)
+}
+
+func (c *CLI) runTest(ctx context.Context, args []string) int {
+	fs := c.newFlagSet("test")
+	output := outputFlag(fs)
+	env := fs.String("env", "", "environment name")
+	collection := fs.String("collection", "", "collection path")
+
+	names, err := parseInterspersed(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(names) > 0 && *collection != "" {
+		_, _ = fmt.Fprintln(c.stderr, "test accepts either request names or --collection, not both")
+		return exitUsage
+	}
+
+	if err := c.activateEnvironment(*env); err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+
+	reqs, err := c.suiteRequests(names, *collection)
+	if err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+
+	suiteName := "saved-requests"
+	if *collection != "" {
+		suiteName = strings.Trim(strings.ToLower(*collection), "/")
+	}
+	suite := service.RunSuite(ctx, c.httpService, suiteName, reqs)
+
+	if err := writeSuite(c.stdout, suite, *output); err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+
+	if ctx.Err() != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Interrupted after %d of %d requests\n", len(suite.Cases), len(reqs))
+		return exitFailure
+	}
+	if !suite.Passed() {
+		return exitFailure
+	}
+	return exitOK
+}
+
+func (c *CLI) runImport(args []string) int {
+	fs := c.newFlagSet("import")
+	output := outputFlag(fs)
+	dryRun := fs.Bool("dry-run", false, "do not save requests")
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(positional) != 1 {
+		_, _ = fmt.Fprintln(c.stderr, "import requires exactly one file")
+		return exitUsage
+	}
+
+	result, err := importer.ImportFile(positional[0], c.cfg)
+	if err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+
+	if !*dryRun {
+		if err := service.ImportRequests(c.httpService, result); err != nil {
+			_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+			return exitFailure
+		}
+	}
+
+	if err := writeImport(c.stdout, result, *dryRun, *output); err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+
+	return exitOK
+}
+
+func (c *CLI) runSecret(args []string) int {
+	fs := c.newFlagSet("secret")
+	output := outputFlag(fs)
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(positional) == 0 {
+		_, _ = fmt.Fprintln(c.stderr, "secret requires set, list or rm")
+		return exitUsage
+	}
+
+	switch action, rest := positional[0], positional[1:]; {
+	case action == "list" && len(rest) == 0:
+		secrets, err := c.httpService.GetSecrets()
+		if err != nil {
+			_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+			return exitFailure
+		}
+		if err := writeSecretList(c.stdout, secrets, *output); err != nil {
+			_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+			return exitUsage
+		}
+	case action == "set" && (len(rest) == 1 || len(rest) == 2):
+		value, err := c.secretValue(rest[1:])
+		if err != nil {
+			_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+			return exitFailure
+		}
+		if err := c.httpService.SaveSecret(rest[0], value); err != nil {
+			_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+			return exitFailure
+		}
+		_, _ = fmt.Fprintf(c.stdout, "Saved secret %s, reference it as %s\n", rest[0], domain.SecretRef(rest[0]))
+	case action == "rm" && len(rest) == 1:
+		if err := c.httpService.DeleteSecret(rest[0]); err != nil {
+			_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+			return exitFailure
+		}
+		_, _ = fmt.Fprintf(c.stdout, "Deleted secret %s\n", rest[0])
+	default:
+		_, _ = fmt.Fprintln(c.stderr, "usage: burrow secret set <name> [value] | list | rm <name>")
+		return exitUsage
+	}
+
+	return exitOK
+}
+
+// secretValue takes the value from the arguments, or from stdin so it does
+// not end up in the shell history.
+func (c *CLI) secretValue(args []string) (string, error) {
+	if len(args) == 1 {
+		return args[0], nil
+	}
+
+	data, err := io.ReadAll(c.stdin)
+	if err != nil {
+		return "", err
+	}
+	value := strings.TrimRight(string(data), "\r\n")
+	if value == "" {
+		return "", errors.New("empty secret value")
+	}
+	return value, nil
+}
+
+func (c *CLI) suiteRequests(names []string, collection string) ([]*domain.Request, error) {
+	if len(names) == 0 {
+		reqs, err := c.httpService.GetSavedRequests()
+		if err != nil {
+			return nil, err
+		}
+		// saved requests are listed newest first, run them in the order they were created
+		slices.Reverse(reqs)
+		if collection != "" {
+			return c.collectionRequests(reqs, collection)
+		}
+		return reqs, nil
+	}
+
+	reqs := make([]*domain.Request, 0, len(names))
+	for _, name := range names {
+		req, err := c.httpService.GetRequest(strings.ToLower(name))
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
+
+// collectionRequests keeps the requests filed under the collection at path,
+// including those in its sub collections.
+func (c *CLI) collectionRequests(reqs []*domain.Request, path string) ([]*domain.Request, error) {
+	collections, err := c.httpService.GetCollections()
+	if err != nil {
+		return nil, err
+	}
+
+	node := domain.BuildCollectionTree(collections, reqs).Find(path)
+	if node == nil {
+		return nil, fmt.Errorf("collection %q not found", path)
+	}
+	return node.AllRequests(), nil
+}
+
+func (c *CLI) send(ctx context.Context, req *domain.Request, output string) int {
+	if req.IsGRPC() {
+		return c.grpc(ctx, req, output)
+	}
+	if req.IsWebSocket() {
+		return c.websocket(ctx, req, output)
+	}
+	if req.IsStream() {
+		return c.stream(ctx, req, output)
+	}
+
+	resp, err := c.httpService.SendRequest(ctx, req)
+	if err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+	defer func() { _ = resp.RemoveBodyFile() }()
+
+	results := domain.EvaluateAssertions(req.Assertions, resp)
+
+	switch output {
+	case outputJSON:
+		err = writeJSON(c.stdout, struct {
+			*domain.Response
+			Assertions []domain.AssertionResult `json:"assertions,omitempty"`
+		}{resp, results})
+	case outputText:
+		_, err = fmt.Fprint(c.stdout, responseText(resp))
+		if err == nil && len(results) > 0 {
+			_, err = fmt.Fprintf(c.stdout, "\nAssertions:\n%s", assertionsText(results))
+		}
+	default:
+		err = fmt.Errorf("unknown output format %q", output)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+
+	return exitStatus(resp, results)
+}
+
+// stream prints events as they arrive, one JSON object per line with json
+// output, until the server ends the stream or the command is interrupted.
+func (c *CLI) stream(ctx context.Context, req *domain.Request, output string) int {
+	var writeEvent func(domain.Event) error
+	switch output {
+	case outputJSON:
+		encoder := json.NewEncoder(c.stdout)
+		writeEvent = func(event domain.Event) error { return encoder.Encode(event) }
+	case outputText:
+		writeEvent = func(event domain.Event) error {
+			_, err := fmt.Fprint(c.stdout, eventText(event))
+			return err
+		}
+	default:
+		_, _ = fmt.Fprintf(c.stderr, "Error: unknown output format %q\n", output)
+		return exitUsage
+	}
+
+	resp, err := c.httpService.StreamRequest(ctx, req, func(event domain.Event) {
+		if err := writeEvent(event); err != nil {
+			_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		}
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+
+	results := domain.EvaluateAssertions(req.Assertions, resp)
+	if output == outputText {
+		_, _ = fmt.Fprint(c.stdout, streamSummaryText(resp))
+		if len(results) > 0 {
+			_, _ = fmt.Fprintf(c.stdout, "\nAssertions:\n%s", assertionsText(results))
+		}
+	}
+	return exitStatus(resp, results)
+}
+
+// websocket connects, sends the request's messages and prints every frame
+// until the server closes the connection or the command is interrupted.
+func (c *CLI) websocket(ctx context.Context, req *domain.Request, output string) int {
+	var writeFrame func(domain.WSFrame) error
+	switch output {
+	case outputJSON:
+		encoder := json.NewEncoder(c.stdout)
+		writeFrame = func(frame domain.WSFrame) error { return encoder.Encode(newFrameJSON(frame)) }
+	case outputText:
+		writeFrame = func(frame domain.WSFrame) error {
+			_, err := fmt.Fprint(c.stdout, frameText(frame))
+			return err
+		}
+	default:
+		_, _ = fmt.Fprintf(c.stderr, "Error: unknown output format %q\n", output)
+		return exitUsage
+	}
+
+	var writeErr error
+	var closeCode int
+	_, err := c.httpService.ConnectWebSocket(ctx, req, nil, func(frame domain.WSFrame) {
+		if frame.Type == domain.FrameClose && !frame.Sent {
+			closeCode = frame.Code
+		}
+		if err := writeFrame(frame); err != nil && writeErr == nil {
+			writeErr = err
+			_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		}
+	})
+	if err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+	if writeErr != nil {
+		return exitFailure
+	}
+	// only a normal closure or the server going away ends the session cleanly
+	if closeCode != 0 && closeCode != 1000 && closeCode != 1001 {
+		_, _ = fmt.Fprintf(c.stderr, "Error: connection closed with %d %s\n", closeCode, domain.CloseCodeText(closeCode))
+		return exitFailure
+	}
+	return exitOK
+}
+
+// grpc calls the method in the URL of req, printing each response message
+// as it arrives with text output, or lists the methods when there is none.
+func (c *CLI) grpc(ctx context.Context, req *domain.Request, output string) int {
+	if output != outputText && output != outputJSON {
+		_, _ = fmt.Fprintf(c.stderr, "Error: unknown output format %q\n", output)
+		return exitUsage
+	}
+	target, err := req.GRPCTarget()
+	if err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+
+	if target.Method == "" {
+		methods, err := c.httpService.ListGRPCMethods(ctx, req)
+		if err == nil {
+			err = writeGRPCMethods(c.stdout, methods, output)
+		}
+		if err != nil {
+			_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+			return exitFailure
+		}
+		return exitOK
+	}
+
+	var onMessage func(domain.Event)
+	if output == outputText {
+		onMessage = func(event domain.Event) {
+			_, _ = fmt.Fprint(c.stdout, eventText(event))
+		}
+	}
+	resp, err := c.httpService.CallGRPC(ctx, req, onMessage)
+	if err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return exitFailure
+	}
+
+	results := domain.EvaluateAssertions(req.Assertions, resp)
+	if output == outputJSON {
+		err = writeJSON(c.stdout, struct {
+			*domain.Response
+			Assertions []domain.AssertionResult `json:"assertions,omitempty"`
+		}{resp, results})
+	} else {
+		_, err = fmt.Fprint(c.stdout, grpcSummaryText(resp))
+		if err == nil && len(results) > 0 {
+			_, err = fmt.Fprintf(c.stdout, "\nAssertions:\n%s", assertionsText(results))
+		}
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(c.stderr, "Error: %v\n", err)
+		return exitUsage
+	}
+	return exitStatus(resp, results)
+}
+
+func exitStatus(resp *domain.Response, results []domain.AssertionResult) int {
+	if len(results) > 0 {
+		if !domain.AssertionsPassed(results) {
+			return exitFailure
+		}
+		return exitOK
+	}
+
+	if !resp.Success() {
+		return exitFailure
+	}
+	return exitOK
+}
+
+func (c *CLI) activateEnvironment(name string) error {
+	if name == "" {
+		return nil
+	}
+	return c.httpService.SetActiveEnvironment(strings.ToLower(name))
+}
+
+func (c *CLI) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = c.usage
+	return fs
+}
+
+func (c *CLI) usage() {
+	_, _ = fmt.Fprint(c.stderr, usageText)
+}
+
+func outputFlag(fs *flag.FlagSet) *string {
+	output := fs.String("output", "text", "output format")
+	fs.StringVar(output, "o", "text", "output format")
+	return output
+}
+
+// parseInterspersed parses flags that appear before or after positional
+// arguments, so `burrow run name --env dev` works like `burrow run --env dev name`.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func addPairs(target map[string]string, pairs []string) error {
+	for _, pair := range pairs {
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return errors.New(pair)
+		}
+		target[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return nil
+}
+
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
@@ -0,0 +1,637 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/ManoloEsS/burrow/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeHttpService struct {
+	service.HttpClientService
+	saved       map[string]*domain.Request
+	response    *domain.Response
+	sendErr     error
+	sent        *domain.Request
+	events      []domain.Event
+	frames      []domain.WSFrame
+	methods     []domain.GRPCMethod
+	activeEnv   string
+	collections []*domain.Collection
+	secrets     map[string]string
+}
+
+func (f *fakeHttpService) SendRequest(_ context.Context, req *domain.Request) (*domain.Response, error) {
+	f.sent = req
+	if f.sendErr != nil {
+		return &domain.Response{}, f.sendErr
+	}
+	return f.response, nil
+}
+
+func (f *fakeHttpService) StreamRequest(_ context.Context, req *domain.Request, onEvent func(domain.Event)) (*domain.Response, error) {
+	f.sent = req
+	if f.sendErr != nil {
+		return &domain.Response{}, f.sendErr
+	}
+	for _, event := range f.events {
+		onEvent(event)
+	}
+	return f.response, nil
+}
+
+func (f *fakeHttpService) ConnectWebSocket(_ context.Context, req *domain.Request, _ <-chan domain.WSMessage, onFrame func(domain.WSFrame)) (*domain.Response, error) {
+	f.sent = req
+	if f.sendErr != nil {
+		return &domain.Response{}, f.sendErr
+	}
+	for _, frame := range f.frames {
+		onFrame(frame)
+	}
+	return f.response, nil
+}
+
+func (f *fakeHttpService) ListGRPCMethods(_ context.Context, req *domain.Request) ([]domain.GRPCMethod, error) {
+	f.sent = req
+	return f.methods, f.sendErr
+}
+
+func (f *fakeHttpService) CallGRPC(_ context.Context, req *domain.Request, onMessage func(domain.Event)) (*domain.Response, error) {
+	f.sent = req
+	if f.sendErr != nil {
+		return &domain.Response{}, f.sendErr
+	}
+	for _, event := range f.events {
+		if onMessage != nil {
+			onMessage(event)
+		}
+	}
+	return f.response, nil
+}
+
+func (f *fakeHttpService) GetSavedRequests() ([]*domain.Request, error) {
+	var reqs []*domain.Request
+	for _, req := range f.saved {
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
+
+func (f *fakeHttpService) GetRequest(name string) (*domain.Request, error) {
+	req, ok := f.saved[name]
+	if !ok {
+		return nil, errors.New("request not found")
+	}
+	return req, nil
+}
+
+func (f *fakeHttpService) SaveRequest(req *domain.Request) error {
+	f.saved[req.Name] = req
+	return nil
+}
+
+func (f *fakeHttpService) GetCollections() ([]*domain.Collection, error) {
+	return f.collections, nil
+}
+
+func (f *fakeHttpService) SetActiveEnvironment(name string) error {
+	f.activeEnv = name
+	return nil
+}
+
+func (f *fakeHttpService) SaveSecret(name, value string) error {
+	f.secrets[name] = value
+	return nil
+}
+
+func (f *fakeHttpService) DeleteSecret(name string) error {
+	if _, ok := f.secrets[name]; !ok {
+		return errors.New("secret not found")
+	}
+	delete(f.secrets, name)
+	return nil
+}
+
+func (f *fakeHttpService) GetSecrets() ([]*domain.Secret, error) {
+	secrets := []*domain.Secret{}
+	for name := range f.secrets {
+		secrets = append(secrets, &domain.Secret{Name: name, Source: domain.SecretSourceDatabase})
+	}
+	return secrets, nil
+}
+
+func newTestCLI(fake *fakeHttpService) (*CLI, *bytes.Buffer, *bytes.Buffer) {
+	cfg := &config.Config{App: config.AppConfig{DefaultPort: "8080"}}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	return New(cfg, fake, stdout, stderr), stdout, stderr
+}
+
+func TestRunExitCodes(t *testing.T) {
+	saved := map[string]*domain.Request{
+		"health": {Name: "health", Method: "GET", URL: "http://localhost:8080/health"},
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		response     *domain.Response
+		sendErr      error
+		expectedCode int
+	}{
+		{
+			name:         "Successful saved request",
+			args:         []string{"run", "health"},
+			response:     &domain.Response{Status: "200 OK", StatusCode: 200},
+			expectedCode: exitOK,
+		},
+		{
+			name:         "Non-2xx saved request",
+			args:         []string{"run", "health"},
+			response:     &domain.Response{Status: "500 Internal Server Error", StatusCode: 500},
+			expectedCode: exitFailure,
+		},
+		{
+			name:         "Transport error",
+			args:         []string{"run", "health"},
+			sendErr:      errors.New("connection refused"),
+			expectedCode: exitFailure,
+		},
+		{
+			name:         "Unknown saved request",
+			args:         []string{"run", "missing"},
+			expectedCode: exitFailure,
+		},
+		{
+			name:         "Missing request name",
+			args:         []string{"run"},
+			expectedCode: exitUsage,
+		},
+		{
+			name:         "Unknown command",
+			args:         []string{"fly"},
+			expectedCode: exitUsage,
+		},
+		{
+			name:         "Unknown output format",
+			args:         []string{"run", "health", "-o", "xml"},
+			response:     &domain.Response{Status: "200 OK", StatusCode: 200},
+			expectedCode: exitUsage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeHttpService{saved: saved, response: tt.response, sendErr: tt.sendErr}
+			c, _, _ := newTestCLI(fake)
+
+			assert.Equal(t, tt.expectedCode, c.Run(context.Background(), tt.args))
+		})
+	}
+}
+
+func TestRunSendBuildsRequest(t *testing.T) {
+	fake := &fakeHttpService{response: &domain.Response{Status: "201 Created", StatusCode: 201, Body: "created"}}
+	c, stdout, _ := newTestCLI(fake)
+
+	code := c.Run(context.Background(), []string{
+		"send", "-X", "post", "/users",
+		"-H", "X-Token: abc",
+		"-p", "page:2",
+		"-d", `{"name":"burrow"}`, "-t", "JSON",
+		"--env", "Dev",
+	})
+
+	assert.Equal(t, exitOK, code)
+	require.NotNil(t, fake.sent)
+	assert.Equal(t, "POST", fake.sent.Method)
+	assert.Equal(t, "http://localhost:8080/users", fake.sent.URL)
+	assert.Equal(t, "abc", fake.sent.Headers["X-Token"])
+	assert.Equal(t, "2", fake.sent.Params["page"])
+	assert.Equal(t, `{"name":"burrow"}`, fake.sent.Body)
+	assert.Equal(t, "dev", fake.activeEnv)
+	assert.Contains(t, stdout.String(), "Status: 201 Created")
+	assert.Contains(t, stdout.String(), "created")
+}
+
+func TestRunSendForm(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		contentType  string
+		expectedCode int
+	}{
+		{name: "Multipart by default", args: []string{"-F", "name:burrow", "-F", "avatar:@me.png"}, contentType: domain.ContentTypeMultipart, expectedCode: exitOK},
+		{name: "URL encoded form", args: []string{"-t", "Form", "-F", "name:burrow", "-F", "avatar:@me.png"}, contentType: domain.ContentTypeForm, expectedCode: exitOK},
+		{name: "Combined with data", args: []string{"-F", "name:burrow", "-d", "raw"}, expectedCode: exitUsage},
+		{name: "Invalid field", args: []string{"-F", "name=burrow"}, expectedCode: exitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeHttpService{response: &domain.Response{Status: "200 OK", StatusCode: 200}}
+			c, _, _ := newTestCLI(fake)
+
+			code := c.Run(context.Background(), append([]string{"send", "-X", "POST", "/upload"}, tt.args...))
+			assert.Equal(t, tt.expectedCode, code)
+			if tt.expectedCode != exitOK {
+				return
+			}
+			require.NotNil(t, fake.sent)
+			assert.Equal(t, tt.contentType, fake.sent.ContentType["Content-Type"])
+			assert.Equal(t, []domain.FormField{
+				{Key: "name", Value: "burrow"},
+				{Key: "avatar", Value: "me.png", File: true},
+			}, fake.sent.Form)
+		})
+	}
+}
+
+func TestRunSendStream(t *testing.T) {
+	received := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	fake := &fakeHttpService{
+		response: &domain.Response{
+			Status:      "200 OK",
+			StatusCode:  200,
+			ContentType: domain.ContentTypeEventStream,
+			Stream:      &domain.StreamStats{Events: 2, Duration: time.Second},
+		},
+		events: []domain.Event{
+			{ID: "1", Type: "tick", Data: "first", ReceivedAt: received},
+			{Type: "message", Data: "second", ReceivedAt: received},
+		},
+	}
+	c, stdout, _ := newTestCLI(fake)
+
+	code := c.Run(context.Background(), []string{"send", "--stream", "/events"})
+	assert.Equal(t, exitOK, code)
+	assert.True(t, fake.sent.IsStream())
+	assert.Contains(t, stdout.String(), "15:04:05.000 tick #1\nfirst\n\n15:04:05.000 message\nsecond\n")
+	assert.Contains(t, stdout.String(), "Stream: 2 events, 0 B in 1s (2.0/s)")
+
+	stdout.Reset()
+	code = c.Run(context.Background(), []string{"send", "-o", "json", "-H", "Accept:text/event-stream", "/events"})
+	assert.Equal(t, exitOK, code)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.JSONEq(t, `{"id":"1","type":"tick","data":"first","received_at":"2026-01-02T15:04:05Z"}`, lines[0])
+}
+
+func TestRunSendWebSocket(t *testing.T) {
+	at := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	fake := &fakeHttpService{
+		response: &domain.Response{Status: "101 Switching Protocols", StatusCode: 101},
+		frames: []domain.WSFrame{
+			{Sent: true, Type: domain.FrameText, Data: []byte(`{"op":"sub"}`), Time: at},
+			{Type: domain.FrameBinary, Data: []byte{0xff}, Time: at},
+			{Type: domain.FrameClose, Code: 1000, Time: at},
+		},
+	}
+	c, stdout, _ := newTestCLI(fake)
+
+	code := c.Run(context.Background(), []string{"send", "-t", "JSON", "-d", `{"op":"sub"}`, "ws://localhost:9000/feed"})
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, []domain.WSMessage{{Text: `{"op":"sub"}`}}, fake.sent.Messages)
+	assert.Equal(t, "15:04:05.000 -> text 12 B\n{\"op\":\"sub\"}\n"+
+		"15:04:05.000 <- binary 1 B\n00000000  ff                                                |.|\n"+
+		"15:04:05.000 <- close 1000 normal closure\n", stdout.String())
+
+	stdout.Reset()
+	code = c.Run(context.Background(), []string{"send", "-X", "WS", "-o", "json", "/feed"})
+	assert.Equal(t, exitOK, code)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.JSONEq(t, `{"direction":"sent","type":"text","text":"{\"op\":\"sub\"}","time":"2026-01-02T15:04:05Z"}`, lines[0])
+	assert.JSONEq(t, `{"direction":"received","type":"binary","binary":"/w==","time":"2026-01-02T15:04:05Z"}`, lines[1])
+
+	fake.sendErr = errors.New("websocket handshake refused: 404 Not Found")
+	code = c.Run(context.Background(), []string{"send", "ws://localhost:9000/feed"})
+	assert.Equal(t, exitFailure, code)
+}
+
+func TestRunSendWebSocketAbnormalClose(t *testing.T) {
+	at := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	fake := &fakeHttpService{
+		response: &domain.Response{Status: "101 Switching Protocols", StatusCode: 101},
+		frames: []domain.WSFrame{
+			{Type: domain.FrameText, Data: []byte("hello"), Time: at},
+			{Type: domain.FrameClose, Code: 1006, Time: at},
+		},
+	}
+	c, stdout, stderr := newTestCLI(fake)
+
+	code := c.Run(context.Background(), []string{"send", "ws://localhost:9000/feed"})
+	assert.Equal(t, exitFailure, code)
+	assert.Contains(t, stdout.String(), "<- close 1006 abnormal closure")
+	assert.Contains(t, stderr.String(), "connection closed with 1006 abnormal closure")
+
+	fake.frames[1].Code = 1001
+	stderr.Reset()
+	code = c.Run(context.Background(), []string{"send", "ws://localhost:9000/feed"})
+	assert.Equal(t, exitOK, code)
+	assert.Empty(t, stderr.String())
+}
+
+// failingWriter fails every write, like stdout piped to a closed reader.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestRunSendWebSocketWriteError(t *testing.T) {
+	fake := &fakeHttpService{
+		response: &domain.Response{Status: "101 Switching Protocols", StatusCode: 101},
+		frames: []domain.WSFrame{
+			{Type: domain.FrameText, Data: []byte("hello")},
+			{Type: domain.FrameText, Data: []byte("again")},
+			{Type: domain.FrameClose, Code: 1000},
+		},
+	}
+	stderr := &bytes.Buffer{}
+	c := New(&config.Config{App: config.AppConfig{DefaultPort: "8080"}}, fake, failingWriter{}, stderr)
+
+	code := c.Run(context.Background(), []string{"send", "ws://localhost:9000/feed"})
+	assert.Equal(t, exitFailure, code)
+	assert.Equal(t, "Error: broken pipe\n", stderr.String())
+}
+
+func TestRunSendGRPC(t *testing.T) {
+	at := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	fake := &fakeHttpService{
+		methods: []domain.GRPCMethod{
+			{Service: "shop.Orders", Name: "Get"},
+			{Service: "shop.Orders", Name: "Follow", ServerStreaming: true},
+		},
+		response: &domain.Response{
+			Status:     "5 NotFound",
+			StatusCode: 5,
+			Headers:    http.Header{"X-Served-By": {"orders-1"}},
+			GRPC:       &domain.GRPCStatus{Message: "no order 7", Trailers: http.Header{"X-Trace": {"abc"}}},
+		},
+		events: []domain.Event{{Type: "shop.Order", Data: `{"id": "7"}`, ReceivedAt: at}},
+	}
+	c, stdout, _ := newTestCLI(fake)
+
+	code := c.Run(context.Background(), []string{"send", "--proto", "orders.proto", "grpc://localhost:50051"})
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "orders.proto", fake.sent.Options.ProtoFiles)
+	assert.Equal(t, "shop.Orders/Get     unary\nshop.Orders/Follow  server streaming\n", stdout.String())
+
+	stdout.Reset()
+	code = c.Run(context.Background(), []string{"send", "-X", "GRPC", "-t", "JSON", "-d", `{"id":"7"}`, "localhost:50051/shop.Orders/Get"})
+	assert.Equal(t, exitFailure, code)
+	assert.Equal(t, `{"id":"7"}`, fake.sent.Body)
+	assert.Equal(t, "15:04:05.000 shop.Order\n{\"id\": \"7\"}\n\n"+
+		"Status: 5 NotFound\nMessage: no order 7\nResponse time: 0s\n"+
+		"Headers:\n  X-Served-By: orders-1\nTrailers:\n  X-Trace: abc\n", stdout.String())
+
+	stdout.Reset()
+	code = c.Run(context.Background(), []string{"send", "-o", "json", "-a", "status == 5", "grpc://localhost:50051/shop.Orders/Get"})
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout.String(), `"message": "no order 7"`)
+
+	code = c.Run(context.Background(), []string{"send", "grpc://localhost:50051/Get"})
+	assert.Equal(t, exitUsage, code)
+}
+
+func TestRunSendGraphQL(t *testing.T) {
+	fake := &fakeHttpService{
+		response: &domain.Response{
+			Status:     "200 OK",
+			StatusCode: 200,
+			GraphQL: &domain.GraphQLResult{
+				Data:   []byte(`{"user":null}`),
+				Errors: []domain.GraphQLError{{Message: "user not found", Path: []any{"user"}}},
+			},
+		},
+	}
+	c, stdout, stderr := newTestCLI(fake)
+
+	code := c.Run(context.Background(), []string{"send", "-X", "POST", "-t", "GraphQL", "-d", "query($id: ID) { user(id: $id) { name } }", "--variables", `{"id": 2}`, "localhost:8080/graphql"})
+	assert.Equal(t, exitFailure, code)
+	assert.Equal(t, `{"id": 2}`, fake.sent.GraphQL.Variables)
+	assert.Equal(t, "application/json", fake.sent.ContentType["Content-Type"])
+	assert.Contains(t, stdout.String(), "Errors (1):\n  user not found at user\n\nData:\n{\n  \"user\": null\n}\n")
+
+	code = c.Run(context.Background(), []string{"send", "--variables", `{"id": 2}`, "localhost:8080/graphql"})
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr.String(), "--variables requires --type GraphQL")
+
+	code = c.Run(context.Background(), []string{"send", "-t", "GraphQL", "-d", "{ me { id } }", "--variables", "[]", "localhost:8080/graphql"})
+	assert.Equal(t, exitUsage, code)
+}
+
+func TestRunJSONOutput(t *testing.T) {
+	fake := &fakeHttpService{
+		saved:    map[string]*domain.Request{"health": {Name: "health", Method: "GET"}},
+		response: &domain.Response{Status: "200 OK", StatusCode: 200, Body: "ok"},
+	}
+	c, stdout, _ := newTestCLI(fake)
+
+	code := c.Run(context.Background(), []string{"run", "health", "--output", "json"})
+	assert.Equal(t, exitOK, code)
+
+	var resp domain.Response
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &resp))
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "ok", resp.Body)
+}
+
+func TestRunList(t *testing.T) {
+	fake := &fakeHttpService{
+		saved: map[string]*domain.Request{
+			"health": {Name: "health", Method: "GET", URL: "http://localhost:8080/health"},
+		},
+	}
+	c, stdout, _ := newTestCLI(fake)
+
+	code := c.Run(context.Background(), []string{"list"})
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stdout.String(), "health")
+	assert.Contains(t, stdout.String(), "http://localhost:8080/health")
+}
+
+func TestRunListCollection(t *testing.T) {
+	fake := &fakeHttpService{
+		saved: map[string]*domain.Request{
+			"health": {Name: "health", Method: "GET", URL: "http://localhost:8080/health"},
+			"charge": {Name: "charge", Method: "POST", URL: "http://localhost:8080/charges", CollectionID: 2},
+		},
+		collections: []*domain.Collection{{ID: 1, Name: "payments"}, {ID: 2, Name: "cards", ParentID: 1}},
+	}
+	c, stdout, stderr := newTestCLI(fake)
+
+	assert.Equal(t, exitOK, c.Run(context.Background(), []string{"list", "--collection", "payments"}))
+	assert.Contains(t, stdout.String(), "charge")
+	assert.NotContains(t, stdout.String(), "health")
+
+	assert.Equal(t, exitFailure, c.Run(context.Background(), []string{"list", "--collection", "payments/missing"}))
+	assert.Contains(t, stderr.String(), `collection "payments/missing" not found`)
+}
+
+func TestParseInterspersed(t *testing.T) {
+	c, _, _ := newTestCLI(&fakeHttpService{})
+	fs := c.newFlagSet("test")
+	env := fs.String("env", "", "")
+
+	positional, err := parseInterspersed(fs, []string{"first", "--env", "dev", "second"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, positional)
+	assert.Equal(t, "dev", *env)
+}
+
+func TestRunSendAssertions(t *testing.T) {
+	tests := []struct {
+		name         string
+		assertion    string
+		expectedCode int
+	}{
+		{name: "Passing assertion on 404", assertion: "status == 404", expectedCode: exitOK},
+		{name: "Failing assertion", assertion: "status == 200", expectedCode: exitFailure},
+		{name: "Invalid assertion", assertion: "status is 200", expectedCode: exitUsage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeHttpService{response: &domain.Response{Status: "404 Not Found", StatusCode: 404}}
+			c, stdout, _ := newTestCLI(fake)
+
+			assert.Equal(t, tt.expectedCode, c.Run(context.Background(), []string{"send", "/missing", "-a", tt.assertion}))
+			if tt.expectedCode != exitUsage {
+				assert.Contains(t, stdout.String(), "Assertions:")
+			}
+		})
+	}
+}
+
+func TestRunTest(t *testing.T) {
+	fake := &fakeHttpService{
+		saved: map[string]*domain.Request{
+			"health": {
+				Name:       "health",
+				Method:     "GET",
+				Assertions: []domain.Assertion{{Kind: domain.AssertStatus, Operator: domain.OpEquals, Expected: "200"}},
+			},
+		},
+		response: &domain.Response{Status: "200 OK", StatusCode: 200},
+	}
+	c, stdout, _ := newTestCLI(fake)
+
+	assert.Equal(t, exitOK, c.Run(context.Background(), []string{"test", "-o", "tap"}))
+	assert.Contains(t, stdout.String(), "ok 1 - health")
+
+	fake.response = &domain.Response{Status: "503 Service Unavailable", StatusCode: 503}
+	assert.Equal(t, exitFailure, c.Run(context.Background(), []string{"test", "health"}))
+}
+
+func TestRunTestCollection(t *testing.T) {
+	fake := &fakeHttpService{
+		saved: map[string]*domain.Request{
+			"health": {Name: "health", Method: "GET"},
+			"charge": {Name: "charge", Method: "POST", CollectionID: 1},
+		},
+		collections: []*domain.Collection{{ID: 1, Name: "payments"}},
+		response:    &domain.Response{Status: "200 OK", StatusCode: 200},
+	}
+	c, stdout, _ := newTestCLI(fake)
+
+	assert.Equal(t, exitOK, c.Run(context.Background(), []string{"test", "--collection", "payments", "-o", "tap"}))
+	assert.Contains(t, stdout.String(), "1..1")
+	assert.Contains(t, stdout.String(), "ok 1 - charge")
+
+	assert.Equal(t, exitUsage, c.Run(context.Background(), []string{"test", "--collection", "payments", "health"}))
+}
+
+func TestRunImport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	spec := "openapi: 3.0.0\npaths:\n  /health:\n    get:\n      operationId: health\n  /users:\n    get:\n      operationId: users\n"
+	require.NoError(t, os.WriteFile(path, []byte(spec), 0o644))
+
+	fake := &fakeHttpService{saved: map[string]*domain.Request{"health": {Name: "health"}}}
+	c, stdout, _ := newTestCLI(fake)
+
+	assert.Equal(t, exitOK, c.Run(context.Background(), []string{"import", path}))
+	assert.Contains(t, fake.saved, "users")
+	assert.Contains(t, stdout.String(), "Imported 1 requests from openapi, skipped 1")
+	assert.Contains(t, stdout.String(), "skipped health: a saved request with this name already exists")
+
+	assert.Equal(t, exitFailure, c.Run(context.Background(), []string{"import", filepath.Join(t.TempDir(), "missing.json")}))
+	assert.Equal(t, exitUsage, c.Run(context.Background(), []string{"import"}))
+}
+
+func TestRunImportDryRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"openapi": "3.0.0", "paths": {"/health": {"get": {}}}}`), 0o644))
+
+	fake := &fakeHttpService{saved: map[string]*domain.Request{}}
+	c, stdout, _ := newTestCLI(fake)
+
+	assert.Equal(t, exitOK, c.Run(context.Background(), []string{"import", "--dry-run", path}))
+	assert.Empty(t, fake.saved)
+	assert.Contains(t, stdout.String(), "Would import 1 requests from openapi")
+}
+
+func TestRunTestInterrupted(t *testing.T) {
+	fake := &fakeHttpService{
+		saved:    map[string]*domain.Request{"health": {Name: "health", Method: "GET"}},
+		response: &domain.Response{Status: "200 OK", StatusCode: 200},
+	}
+	c, _, stderr := newTestCLI(fake)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Equal(t, exitFailure, c.Run(ctx, []string{"test"}))
+	assert.Nil(t, fake.sent)
+	assert.Contains(t, stderr.String(), "Interrupted after 0 of 1 requests")
+}
+
+func TestRunSecret(t *testing.T) {
+	fake := &fakeHttpService{secrets: map[string]string{}}
+	c, stdout, _ := newTestCLI(fake)
+
+	c.stdin = strings.NewReader("s3cret\n")
+	assert.Equal(t, exitOK, c.Run(context.Background(), []string{"secret", "set", "token"}))
+	assert.Equal(t, "s3cret", fake.secrets["token"])
+	assert.Contains(t, stdout.String(), "{{secret.token}}")
+
+	assert.Equal(t, exitOK, c.Run(context.Background(), []string{"secret", "set", "key", "abc"}))
+	assert.Equal(t, "abc", fake.secrets["key"])
+
+	stdout.Reset()
+	assert.Equal(t, exitOK, c.Run(context.Background(), []string{"secret", "list", "-o", "json"}))
+	assert.NotContains(t, stdout.String(), "s3cret")
+	assert.Contains(t, stdout.String(), `"source": "database"`)
+
+	assert.Equal(t, exitOK, c.Run(context.Background(), []string{"secret", "rm", "token"}))
+	assert.NotContains(t, fake.secrets, "token")
+	assert.Equal(t, exitFailure, c.Run(context.Background(), []string{"secret", "rm", "token"}))
+
+	c.stdin = strings.NewReader("")
+	assert.Equal(t, exitFailure, c.Run(context.Background(), []string{"secret", "set", "empty"}))
+	assert.Equal(t, exitUsage, c.Run(context.Background(), []string{"secret"}))
+	assert.Equal(t, exitUsage, c.Run(context.Background(), []string{"secret", "rm"}))
+}
+
+func TestResponseTextLargeAndBinaryBodies(t *testing.T) {
+	binary := responseText(&domain.Response{Status: "200 OK", Body: "\x89PNG", BodySize: 4, Binary: true})
+	assert.Contains(t, binary, "Binary body of 4 B, first 4 bytes shown:")
+	assert.Contains(t, binary, "00000000  89 50 4e 47")
+
+	truncated := responseText(&domain.Response{Status: "200 OK", Body: "partial", BodySize: 3 << 20, Truncated: true})
+	assert.Contains(t, truncated, "partial\n")
+	assert.Contains(t, truncated, "Body truncated to 7 B of 3.0 MB")
+}
@@ -0,0 +1,248 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/ManoloEsS/burrow/internal/importer"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+func writeRequestList(w io.Writer, reqs []*domain.Request, format string) error {
+	switch format {
+	case outputJSON:
+		if reqs == nil {
+			reqs = []*domain.Request{}
+		}
+		return writeJSON(w, reqs)
+	case outputText:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, req := range reqs {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", req.Name, req.Method, req.URL)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+func writeSecretList(w io.Writer, secrets []*domain.Secret, format string) error {
+	switch format {
+	case outputJSON:
+		return writeJSON(w, secrets)
+	case outputText:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, secret := range secrets {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\n", secret.Name, secret.Source)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func responseText(resp *domain.Response) string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "Status: %s\n", resp.Status)
+	fmt.Fprintf(&builder, "Response time: %s\n", resp.ResponseTime)
+	if resp.Timing != nil {
+		fmt.Fprintf(&builder, "Timing: %s\n", resp.Timing)
+	}
+	fmt.Fprintf(&builder, "Content-Type: %s\n", resp.ContentType)
+	fmt.Fprintf(&builder, "Content-Length: %d\n\n", resp.ContentLenght)
+
+	switch {
+	case resp.GraphQL != nil:
+		fmt.Fprint(&builder, graphQLResultText(resp.GraphQL))
+	case resp.Binary:
+		fmt.Fprintf(&builder, "Binary body of %s, first %d bytes shown:\n", domain.FormatBytes(resp.BodySize), min(len(resp.Body), domain.HexPreviewSize))
+		fmt.Fprint(&builder, resp.HexPreview())
+	case resp.Body != "":
+		fmt.Fprint(&builder, resp.Body)
+		if !strings.HasSuffix(resp.Body, "\n") {
+			fmt.Fprintln(&builder)
+		}
+	}
+	if resp.Truncated {
+		fmt.Fprintf(&builder, "Body truncated to %s of %s, raise response.max_body_mb to keep more\n", domain.FormatBytes(int64(len(resp.Body))), domain.FormatBytes(resp.BodySize))
+	}
+
+	return builder.String()
+}
+
+// graphQLResultText writes the errors of a GraphQL response ahead of its
+// data.
+func graphQLResultText(result *domain.GraphQLResult) string {
+	var builder strings.Builder
+	if len(result.Errors) > 0 {
+		fmt.Fprintf(&builder, "Errors (%d):\n", len(result.Errors))
+		for _, gqlErr := range result.Errors {
+			fmt.Fprintf(&builder, "  %s\n", gqlErr)
+		}
+		builder.WriteString("\n")
+	}
+	data := result.DataString()
+	if data == "" {
+		data = "null"
+	}
+	fmt.Fprintf(&builder, "Data:\n%s\n", data)
+	return builder.String()
+}
+
+// eventText writes an event as its type and id over its data, chunks of a
+// plain stream are written as they came.
+func eventText(event domain.Event) string {
+	if event.IsChunk() {
+		return event.Data
+	}
+	header := event.Type
+	if event.ID != "" {
+		header += " #" + event.ID
+	}
+	return fmt.Sprintf("%s %s\n%s\n\n", event.ReceivedAt.Format("15:04:05.000"), header, event.Data)
+}
+
+func frameText(frame domain.WSFrame) string {
+	direction := "<-"
+	if frame.Sent {
+		direction = "->"
+	}
+	text := fmt.Sprintf("%s %s %s\n", frame.Time.Format("15:04:05.000"), direction, frame.Label())
+	if payload := frame.Payload(); payload != "" {
+		text += payload + "\n"
+	}
+	return text
+}
+
+// frameJSON keeps text readable in json output, binary data is base64.
+type frameJSON struct {
+	Direction string    `json:"direction"`
+	Type      string    `json:"type"`
+	Text      string    `json:"text,omitempty"`
+	Binary    []byte    `json:"binary,omitempty"`
+	Code      int       `json:"code,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+func newFrameJSON(frame domain.WSFrame) frameJSON {
+	out := frameJSON{Direction: "received", Type: frame.Type, Code: frame.Code, Time: frame.Time}
+	if frame.Sent {
+		out.Direction = "sent"
+	}
+	if frame.Type == domain.FrameBinary {
+		out.Binary = frame.Data
+	} else {
+		out.Text = string(frame.Data)
+	}
+	return out
+}
+
+func streamSummaryText(resp *domain.Response) string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "\nStatus: %s\n", resp.Status)
+	fmt.Fprintf(&builder, "Content-Type: %s\n", resp.ContentType)
+	if resp.Stream != nil {
+		fmt.Fprintf(&builder, "Stream: %d events, %s in %s (%.1f/s)\n",
+			resp.Stream.Events, domain.FormatBytes(resp.BodySize), resp.Stream.Duration.Round(time.Millisecond), resp.Stream.Rate())
+	}
+	return builder.String()
+}
+
+func writeGRPCMethods(w io.Writer, methods []domain.GRPCMethod, format string) error {
+	switch format {
+	case outputJSON:
+		if methods == nil {
+			methods = []domain.GRPCMethod{}
+		}
+		return writeJSON(w, methods)
+	case outputText:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, method := range methods {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\n", method.Path(), method.Kind())
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// grpcSummaryText writes the status of a gRPC call with its metadata, the
+// messages were printed as they arrived.
+func grpcSummaryText(resp *domain.Response) string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "Status: %s\n", resp.Status)
+	if resp.GRPC != nil && resp.GRPC.Message != "" {
+		fmt.Fprintf(&builder, "Message: %s\n", resp.GRPC.Message)
+	}
+	fmt.Fprintf(&builder, "Response time: %s\n", resp.ResponseTime)
+	if resp.Stream != nil {
+		fmt.Fprintf(&builder, "Stream: %d messages, %s in %s (%.1f/s)\n",
+			resp.Stream.Events, domain.FormatBytes(resp.BodySize), resp.Stream.Duration.Round(time.Millisecond), resp.Stream.Rate())
+	}
+	writeMetadata(&builder, "Headers", resp.Headers)
+	if resp.GRPC != nil {
+		writeMetadata(&builder, "Trailers", resp.GRPC.Trailers)
+	}
+	return builder.String()
+}
+
+func writeMetadata(builder *strings.Builder, title string, header http.Header) {
+	if len(header) == 0 {
+		return
+	}
+	fmt.Fprintf(builder, "%s:\n", title)
+	for _, key := range slices.Sorted(maps.Keys(header)) {
+		for _, value := range header[key] {
+			fmt.Fprintf(builder, "  %s: %s\n", key, value)
+		}
+	}
+}
+
+func writeImport(w io.Writer, result *importer.Result, dryRun bool, format string) error {
+	switch format {
+	case outputJSON:
+		return writeJSON(w, result)
+	case outputText:
+		verb := "Imported"
+		if dryRun {
+			verb = "Would import"
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, req := range result.Requests {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", req.Name, req.Method, req.URL)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(w, "\n%s %d requests from %s, skipped %d\n", verb, len(result.Requests), result.Format, len(result.Skipped))
+		for _, skipped := range result.Skipped {
+			_, _ = fmt.Fprintf(w, "  skipped %s: %s\n", skipped.Name, skipped.Reason)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
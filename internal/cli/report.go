@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+)
+
+const (
+	outputJUnit = "junit"
+	outputTAP   = "tap"
+)
+
+func writeSuite(w io.Writer, suite *domain.SuiteResult, format string) error {
+	switch format {
+	case outputText:
+		_, err := fmt.Fprint(w, suiteText(suite))
+		return err
+	case outputJSON:
+		return writeJSON(w, suite)
+	case outputJUnit:
+		return writeJUnit(w, suite)
+	case outputTAP:
+		_, err := fmt.Fprint(w, suiteTAP(suite))
+		return err
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+func suiteText(suite *domain.SuiteResult) string {
+	var builder strings.Builder
+
+	for _, tc := range suite.Cases {
+		status := "PASS"
+		if !tc.Passed() {
+			status = "FAIL"
+		}
+		fmt.Fprintf(&builder, "%s %s (%s)\n", status, tc.Name, tc.Duration.Round(time.Microsecond))
+
+		if tc.Error != "" {
+			fmt.Fprintf(&builder, "    error: %s\n", tc.Error)
+			continue
+		}
+		if len(tc.Results) == 0 {
+			fmt.Fprintf(&builder, "    status %s\n", tc.Response.Status)
+			continue
+		}
+		builder.WriteString(indent(assertionsText(tc.Results), "    "))
+	}
+
+	fmt.Fprintf(&builder, "\n%d passed, %d failed, %d total in %s\n",
+		suite.PassedCount(), suite.FailedCount(), len(suite.Cases), suite.Duration.Round(time.Microsecond))
+
+	return builder.String()
+}
+
+func assertionsText(results []domain.AssertionResult) string {
+	var builder strings.Builder
+	for _, r := range results {
+		if r.Passed {
+			fmt.Fprintf(&builder, "ok   %s\n", r.Assertion)
+		} else {
+			fmt.Fprintf(&builder, "FAIL %s: %s\n", r.Assertion, r.Message)
+		}
+	}
+	return builder.String()
+}
+
+func suiteTAP(suite *domain.SuiteResult) string {
+	var builder strings.Builder
+
+	builder.WriteString("TAP version 13\n")
+	fmt.Fprintf(&builder, "1..%d\n", len(suite.Cases))
+
+	for i, tc := range suite.Cases {
+		if tc.Passed() {
+			fmt.Fprintf(&builder, "ok %d - %s\n", i+1, tc.Name)
+			continue
+		}
+
+		fmt.Fprintf(&builder, "not ok %d - %s\n", i+1, tc.Name)
+		builder.WriteString("  ---\n")
+		if tc.Error != "" {
+			fmt.Fprintf(&builder, "  message: %q\n", tc.Error)
+		} else {
+			fmt.Fprintf(&builder, "  message: %q\n", failureMessage(tc))
+		}
+		builder.WriteString("  ...\n")
+	}
+
+	return builder.String()
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+func writeJUnit(w io.Writer, suite *domain.SuiteResult) error {
+	junitSuite := junitTestSuite{
+		Name:  suite.Name,
+		Tests: len(suite.Cases),
+		Time:  fmt.Sprintf("%.3f", suite.Duration.Seconds()),
+	}
+
+	for _, tc := range suite.Cases {
+		testCase := junitTestCase{
+			Name:      tc.Name,
+			ClassName: "burrow." + suite.Name,
+			Time:      fmt.Sprintf("%.3f", tc.Duration.Seconds()),
+		}
+
+		switch {
+		case tc.Error != "":
+			junitSuite.Errors++
+			testCase.Error = &junitProblem{Message: tc.Error, Type: "transport"}
+		case !tc.Passed():
+			junitSuite.Failures++
+			testCase.Failure = &junitProblem{
+				Message: failureMessage(tc),
+				Type:    "assertion",
+				Body:    assertionsText(tc.Results),
+			}
+		}
+
+		junitSuite.Cases = append(junitSuite.Cases, testCase)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitTestSuites{Suites: []junitTestSuite{junitSuite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func failureMessage(tc *domain.TestCase) string {
+	if len(tc.Results) == 0 {
+		return fmt.Sprintf("unexpected status %s", tc.Response.Status)
+	}
+
+	failed := 0
+	for _, r := range tc.Results {
+		if !r.Passed {
+			failed++
+		}
+	}
+	return fmt.Sprintf("%d of %d assertions failed", failed, len(tc.Results))
+}
+
+func indent(text, prefix string) string {
+	var builder strings.Builder
+	for line := range strings.Lines(text) {
+		builder.WriteString(prefix + line)
+	}
+	return builder.String()
+}
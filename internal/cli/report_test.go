@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSuite() *domain.SuiteResult {
+	return &domain.SuiteResult{
+		Name: "smoke",
+		Cases: []*domain.TestCase{
+			{
+				Name:     "health",
+				Response: &domain.Response{Status: "200 OK", StatusCode: 200},
+				Results: []domain.AssertionResult{
+					{Assertion: domain.Assertion{Kind: "status", Operator: "==", Expected: "200"}, Passed: true},
+				},
+			},
+			{
+				Name:     "users",
+				Response: &domain.Response{Status: "200 OK", StatusCode: 200},
+				Results: []domain.AssertionResult{
+					{Assertion: domain.Assertion{Kind: "json", Target: "$.id", Operator: "==", Expected: "1"}, Message: "expected == 1, got 2"},
+				},
+			},
+			{Name: "down", Error: "connection refused"},
+		},
+	}
+}
+
+func TestWriteSuiteTAP(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, writeSuite(&out, testSuite(), outputTAP))
+
+	expected := `TAP version 13
+1..3
+ok 1 - health
+not ok 2 - users
+  ---
+  message: "1 of 1 assertions failed"
+  ...
+not ok 3 - down
+  ---
+  message: "connection refused"
+  ...
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestWriteSuiteJUnit(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, writeSuite(&out, testSuite(), outputJUnit))
+
+	var parsed junitTestSuites
+	require.NoError(t, xml.Unmarshal(out.Bytes(), &parsed))
+	require.Len(t, parsed.Suites, 1)
+
+	suite := parsed.Suites[0]
+	assert.Equal(t, "smoke", suite.Name)
+	assert.Equal(t, 3, suite.Tests)
+	assert.Equal(t, 1, suite.Failures)
+	assert.Equal(t, 1, suite.Errors)
+	require.Len(t, suite.Cases, 3)
+	assert.Nil(t, suite.Cases[0].Failure)
+	require.NotNil(t, suite.Cases[1].Failure)
+	assert.Contains(t, suite.Cases[1].Failure.Body, "json $.id == 1")
+	require.NotNil(t, suite.Cases[2].Error)
+	assert.Equal(t, "connection refused", suite.Cases[2].Error.Message)
+}
+
+func TestWriteSuiteText(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, writeSuite(&out, testSuite(), outputText))
+
+	assert.Contains(t, out.String(), "PASS health")
+	assert.Contains(t, out.String(), "FAIL users")
+	assert.Contains(t, out.String(), "error: connection refused")
+	assert.Contains(t, out.String(), "1 passed, 2 failed, 3 total")
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -10,7 +12,16 @@ import (
 type Config struct {
 	App      AppConfig      `yaml:"app"`
 	Database DatabaseConfig `yaml:"database"`
-	Paths    PathsConfig    `yaml:"-"`
+	History  HistoryConfig  `yaml:"history"`
+	HTTP     HTTPConfig     `yaml:"http"`
+	Response ResponseConfig `yaml:"response"`
+	Secrets  SecretsConfig  `yaml:"secrets"`
+	Servers  []ServerConfig `yaml:"servers"`
+	// ServerLogs is about the output of the servers Burrow runs
+	ServerLogs ServerLogsConfig `yaml:"server_logs"`
+	// Watch is about reloading the servers started with watch on
+	Watch WatchConfig `yaml:"watch"`
+	Paths PathsConfig `yaml:"-"`
 }
 
 type AppConfig struct {
@@ -22,6 +33,142 @@ type DatabaseConfig struct {
 	ConnectionString string `yaml:"-"`
 }
 
+// HistoryConfig limits the request history, a zero value disables that limit.
+type HistoryConfig struct {
+	MaxEntries int           `yaml:"max_entries"`
+	MaxAge     time.Duration `yaml:"max_age"`
+	MaxSizeMB  int           `yaml:"max_size_mb"`
+}
+
+// ResponseConfig bounds how much of a response body is kept in memory, the
+// rest of a larger body is written to a temporary file. Zero keeps whole
+// bodies in memory.
+type ResponseConfig struct {
+	MaxBodyMB int `yaml:"max_body_mb"`
+}
+
+// MaxBodyBytes is MaxBodyMB in bytes.
+func (c ResponseConfig) MaxBodyBytes() int64 {
+	return int64(c.MaxBodyMB) << 20
+}
+
+// HTTPConfig configures the client used to send requests. Saved requests can
+// override any of these settings.
+type HTTPConfig struct {
+	Timeout         time.Duration `yaml:"timeout"`
+	FollowRedirects bool          `yaml:"follow_redirects"`
+	MaxRedirects    int           `yaml:"max_redirects"`
+	Proxy           string        `yaml:"proxy"`
+	CACert          string        `yaml:"ca_cert"`
+	ClientCert      string        `yaml:"client_cert"`
+	ClientKey       string        `yaml:"client_key"`
+	Insecure        bool          `yaml:"insecure"`
+	// HTTPVersion forces "1.1" or "2", empty negotiates the version
+	HTTPVersion string `yaml:"http_version"`
+}
+
+// Validate checks the settings that do not need the filesystem.
+func (c HTTPConfig) Validate() error {
+	if c.Timeout < 0 {
+		return fmt.Errorf("http timeout cannot be negative")
+	}
+	if c.MaxRedirects < 0 {
+		return fmt.Errorf("http max redirects cannot be negative")
+	}
+	if (c.ClientCert == "") != (c.ClientKey == "") {
+		return fmt.Errorf("http client certificate and key must be set together")
+	}
+	switch c.HTTPVersion {
+	case "", "1.1", "2":
+	default:
+		return fmt.Errorf("unsupported http version %q, use 1.1 or 2", c.HTTPVersion)
+	}
+	return nil
+}
+
+// SecretsConfig tells where {{secret.NAME}} references are looked up besides
+// the encrypted store in the database.
+type SecretsConfig struct {
+	// File holds NAME=value lines, secrets.env next to the config by default
+	File string `yaml:"file"`
+	// EnvPrefix is put before the upper cased name to read a secret from
+	// the environment
+	EnvPrefix string `yaml:"env_prefix"`
+	// PassphraseEnv names the variable holding the passphrase that unlocks
+	// the database store
+	PassphraseEnv string `yaml:"passphrase_env"`
+}
+
+// ServerConfig describes a server Burrow builds and runs. Servers listed in
+// the config can be started together, such as an API and the auth stub it
+// depends on.
+type ServerConfig struct {
+	Name string `yaml:"name"`
+	// Path is the .go file, package directory, ./ path relative to the
+	// module or import path to build
+	Path string `yaml:"path"`
+	// Dir is where relative paths are looked up, the working directory by
+	// default
+	Dir string `yaml:"dir,omitempty"`
+	// Port is handed to the server in the PORT variable and used for the
+	// default health URL
+	Port string `yaml:"port"`
+	// HealthURL is polled while the server runs, http://localhost:<port>/health
+	// by default
+	HealthURL string   `yaml:"health_url,omitempty"`
+	Tags      []string `yaml:"tags,omitempty"`
+	LDFlags   string   `yaml:"ldflags,omitempty"`
+	Race      bool     `yaml:"race,omitempty"`
+	// Watch rebuilds and restarts the server when its files change
+	Watch bool `yaml:"watch,omitempty"`
+}
+
+// HealthCheckURL returns the URL polled to tell whether the server is up.
+func (c ServerConfig) HealthCheckURL() string {
+	if c.HealthURL != "" {
+		return c.HealthURL
+	}
+	return "http://localhost:" + c.Port + "/health"
+}
+
+// ServerLogsConfig sets how much of the output of each server is kept.
+type ServerLogsConfig struct {
+	// Lines is how many of the last lines are kept in memory
+	Lines int `yaml:"lines"`
+	// Persist also appends the output to a file per server next to the
+	// Burrow log
+	Persist bool `yaml:"persist"`
+}
+
+// WatchConfig sets which changes reload a watched server. Globs match the
+// name of a file or its path relative to the watched directory, a directory
+// matching Exclude is not watched at all.
+type WatchConfig struct {
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+	// Debounce is how long to wait after the last change before rebuilding
+	Debounce time.Duration `yaml:"debounce"`
+}
+
+func validateServers(servers []ServerConfig) error {
+	names := make(map[string]bool, len(servers))
+	ports := make(map[string]string, len(servers))
+	for i, server := range servers {
+		if server.Name == "" || server.Path == "" {
+			return fmt.Errorf("server %d needs a name and a path", i+1)
+		}
+		if names[server.Name] {
+			return fmt.Errorf("server %q is listed twice", server.Name)
+		}
+		names[server.Name] = true
+		if other, ok := ports[server.Port]; ok && server.Port != "" {
+			return fmt.Errorf("servers %q and %q use the same port %s", other, server.Name, server.Port)
+		}
+		ports[server.Port] = server.Name
+	}
+	return nil
+}
+
 type PathsConfig struct {
 	ConfigFile string `yaml:"-"`
 	LogFile    string `yaml:"-"`
@@ -57,6 +204,19 @@ func Load() (*Config, error) {
 func applyDefaults(cfg *Config) {
 	cfg.App.DefaultPort = "8080"
 	cfg.Database.Path = ""
+	cfg.History.MaxEntries = 500
+	cfg.History.MaxAge = 30 * 24 * time.Hour
+	cfg.History.MaxSizeMB = 50
+	cfg.HTTP.Timeout = 5 * time.Second
+	cfg.HTTP.FollowRedirects = true
+	cfg.HTTP.MaxRedirects = 10
+	cfg.Response.MaxBodyMB = 10
+	cfg.Secrets.EnvPrefix = "BURROW_SECRET_"
+	cfg.Secrets.PassphraseEnv = "BURROW_PASSPHRASE"
+	cfg.ServerLogs.Lines = 1000
+	cfg.Watch.Include = []string{"*.go", "go.mod", "go.sum"}
+	cfg.Watch.Exclude = []string{"*_test.go", ".*", "testdata", "vendor", "node_modules"}
+	cfg.Watch.Debounce = 300 * time.Millisecond
 }
 
 func loadFromFile(cfg *Config) error {
@@ -93,6 +253,10 @@ func resolvePaths(cfg *Config) error {
 		cfg.Database.Path = GetDatabasePath()
 	}
 
+	if cfg.Secrets.File == "" {
+		cfg.Secrets.File = GetSecretsPath()
+	}
+
 	cfg.Paths.ConfigFile = GetConfigPath()
 	cfg.Paths.LogFile = GetLogPath()
 
@@ -119,5 +283,34 @@ func validate(cfg *Config) error {
 		return fmt.Errorf("default port cannot be empty")
 	}
 
+	if cfg.History.MaxEntries < 0 || cfg.History.MaxAge < 0 || cfg.History.MaxSizeMB < 0 {
+		return fmt.Errorf("history limits cannot be negative")
+	}
+
+	if err := cfg.HTTP.Validate(); err != nil {
+		return err
+	}
+
+	if cfg.Response.MaxBodyMB < 0 {
+		return fmt.Errorf("response max_body_mb cannot be negative")
+	}
+
+	if err := validateServers(cfg.Servers); err != nil {
+		return err
+	}
+
+	if cfg.ServerLogs.Lines <= 0 {
+		return fmt.Errorf("server_logs lines must be positive")
+	}
+
+	if cfg.Watch.Debounce < 0 {
+		return fmt.Errorf("watch debounce cannot be negative")
+	}
+	for _, pattern := range append(cfg.Watch.Include, cfg.Watch.Exclude...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid watch glob %q: %v", pattern, err)
+		}
+	}
+
 	return nil
 }
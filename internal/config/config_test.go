@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -22,6 +23,21 @@ func TestLoad_WithDefaults(t *testing.T) {
 	assert.Equal(t, GetDatabasePath(), cfg.Database.Path)
 	assert.Equal(t, GetConfigPath(), cfg.Paths.ConfigFile)
 	assert.Equal(t, GetLogPath(), cfg.Paths.LogFile)
+	assert.Equal(t, 500, cfg.History.MaxEntries)
+	assert.Equal(t, 30*24*time.Hour, cfg.History.MaxAge)
+	assert.Equal(t, 50, cfg.History.MaxSizeMB)
+	assert.Equal(t, 10, cfg.Response.MaxBodyMB)
+	assert.Equal(t, 5*time.Second, cfg.HTTP.Timeout)
+	assert.True(t, cfg.HTTP.FollowRedirects)
+	assert.Equal(t, 10, cfg.HTTP.MaxRedirects)
+	assert.Equal(t, GetSecretsPath(), cfg.Secrets.File)
+	assert.Equal(t, "BURROW_SECRET_", cfg.Secrets.EnvPrefix)
+	assert.Equal(t, "BURROW_PASSPHRASE", cfg.Secrets.PassphraseEnv)
+	assert.Equal(t, 1000, cfg.ServerLogs.Lines)
+	assert.False(t, cfg.ServerLogs.Persist)
+	assert.Equal(t, []string{"*.go", "go.mod", "go.sum"}, cfg.Watch.Include)
+	assert.Contains(t, cfg.Watch.Exclude, "*_test.go")
+	assert.Equal(t, 300*time.Millisecond, cfg.Watch.Debounce)
 
 	expectedConnectionString := fmt.Sprintf(
 		"file:%s?cache=shared&mode=rwc&_foreign_keys=on&_busy_timeout=5000&_journal_mode=WAL",
@@ -129,6 +145,106 @@ func TestValidate_MissingDefaultPort(t *testing.T) {
 	assert.Contains(t, err.Error(), "default port cannot be empty")
 }
 
+func TestLoad_WithHistoryConfig(t *testing.T) {
+	clearEnvVars()
+
+	configPath := GetConfigPath()
+	configDir := GetConfigDir()
+
+	err := os.MkdirAll(configDir, 0755)
+	assert.NoError(t, err)
+	defer os.RemoveAll(configDir)
+
+	configContent := `history:
+  max_entries: 100
+  max_age: 72h
+  max_size_mb: 0
+`
+	err = os.WriteFile(configPath, []byte(configContent), 0644)
+	assert.NoError(t, err)
+	defer os.Remove(configPath)
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 100, cfg.History.MaxEntries)
+	assert.Equal(t, 72*time.Hour, cfg.History.MaxAge)
+	assert.Equal(t, 0, cfg.History.MaxSizeMB)
+}
+
+func TestValidate_NegativeHistoryLimit(t *testing.T) {
+	cfg := &Config{
+		App: AppConfig{DefaultPort: "8080"},
+		Database: DatabaseConfig{
+			Path: "/path/to/db.sqlite",
+		},
+		History: HistoryConfig{MaxEntries: -1},
+	}
+
+	err := validate(cfg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "history limits cannot be negative")
+}
+
+func TestLoad_WithHTTPConfig(t *testing.T) {
+	clearEnvVars()
+
+	configPath := GetConfigPath()
+	configDir := GetConfigDir()
+
+	err := os.MkdirAll(configDir, 0755)
+	assert.NoError(t, err)
+	defer os.RemoveAll(configDir)
+
+	configContent := `http:
+  timeout: 10s
+  follow_redirects: false
+  proxy: http://localhost:3128
+  insecure: true
+  http_version: "1.1"
+`
+	err = os.WriteFile(configPath, []byte(configContent), 0644)
+	assert.NoError(t, err)
+	defer os.Remove(configPath)
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 10*time.Second, cfg.HTTP.Timeout)
+	assert.False(t, cfg.HTTP.FollowRedirects)
+	assert.Equal(t, 10, cfg.HTTP.MaxRedirects)
+	assert.Equal(t, "http://localhost:3128", cfg.HTTP.Proxy)
+	assert.True(t, cfg.HTTP.Insecure)
+	assert.Equal(t, "1.1", cfg.HTTP.HTTPVersion)
+}
+
+func TestHTTPConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     HTTPConfig
+		wantErr string
+	}{
+		{name: "defaults", cfg: HTTPConfig{Timeout: time.Second, FollowRedirects: true, MaxRedirects: 10}},
+		{name: "http2", cfg: HTTPConfig{HTTPVersion: "2"}},
+		{name: "client certificate", cfg: HTTPConfig{ClientCert: "cert.pem", ClientKey: "key.pem"}},
+		{name: "negative timeout", cfg: HTTPConfig{Timeout: -time.Second}, wantErr: "timeout cannot be negative"},
+		{name: "negative redirects", cfg: HTTPConfig{MaxRedirects: -1}, wantErr: "max redirects cannot be negative"},
+		{name: "certificate without key", cfg: HTTPConfig{ClientCert: "cert.pem"}, wantErr: "must be set together"},
+		{name: "unknown version", cfg: HTTPConfig{HTTPVersion: "3"}, wantErr: "unsupported http version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
 func TestGenerateDbString(t *testing.T) {
 	dbPath := "/path/to/test.db"
 	expected := "file:/path/to/test.db?cache=shared&mode=rwc&_foreign_keys=on&_busy_timeout=5000&_journal_mode=WAL"
@@ -143,3 +259,48 @@ func clearEnvVars() {
 		os.Unsetenv(env)
 	}
 }
+
+func TestValidate_Servers(t *testing.T) {
+	cfg := &Config{
+		App:        AppConfig{DefaultPort: "8080"},
+		Database:   DatabaseConfig{Path: "/path/to/db.sqlite"},
+		ServerLogs: ServerLogsConfig{Lines: 1000},
+		Servers: []ServerConfig{
+			{Name: "api", Path: "cmd/api/main.go", Port: "8080"},
+			{Name: "auth", Path: "stubs/auth.go", Port: "9090", HealthURL: "http://localhost:9090/ping"},
+		},
+	}
+	assert.NoError(t, validate(cfg))
+	assert.Equal(t, "http://localhost:8080/health", cfg.Servers[0].HealthCheckURL())
+	assert.Equal(t, "http://localhost:9090/ping", cfg.Servers[1].HealthCheckURL())
+
+	cfg.Servers[1].Port = "8080"
+	assert.ErrorContains(t, validate(cfg), `servers "api" and "auth" use the same port 8080`)
+
+	cfg.Servers[1].Name = "api"
+	assert.ErrorContains(t, validate(cfg), `server "api" is listed twice`)
+
+	cfg.Servers[1].Path = ""
+	assert.ErrorContains(t, validate(cfg), "server 2 needs a name and a path")
+
+	cfg.Servers = nil
+	cfg.ServerLogs.Lines = 0
+	assert.ErrorContains(t, validate(cfg), "server_logs lines must be positive")
+}
+
+func TestValidate_Watch(t *testing.T) {
+	cfg := &Config{
+		App:        AppConfig{DefaultPort: "8080"},
+		Database:   DatabaseConfig{Path: "/path/to/db.sqlite"},
+		ServerLogs: ServerLogsConfig{Lines: 1000},
+		Watch:      WatchConfig{Include: []string{"*.go", "templates/*.html"}, Debounce: time.Second},
+	}
+	assert.NoError(t, validate(cfg))
+
+	cfg.Watch.Exclude = []string{"[gen"}
+	assert.ErrorContains(t, validate(cfg), `invalid watch glob "[gen"`)
+
+	cfg.Watch.Exclude = nil
+	cfg.Watch.Debounce = -time.Second
+	assert.ErrorContains(t, validate(cfg), "watch debounce cannot be negative")
+}
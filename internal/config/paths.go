@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/adrg/xdg"
 )
@@ -14,6 +15,10 @@ func GetConfigPath() string {
 	return filepath.Join(xdg.ConfigHome, appName, "config.yaml")
 }
 
+func GetSecretsPath() string {
+	return filepath.Join(xdg.ConfigHome, appName, "secrets.env")
+}
+
 func GetDatabasePath() string {
 	return filepath.Join(xdg.DataHome, appName, "burrow.db")
 }
@@ -22,6 +27,12 @@ func GetLogPath() string {
 	return filepath.Join(xdg.StateHome, appName, "burrow_log")
 }
 
+// GetServerLogPath is where the output of the named server is persisted.
+func GetServerLogPath(name string) string {
+	name = strings.ReplaceAll(name, string(filepath.Separator), "_")
+	return filepath.Join(filepath.Dir(GetLogPath()), "servers", name+".log")
+}
+
 func GetCachePath() string {
 	return filepath.Join(xdg.CacheHome, appName)
 }
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -12,6 +13,12 @@ func TestGetConfigPath(t *testing.T) {
 	assert.Contains(t, path, "burrow")
 }
 
+func TestGetSecretsPath(t *testing.T) {
+	path := GetSecretsPath()
+	assert.NotEmpty(t, path)
+	assert.Contains(t, path, "secrets.env")
+}
+
 func TestGetDatabasePath(t *testing.T) {
 	path := GetDatabasePath()
 	assert.NotEmpty(t, path)
@@ -29,3 +36,8 @@ func TestGetServerCachePath(t *testing.T) {
 	assert.NotEmpty(t, path)
 	assert.Contains(t, path, "servers")
 }
+
+func TestGetServerLogPath(t *testing.T) {
+	path := GetServerLogPath("cmd/api")
+	assert.Equal(t, filepath.Join(filepath.Dir(GetLogPath()), "servers", "cmd_api.log"), path)
+}
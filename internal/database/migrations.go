@@ -4,14 +4,16 @@ import (
 	"database/sql"
 	"embed"
 	"fmt"
+	"io/fs"
 	"log"
+	"path"
 	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
-//go:embed migrations/001_create_requests_table.sql
+//go:embed migrations/*.sql
 var migrationFS embed.FS
 
 type Migration struct {
@@ -45,21 +47,33 @@ func (mr *MigrationRunner) LoadMigrations() error {
 }
 
 func (mr *MigrationRunner) loadEmbeddedMigrations() error {
-	migrationFile := "migrations/001_create_requests_table.sql"
-
-	content, err := migrationFS.ReadFile(migrationFile)
+	entries, err := fs.ReadDir(migrationFS, "migrations")
 	if err != nil {
-		return fmt.Errorf("failed to read embedded migration: %w", err)
+		return fmt.Errorf("failed to list embedded migrations: %w", err)
 	}
 
-	migration, err := mr.parseMigration("001_create_requests_table.sql", string(content))
-	if err != nil {
-		return fmt.Errorf("failed to parse embedded migration: %w", err)
-	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		migrationFile := path.Join("migrations", entry.Name())
 
-	mr.migrations = append(mr.migrations, migration)
+		content, err := migrationFS.ReadFile(migrationFile)
+		if err != nil {
+			return fmt.Errorf("failed to read embedded migration: %w", err)
+		}
+
+		migration, err := mr.parseMigration(entry.Name(), string(content))
+		if err != nil {
+			return fmt.Errorf("failed to parse embedded migration: %w", err)
+		}
+
+		mr.migrations = append(mr.migrations, migration)
+
+		log.Printf("Successfully loaded embedded migration: %s", migrationFile)
+	}
 
-	log.Printf("Successfully loaded embedded migration: %s", migrationFile)
 	return nil
 }
 
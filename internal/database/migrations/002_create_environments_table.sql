@@ -0,0 +1,10 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS environments (
+  name TEXT PRIMARY KEY,
+  created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  variables_json TEXT NOT NULL
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS environments;
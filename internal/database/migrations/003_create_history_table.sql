@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS history (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  sent_at DATETIME NOT NULL,
+  method TEXT NOT NULL,
+  url TEXT NOT NULL,
+  status_code INTEGER NOT NULL DEFAULT 0,
+  request_json TEXT NOT NULL,
+  response_json TEXT NOT NULL,
+  error TEXT NOT NULL DEFAULT '',
+  size INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS history_sent_at_idx ON history (sent_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS history_sent_at_idx;
+DROP TABLE IF EXISTS history;
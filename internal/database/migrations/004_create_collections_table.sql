@@ -0,0 +1,22 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS collections (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name TEXT NOT NULL,
+  parent_id INTEGER REFERENCES collections (id) ON DELETE CASCADE,
+  created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE request_blobs ADD COLUMN collection_id INTEGER REFERENCES collections (id) ON DELETE CASCADE;
+
+-- +migrate Down
+CREATE TABLE request_blobs_old (
+  name TEXT PRIMARY KEY,
+  created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  request_json TEXT NOT_NULL
+);
+INSERT INTO request_blobs_old SELECT name, created_at, updated_at, request_json FROM request_blobs;
+DROP TABLE request_blobs;
+ALTER TABLE request_blobs_old RENAME TO request_blobs;
+DROP TABLE IF EXISTS collections;
@@ -0,0 +1,9 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS server_targets (
+  path TEXT PRIMARY KEY,
+  spec_json TEXT NOT NULL,
+  used_at DATETIME NOT NULL
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS server_targets;
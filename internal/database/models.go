@@ -6,11 +6,55 @@ package database
 
 import (
 	"database/sql"
+	"time"
 )
 
+type Collection struct {
+	ID        int64
+	Name      string
+	ParentID  sql.NullInt64
+	CreatedAt sql.NullTime
+	UpdatedAt sql.NullTime
+}
+
+type Environment struct {
+	Name          string
+	CreatedAt     sql.NullTime
+	UpdatedAt     sql.NullTime
+	VariablesJson string
+}
+
+type History struct {
+	ID           int64
+	SentAt       time.Time
+	Method       string
+	Url          string
+	StatusCode   int64
+	RequestJson  string
+	ResponseJson string
+	Error        string
+	Size         int64
+}
+
 type RequestBlob struct {
-	Name        string
-	CreatedAt   sql.NullTime
-	UpdatedAt   sql.NullTime
-	RequestJson interface{}
+	Name         string
+	CreatedAt    sql.NullTime
+	UpdatedAt    sql.NullTime
+	RequestJson  interface{}
+	CollectionID sql.NullInt64
+}
+
+type Secret struct {
+	Name       string
+	CreatedAt  sql.NullTime
+	UpdatedAt  sql.NullTime
+	Salt       []byte
+	Nonce      []byte
+	Ciphertext []byte
+}
+
+type ServerTarget struct {
+	Path     string
+	SpecJson string
+	UsedAt   time.Time
 }
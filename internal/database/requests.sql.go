@@ -7,30 +7,33 @@ package database
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createRequest = `-- name: CreateRequest :one
 INSERT INTO request_blobs (
-  name, request_json
+  name, request_json, collection_id
 ) VALUES (
-    ?, ?
+    ?, ?, ?
 )
-RETURNING name, created_at, updated_at, request_json
+RETURNING name, created_at, updated_at, request_json, collection_id
 `
 
 type CreateRequestParams struct {
-	Name        string
-	RequestJson interface{}
+	Name         string
+	RequestJson  interface{}
+	CollectionID sql.NullInt64
 }
 
 func (q *Queries) CreateRequest(ctx context.Context, arg CreateRequestParams) (RequestBlob, error) {
-	row := q.db.QueryRowContext(ctx, createRequest, arg.Name, arg.RequestJson)
+	row := q.db.QueryRowContext(ctx, createRequest, arg.Name, arg.RequestJson, arg.CollectionID)
 	var i RequestBlob
 	err := row.Scan(
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.RequestJson,
+		&i.CollectionID,
 	)
 	return i, err
 }
@@ -45,7 +48,7 @@ func (q *Queries) DeleteRequest(ctx context.Context, name string) error {
 }
 
 const getRequest = `-- name: GetRequest :one
-SELECT name, created_at, updated_at, request_json FROM request_blobs WHERE name = ? LIMIT 1
+SELECT name, created_at, updated_at, request_json, collection_id FROM request_blobs WHERE name = ? LIMIT 1
 `
 
 func (q *Queries) GetRequest(ctx context.Context, name string) (RequestBlob, error) {
@@ -56,12 +59,13 @@ func (q *Queries) GetRequest(ctx context.Context, name string) (RequestBlob, err
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.RequestJson,
+		&i.CollectionID,
 	)
 	return i, err
 }
 
 const listRequests = `-- name: ListRequests :many
-SELECT name, created_at, updated_at, request_json FROM request_blobs ORDER BY created_at DESC
+SELECT name, created_at, updated_at, request_json, collection_id FROM request_blobs ORDER BY created_at DESC
 `
 
 func (q *Queries) ListRequests(ctx context.Context) ([]RequestBlob, error) {
@@ -78,6 +82,7 @@ func (q *Queries) ListRequests(ctx context.Context) ([]RequestBlob, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.RequestJson,
+			&i.CollectionID,
 		); err != nil {
 			return nil, err
 		}
@@ -92,11 +97,44 @@ func (q *Queries) ListRequests(ctx context.Context) ([]RequestBlob, error) {
 	return items, nil
 }
 
+const moveRequest = `-- name: MoveRequest :exec
+UPDATE request_blobs
+SET collection_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE name = ?
+`
+
+type MoveRequestParams struct {
+	CollectionID sql.NullInt64
+	Name         string
+}
+
+func (q *Queries) MoveRequest(ctx context.Context, arg MoveRequestParams) error {
+	_, err := q.db.ExecContext(ctx, moveRequest, arg.CollectionID, arg.Name)
+	return err
+}
+
+const renameRequest = `-- name: RenameRequest :exec
+UPDATE request_blobs
+SET name = ?1, request_json = ?2, updated_at = CURRENT_TIMESTAMP
+WHERE name = ?3
+`
+
+type RenameRequestParams struct {
+	NewName     string
+	RequestJson interface{}
+	Name        string
+}
+
+func (q *Queries) RenameRequest(ctx context.Context, arg RenameRequestParams) error {
+	_, err := q.db.ExecContext(ctx, renameRequest, arg.NewName, arg.RequestJson, arg.Name)
+	return err
+}
+
 const updateRequest = `-- name: UpdateRequest :one
 UPDATE request_blobs
 SET request_json = ?, updated_at = CURRENT_TIMESTAMP
 WHERE name = ?
-RETURNING name, created_at, updated_at, request_json
+RETURNING name, created_at, updated_at, request_json, collection_id
 `
 
 type UpdateRequestParams struct {
@@ -112,6 +150,7 @@ func (q *Queries) UpdateRequest(ctx context.Context, arg UpdateRequestParams) (R
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.RequestJson,
+		&i.CollectionID,
 	)
 	return i, err
 }
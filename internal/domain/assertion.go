@@ -0,0 +1,295 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/textproto"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	AssertStatus = "status"
+	AssertHeader = "header"
+	AssertJSON   = "json"
+	AssertBody   = "body"
+	AssertTime   = "time"
+)
+
+const (
+	OpEquals    = "=="
+	OpNotEquals = "!="
+	OpContains  = "contains"
+	OpMatches   = "~"
+	OpLessThan  = "<"
+)
+
+// allowedOperators lists the operators each assertion kind understands.
+var allowedOperators = map[string][]string{
+	AssertStatus: {OpEquals, OpNotEquals},
+	AssertHeader: {OpEquals, OpNotEquals, OpContains},
+	AssertJSON:   {OpEquals, OpNotEquals},
+	AssertBody:   {OpContains, OpMatches},
+	AssertTime:   {OpLessThan},
+}
+
+type Assertion struct {
+	Kind     string `json:"kind"`
+	Target   string `json:"target,omitempty"`
+	Operator string `json:"operator"`
+	Expected string `json:"expected"`
+}
+
+type AssertionResult struct {
+	Assertion Assertion `json:"assertion"`
+	Passed    bool      `json:"passed"`
+	Actual    string    `json:"actual"`
+	Message   string    `json:"message,omitempty"`
+}
+
+// ParseAssertion reads a single assertion line such as
+// "status == 200", "header Content-Type contains json",
+// "json $.user.id == 5", "body ~ ^ok$" or "time < 500ms".
+func ParseAssertion(line string) (Assertion, error) {
+	kind, rest := cutField(strings.TrimSpace(line))
+	a := Assertion{Kind: strings.ToLower(kind)}
+
+	operators, ok := allowedOperators[a.Kind]
+	if !ok {
+		return Assertion{}, fmt.Errorf("unknown assertion %q", kind)
+	}
+
+	if a.Kind == AssertHeader || a.Kind == AssertJSON {
+		a.Target, rest = cutField(rest)
+		if a.Target == "" {
+			return Assertion{}, fmt.Errorf("%s assertion requires a target", a.Kind)
+		}
+	}
+
+	a.Operator, rest = cutField(rest)
+	a.Expected = strings.TrimSpace(rest)
+
+	if !slices.Contains(operators, a.Operator) {
+		return Assertion{}, fmt.Errorf("%s assertion does not support operator %q", a.Kind, a.Operator)
+	}
+	if a.Expected == "" {
+		return Assertion{}, fmt.Errorf("%s assertion requires an expected value", a.Kind)
+	}
+
+	switch a.Kind {
+	case AssertStatus:
+		if _, err := strconv.Atoi(a.Expected); err != nil {
+			return Assertion{}, fmt.Errorf("invalid status code %q", a.Expected)
+		}
+	case AssertTime:
+		if _, err := time.ParseDuration(a.Expected); err != nil {
+			return Assertion{}, fmt.Errorf("invalid duration %q", a.Expected)
+		}
+	case AssertBody:
+		if a.Operator == OpMatches {
+			if _, err := regexp.Compile(a.Expected); err != nil {
+				return Assertion{}, fmt.Errorf("invalid regex %q: %v", a.Expected, err)
+			}
+		}
+	}
+
+	return a, nil
+}
+
+func (req *Request) ParseAssertions(assertionsStr string) error {
+	req.Assertions = nil
+
+	for line := range strings.SplitSeq(assertionsStr, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		a, err := ParseAssertion(line)
+		if err != nil {
+			return err
+		}
+		req.Assertions = append(req.Assertions, a)
+	}
+	return nil
+}
+
+func (a Assertion) String() string {
+	if a.Target != "" {
+		return fmt.Sprintf("%s %s %s %s", a.Kind, a.Target, a.Operator, a.Expected)
+	}
+	return fmt.Sprintf("%s %s %s", a.Kind, a.Operator, a.Expected)
+}
+
+func (a Assertion) Evaluate(resp *Response) AssertionResult {
+	result := AssertionResult{Assertion: a}
+
+	switch a.Kind {
+	case AssertStatus:
+		result.Actual = strconv.Itoa(resp.StatusCode)
+		result.Passed = compare(a.Operator, result.Actual, a.Expected)
+	case AssertHeader:
+		values, ok := resp.Headers[textproto.CanonicalMIMEHeaderKey(a.Target)]
+		if !ok {
+			result.Message = "header not present"
+			result.Passed = a.Operator == OpNotEquals
+			return result
+		}
+		result.Actual = strings.Join(values, ", ")
+		result.Passed = compare(a.Operator, result.Actual, a.Expected)
+	case AssertJSON:
+		actual, err := lookupJSONPath(resp.Body, a.Target)
+		if err != nil {
+			result.Message = err.Error()
+			return result
+		}
+		result.Actual = actual
+		result.Passed = compare(a.Operator, actual, unquoteJSON(a.Expected))
+	case AssertBody:
+		result.Actual = truncate(resp.Body, 60)
+		if a.Operator == OpMatches {
+			re, err := regexp.Compile(a.Expected)
+			if err != nil {
+				result.Message = err.Error()
+				return result
+			}
+			result.Passed = re.MatchString(resp.Body)
+		} else {
+			result.Passed = strings.Contains(resp.Body, a.Expected)
+		}
+	case AssertTime:
+		result.Actual = resp.ResponseTime.String()
+		limit, err := time.ParseDuration(a.Expected)
+		if err != nil {
+			result.Message = err.Error()
+			return result
+		}
+		result.Passed = resp.ResponseTime < limit
+	default:
+		result.Message = fmt.Sprintf("unknown assertion %q", a.Kind)
+	}
+
+	if !result.Passed && result.Message == "" {
+		result.Message = fmt.Sprintf("expected %s %s, got %s", a.Operator, a.Expected, result.Actual)
+	}
+
+	return result
+}
+
+func EvaluateAssertions(assertions []Assertion, resp *Response) []AssertionResult {
+	results := make([]AssertionResult, 0, len(assertions))
+	for _, a := range assertions {
+		results = append(results, a.Evaluate(resp))
+	}
+	return results
+}
+
+func AssertionsPassed(results []AssertionResult) bool {
+	for _, r := range results {
+		if !r.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+func compare(operator, actual, expected string) bool {
+	switch operator {
+	case OpEquals:
+		return actual == expected
+	case OpNotEquals:
+		return actual != expected
+	case OpContains:
+		return strings.Contains(actual, expected)
+	}
+	return false
+}
+
+// lookupJSONPath resolves paths like "$.users[0].name" or "users.0.name"
+// against a JSON document and returns the value in its JSON text form,
+// with strings unquoted.
+func lookupJSONPath(body, path string) (string, error) {
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+
+	var current any
+	if err := decoder.Decode(&current); err != nil {
+		return "", errors.New("body is not valid JSON")
+	}
+
+	for _, segment := range splitJSONPath(path) {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[segment]
+			if !ok {
+				return "", fmt.Errorf("path %s not found", path)
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return "", fmt.Errorf("path %s not found", path)
+			}
+			current = node[index]
+		default:
+			return "", fmt.Errorf("path %s not found", path)
+		}
+	}
+
+	switch value := current.(type) {
+	case string:
+		return value, nil
+	case nil:
+		return "null", nil
+	default:
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(value); err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(buf.String()), nil
+	}
+}
+
+func splitJSONPath(path string) []string {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	path = strings.ReplaceAll(path, "[", ".")
+	path = strings.ReplaceAll(path, "]", "")
+
+	var segments []string
+	for segment := range strings.SplitSeq(path, ".") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+func unquoteJSON(s string) string {
+	if unquoted, err := strconv.Unquote(s); err == nil && strings.HasPrefix(s, `"`) {
+		return unquoted
+	}
+	return s
+}
+
+func cutField(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	field, rest, _ := strings.Cut(s, " ")
+	return field, strings.TrimSpace(rest)
+}
+
+// truncate cuts s to at most max bytes, backing up to a rune boundary.
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max] + "..."
+}
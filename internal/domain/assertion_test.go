@@ -0,0 +1,198 @@
+package domain
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAssertion(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    Assertion
+		expectError bool
+	}{
+		{
+			name:     "Parse status assertion",
+			input:    "status == 200",
+			expected: Assertion{Kind: AssertStatus, Operator: OpEquals, Expected: "200"},
+		},
+		{
+			name:     "Parse header contains assertion",
+			input:    "header Content-Type contains json",
+			expected: Assertion{Kind: AssertHeader, Target: "Content-Type", Operator: OpContains, Expected: "json"},
+		},
+		{
+			name:     "Parse json path assertion with spaces in value",
+			input:    `json $.user.name == "Burrow Bunny"`,
+			expected: Assertion{Kind: AssertJSON, Target: "$.user.name", Operator: OpEquals, Expected: `"Burrow Bunny"`},
+		},
+		{
+			name:     "Parse body regex assertion",
+			input:    "  body ~ ^Welcome  ",
+			expected: Assertion{Kind: AssertBody, Operator: OpMatches, Expected: "^Welcome"},
+		},
+		{
+			name:     "Parse time assertion",
+			input:    "time < 500ms",
+			expected: Assertion{Kind: AssertTime, Operator: OpLessThan, Expected: "500ms"},
+		},
+		{
+			name:        "Parse unknown kind",
+			input:       "cookie == 1",
+			expectError: true,
+		},
+		{
+			name:        "Parse unsupported operator",
+			input:       "status contains 2",
+			expectError: true,
+		},
+		{
+			name:        "Parse invalid status code",
+			input:       "status == ok",
+			expectError: true,
+		},
+		{
+			name:        "Parse invalid duration",
+			input:       "time < soon",
+			expectError: true,
+		},
+		{
+			name:        "Parse invalid regex",
+			input:       "body ~ (",
+			expectError: true,
+		},
+		{
+			name:        "Parse missing expected value",
+			input:       "header Content-Type ==",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := ParseAssertion(tt.input)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, a)
+		})
+	}
+}
+
+func TestParseAssertions(t *testing.T) {
+	req := &Request{}
+	err := req.ParseAssertions("status == 200\n\n  time < 1s\n")
+	assert.NoError(t, err)
+	assert.Len(t, req.Assertions, 2)
+
+	err = req.ParseAssertions("status == 200\nbogus")
+	assert.Error(t, err)
+}
+
+func TestAssertionString(t *testing.T) {
+	for _, line := range []string{"status == 200", "header Content-Type contains json", "time < 500ms"} {
+		a, err := ParseAssertion(line)
+		require.NoError(t, err)
+		assert.Equal(t, line, a.String())
+	}
+}
+
+func TestEvaluateAssertion(t *testing.T) {
+	resp := &Response{
+		StatusCode: 201,
+		Headers: http.Header{
+			"Content-Type": []string{"application/json"},
+		},
+		Body:         "{\n \"id\": 7,\n \"user\": {\"name\": \"burrow\", \"tags\": [\"go\", \"tui\"]},\n \"active\": true\n}",
+		ResponseTime: 120 * time.Millisecond,
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{name: "Status equals", input: "status == 201", expected: true},
+		{name: "Status mismatch", input: "status == 200", expected: false},
+		{name: "Status not equals", input: "status != 500", expected: true},
+		{name: "Header equals case insensitive name", input: "header content-type == application/json", expected: true},
+		{name: "Header contains", input: "header Content-Type contains json", expected: true},
+		{name: "Missing header", input: "header X-Missing == 1", expected: false},
+		{name: "Missing header not equals", input: "header X-Missing != 1", expected: true},
+		{name: "JSON number", input: "json $.id == 7", expected: true},
+		{name: "JSON quoted string", input: `json $.user.name == "burrow"`, expected: true},
+		{name: "JSON bare string", input: "json user.name == burrow", expected: true},
+		{name: "JSON array index", input: "json $.user.tags[1] == tui", expected: true},
+		{name: "JSON boolean", input: "json $.active == true", expected: true},
+		{name: "JSON object", input: `json $.user.tags == ["go","tui"]`, expected: true},
+		{name: "JSON missing path", input: "json $.nope == 1", expected: false},
+		{name: "Body regex", input: `body ~ "id":\s*7`, expected: true},
+		{name: "Body contains", input: "body contains burrow", expected: true},
+		{name: "Time under limit", input: "time < 500ms", expected: true},
+		{name: "Time over limit", input: "time < 100ms", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := ParseAssertion(tt.input)
+			require.NoError(t, err)
+
+			result := a.Evaluate(resp)
+			assert.Equal(t, tt.expected, result.Passed, result.Message)
+			if !tt.expected {
+				assert.NotEmpty(t, result.Message)
+			}
+		})
+	}
+}
+
+func TestTestCasePassed(t *testing.T) {
+	ok := &Response{StatusCode: 200}
+	notFound := &Response{StatusCode: 404}
+
+	tests := []struct {
+		name     string
+		tc       TestCase
+		expected bool
+	}{
+		{name: "Transport error", tc: TestCase{Error: "refused"}, expected: false},
+		{name: "No assertions 2xx", tc: TestCase{Response: ok}, expected: true},
+		{name: "No assertions 404", tc: TestCase{Response: notFound}, expected: false},
+		{
+			name:     "Passing assertions on 404",
+			tc:       TestCase{Response: notFound, Results: []AssertionResult{{Passed: true}}},
+			expected: true,
+		},
+		{
+			name:     "Failing assertion",
+			tc:       TestCase{Response: ok, Results: []AssertionResult{{Passed: true}, {Passed: false}}},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.tc.Passed())
+		})
+	}
+
+	suite := &SuiteResult{Cases: []*TestCase{{Response: ok}, {Error: "refused"}}}
+	assert.Equal(t, 1, suite.PassedCount())
+	assert.Equal(t, 1, suite.FailedCount())
+	assert.False(t, suite.Passed())
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", truncate("short", 10))
+	assert.Equal(t, "abc...", truncate("abcdef", 3))
+	// é is two bytes, cutting at 2 would split it
+	assert.Equal(t, "a...", truncate("aéb", 2))
+	assert.Equal(t, "aé...", truncate("aébc", 3))
+}
@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	AuthBasic  = "basic"
+	AuthBearer = "bearer"
+	AuthAPIKey = "apikey"
+	AuthOAuth2 = "oauth2"
+	AuthDigest = "digest"
+)
+
+const (
+	APIKeyInHeader = "header"
+	APIKeyInQuery  = "query"
+)
+
+const (
+	GrantClientCredentials = "client_credentials"
+	GrantPassword          = "password"
+)
+
+// Auth holds the credentials of a request. Only the fields used by its Type
+// are set: Username and Password for basic, digest and the OAuth2 password
+// grant, Token for bearer, and Key, Value and In for API keys.
+type Auth struct {
+	Type     string `json:"type"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+	Key      string `json:"key,omitempty"`
+	Value    string `json:"value,omitempty"`
+	In       string `json:"in,omitempty"`
+
+	Grant        string `json:"grant,omitempty"`
+	TokenURL     string `json:"token_url,omitempty"`
+	ClientID     string `json:"client_id,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+	Scopes       string `json:"scopes,omitempty"`
+}
+
+func (a *Auth) Validate() error {
+	if a == nil {
+		return nil
+	}
+
+	switch a.Type {
+	case AuthBasic, AuthDigest:
+		if a.Username == "" {
+			return errors.New("auth username required")
+		}
+	case AuthBearer:
+		if a.Token == "" {
+			return errors.New("auth token required")
+		}
+	case AuthAPIKey:
+		if a.Key == "" {
+			return errors.New("api key name required")
+		}
+		if a.In != APIKeyInHeader && a.In != APIKeyInQuery {
+			return fmt.Errorf("api key must go in %s or %s", APIKeyInHeader, APIKeyInQuery)
+		}
+	case AuthOAuth2:
+		if a.TokenURL == "" || a.ClientID == "" {
+			return errors.New("oauth2 token url and client id required")
+		}
+		switch a.Grant {
+		case GrantClientCredentials:
+		case GrantPassword:
+			if a.Username == "" {
+				return errors.New("oauth2 password grant requires a username")
+			}
+		default:
+			return fmt.Errorf("unsupported oauth2 grant %q", a.Grant)
+		}
+	default:
+		return fmt.Errorf("unsupported auth type %q", a.Type)
+	}
+	return nil
+}
+
+// Apply adds the credentials that need no round trip to the server. OAuth2
+// and digest are handled when the request is sent.
+func (a *Auth) Apply(httpReq *http.Request) {
+	if a == nil {
+		return
+	}
+
+	switch a.Type {
+	case AuthBasic:
+		httpReq.SetBasicAuth(a.Username, a.Password)
+	case AuthBearer:
+		httpReq.Header.Set("Authorization", "Bearer "+a.Token)
+	case AuthAPIKey:
+		if a.In == APIKeyInQuery {
+			query := httpReq.URL.Query()
+			query.Set(a.Key, a.Value)
+			httpReq.URL.RawQuery = query.Encode()
+			return
+		}
+		httpReq.Header.Set(a.Key, a.Value)
+	}
+}
+
+// Summary describes the auth for display with its secrets masked.
+func (a *Auth) Summary() string {
+	if a == nil {
+		return "none"
+	}
+
+	switch a.Type {
+	case AuthBasic:
+		return fmt.Sprintf("Basic %s:%s", a.Username, MaskSecret(a.Password))
+	case AuthDigest:
+		return fmt.Sprintf("Digest %s:%s", a.Username, MaskSecret(a.Password))
+	case AuthBearer:
+		return "Bearer " + MaskSecret(a.Token)
+	case AuthAPIKey:
+		return fmt.Sprintf("API key %s in %s: %s", a.Key, a.In, MaskSecret(a.Value))
+	case AuthOAuth2:
+		return fmt.Sprintf("OAuth2 %s for %s at %s", a.Grant, a.ClientID, a.TokenURL)
+	default:
+		return a.Type
+	}
+}
+
+// Masked returns a copy of the auth with its secrets masked, for rendering
+// the request somewhere the credentials should not show.
+func (a *Auth) Masked() *Auth {
+	if a == nil {
+		return nil
+	}
+
+	masked := *a
+	masked.Password = MaskSecret(a.Password)
+	masked.Token = MaskSecret(a.Token)
+	masked.Value = MaskSecret(a.Value)
+	masked.ClientSecret = MaskSecret(a.ClientSecret)
+	return &masked
+}
+
+// MaskSecret hides a secret value. Variable references are shown as they do
+// not reveal anything.
+func MaskSecret(value string) string {
+	if value == "" || (strings.HasPrefix(value, "{{") && strings.HasSuffix(value, "}}")) {
+		return value
+	}
+	return "****"
+}
+
+func (a *Auth) withVariables(lookup func(string) (string, bool)) *Auth {
+	if a == nil {
+		return nil
+	}
+
+	resolved := *a
+	for _, field := range []*string{
+		&resolved.Username, &resolved.Password, &resolved.Token, &resolved.Key, &resolved.Value,
+		&resolved.TokenURL, &resolved.ClientID, &resolved.ClientSecret, &resolved.Scopes,
+	} {
+		*field = ExpandVariables(*field, lookup)
+	}
+	return &resolved
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		auth    *Auth
+		wantErr string
+	}{
+		{name: "none", auth: nil},
+		{name: "basic", auth: &Auth{Type: AuthBasic, Username: "bob"}},
+		{name: "basic without user", auth: &Auth{Type: AuthBasic}, wantErr: "username required"},
+		{name: "bearer without token", auth: &Auth{Type: AuthBearer}, wantErr: "token required"},
+		{name: "api key in cookie", auth: &Auth{Type: AuthAPIKey, Key: "k", In: "cookie"}, wantErr: "must go in header or query"},
+		{name: "oauth2", auth: &Auth{Type: AuthOAuth2, Grant: GrantClientCredentials, TokenURL: "http://t", ClientID: "id"}},
+		{name: "oauth2 without url", auth: &Auth{Type: AuthOAuth2, Grant: GrantClientCredentials, ClientID: "id"}, wantErr: "token url and client id required"},
+		{name: "oauth2 password without user", auth: &Auth{Type: AuthOAuth2, Grant: GrantPassword, TokenURL: "http://t", ClientID: "id"}, wantErr: "requires a username"},
+		{name: "oauth2 implicit", auth: &Auth{Type: AuthOAuth2, Grant: "implicit", TokenURL: "http://t", ClientID: "id"}, wantErr: "unsupported oauth2 grant"},
+		{name: "unknown", auth: &Auth{Type: "ntlm"}, wantErr: "unsupported auth type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.auth.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestAuthApply(t *testing.T) {
+	tests := []struct {
+		name       string
+		auth       *Auth
+		wantHeader map[string]string
+		wantQuery  string
+	}{
+		{name: "none", auth: nil, wantQuery: "page=1"},
+		{name: "basic", auth: &Auth{Type: AuthBasic, Username: "bob", Password: "pw"}, wantHeader: map[string]string{"Authorization": "Basic Ym9iOnB3"}, wantQuery: "page=1"},
+		{name: "bearer", auth: &Auth{Type: AuthBearer, Token: "abc"}, wantHeader: map[string]string{"Authorization": "Bearer abc"}, wantQuery: "page=1"},
+		{name: "api key header", auth: &Auth{Type: AuthAPIKey, Key: "X-Api-Key", Value: "k1", In: APIKeyInHeader}, wantHeader: map[string]string{"X-Api-Key": "k1"}, wantQuery: "page=1"},
+		{name: "api key query", auth: &Auth{Type: AuthAPIKey, Key: "api_key", Value: "k 1", In: APIKeyInQuery}, wantQuery: "api_key=k+1&page=1"},
+		{name: "digest waits for a challenge", auth: &Auth{Type: AuthDigest, Username: "bob"}, wantQuery: "page=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpReq, err := http.NewRequest("GET", "http://example.com/users?page=1", nil)
+			require.NoError(t, err)
+
+			tt.auth.Apply(httpReq)
+
+			for key, val := range tt.wantHeader {
+				assert.Equal(t, val, httpReq.Header.Get(key))
+			}
+			if tt.wantHeader == nil {
+				assert.Empty(t, httpReq.Header)
+			}
+			assert.Equal(t, tt.wantQuery, httpReq.URL.RawQuery)
+		})
+	}
+}
+
+func TestAuthSummaryMasksSecrets(t *testing.T) {
+	assert.Equal(t, "none", (*Auth)(nil).Summary())
+	assert.Equal(t, "Basic bob:****", (&Auth{Type: AuthBasic, Username: "bob", Password: "pw"}).Summary())
+	assert.Equal(t, "Bearer {{token}}", (&Auth{Type: AuthBearer, Token: "{{token}}"}).Summary())
+	assert.Equal(t, "API key X-Api-Key in header: ****", (&Auth{Type: AuthAPIKey, Key: "X-Api-Key", Value: "k1", In: APIKeyInHeader}).Summary())
+	assert.NotContains(t, (&Auth{Type: AuthOAuth2, Grant: GrantClientCredentials, ClientID: "id", ClientSecret: "s3cret", TokenURL: "http://t"}).Summary(), "s3cret")
+
+	auth := &Auth{Type: AuthBasic, Username: "bob", Password: "pw"}
+	assert.Equal(t, &Auth{Type: AuthBasic, Username: "bob", Password: "****"}, auth.Masked())
+	assert.Equal(t, "pw", auth.Password)
+}
+
+func TestWithVariablesExpandsAuth(t *testing.T) {
+	env := &Environment{Variables: map[string]string{"user": "bob", "pass": "pw"}}
+	req := &Request{URL: "http://example.com", Auth: &Auth{Type: AuthBasic, Username: "{{user}}", Password: "{{pass}}"}}
+
+	resolved := req.WithVariables(env.Lookup)
+
+	assert.Equal(t, "bob", resolved.Auth.Username)
+	assert.Equal(t, "pw", resolved.Auth.Password)
+	assert.Equal(t, "{{user}}", req.Auth.Username)
+}
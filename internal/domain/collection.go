@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+type Collection struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	ParentID int64  `json:"parent_id,omitempty"`
+}
+
+func (c *Collection) ParseName(nameStr string) error {
+	name := strings.ToLower(strings.TrimSpace(nameStr))
+	if name == "" {
+		return errors.New("collection name required")
+	}
+	if strings.Contains(name, "/") {
+		return errors.New("collection name cannot contain '/'")
+	}
+	c.Name = name
+	return nil
+}
+
+// CollectionNode is a collection with its sub collections and requests. The
+// root node has no collection and holds everything that is not in one.
+type CollectionNode struct {
+	Collection *Collection
+	Parent     *CollectionNode
+	Children   []*CollectionNode
+	Requests   []*Request
+}
+
+// BuildCollectionTree nests collections under their parents and files
+// requests into their collection. Anything pointing at a collection that no
+// longer exists ends up at the root.
+func BuildCollectionTree(collections []*Collection, requests []*Request) *CollectionNode {
+	root := &CollectionNode{}
+
+	nodes := make(map[int64]*CollectionNode, len(collections))
+	for _, c := range collections {
+		nodes[c.ID] = &CollectionNode{Collection: c}
+	}
+
+	for _, c := range collections {
+		parent, ok := nodes[c.ParentID]
+		if !ok || parent.isDescendantOf(nodes[c.ID]) {
+			parent = root
+		}
+		nodes[c.ID].Parent = parent
+		parent.Children = append(parent.Children, nodes[c.ID])
+	}
+
+	for _, req := range requests {
+		node, ok := nodes[req.CollectionID]
+		if !ok {
+			node = root
+		}
+		node.Requests = append(node.Requests, req)
+	}
+
+	return root
+}
+
+func (n *CollectionNode) isDescendantOf(ancestor *CollectionNode) bool {
+	for node := n; node != nil; node = node.Parent {
+		if node == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// ID is the collection id of the node, zero for the root.
+func (n *CollectionNode) ID() int64 {
+	if n.Collection == nil {
+		return 0
+	}
+	return n.Collection.ID
+}
+
+// Path is the slash separated list of collection names leading to the node.
+func (n *CollectionNode) Path() string {
+	var parts []string
+	for node := n; node.Collection != nil; node = node.Parent {
+		parts = append([]string{node.Collection.Name}, parts...)
+	}
+	return strings.Join(parts, "/")
+}
+
+// Find returns the node for a slash separated collection path, the root for
+// an empty path and nil when there is no such collection.
+func (n *CollectionNode) Find(path string) *CollectionNode {
+	node := n
+	for part := range strings.SplitSeq(strings.Trim(path, "/"), "/") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+
+		var next *CollectionNode
+		for _, child := range node.Children {
+			if child.Collection.Name == part {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+// Node returns the node of the collection with the given id.
+func (n *CollectionNode) Node(id int64) *CollectionNode {
+	if n.ID() == id {
+		return n
+	}
+	for _, child := range n.Children {
+		if node := child.Node(id); node != nil {
+			return node
+		}
+	}
+	return nil
+}
+
+// AllRequests returns the requests of the node followed by those of its
+// sub collections.
+func (n *CollectionNode) AllRequests() []*Request {
+	reqs := append([]*Request{}, n.Requests...)
+	for _, child := range n.Children {
+		reqs = append(reqs, child.AllRequests()...)
+	}
+	return reqs
+}
+
+// Paths lists the path of every collection below the node.
+func (n *CollectionNode) Paths() []string {
+	var paths []string
+	for _, child := range n.Children {
+		paths = append(paths, child.Path())
+		paths = append(paths, child.Paths()...)
+	}
+	return paths
+}
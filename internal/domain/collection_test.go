@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionParseName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "lowercases and trims", input: "  Payments ", want: "payments"},
+		{name: "empty", input: " ", wantErr: true},
+		{name: "slash", input: "a/b", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Collection{}
+			err := c.ParseName(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, c.Name)
+		})
+	}
+}
+
+func TestBuildCollectionTree(t *testing.T) {
+	collections := []*Collection{
+		{ID: 1, Name: "payments"},
+		{ID: 2, Name: "refunds", ParentID: 1},
+		{ID: 3, Name: "users"},
+	}
+	requests := []*Request{
+		{Name: "health"},
+		{Name: "charge", CollectionID: 1},
+		{Name: "refund", CollectionID: 2},
+		{Name: "orphan", CollectionID: 42},
+	}
+
+	root := BuildCollectionTree(collections, requests)
+
+	assert.Equal(t, int64(0), root.ID())
+	require.Len(t, root.Children, 2)
+	assert.Equal(t, []*Request{requests[0], requests[3]}, root.Requests)
+
+	payments := root.Find("payments")
+	require.NotNil(t, payments)
+	assert.Equal(t, []*Request{requests[1], requests[2]}, payments.AllRequests())
+
+	refunds := root.Find("/Payments/refunds/")
+	require.NotNil(t, refunds)
+	assert.Equal(t, "payments/refunds", refunds.Path())
+	assert.Same(t, refunds, root.Node(2))
+
+	assert.Same(t, root, root.Find(""))
+	assert.Nil(t, root.Find("payments/missing"))
+	assert.Nil(t, root.Node(42))
+	assert.Equal(t, []string{"payments", "payments/refunds", "users"}, root.Paths())
+	assert.Len(t, root.AllRequests(), 4)
+}
+
+func TestBuildCollectionTreeBreaksCycles(t *testing.T) {
+	collections := []*Collection{
+		{ID: 1, Name: "a", ParentID: 2},
+		{ID: 2, Name: "b", ParentID: 1},
+	}
+
+	root := BuildCollectionTree(collections, nil)
+
+	assert.Equal(t, []string{"b", "b/a"}, root.Paths())
+}
+
+func TestRequestClone(t *testing.T) {
+	req := &Request{
+		Name:       "orig",
+		Headers:    map[string]string{"X-Id": "1"},
+		Assertions: []Assertion{{Kind: "status", Operator: "==", Expected: "200"}},
+	}
+
+	clone := req.Clone()
+	clone.Name = "copy"
+	clone.Headers["X-Id"] = "2"
+	clone.Assertions[0].Expected = "201"
+
+	assert.Equal(t, "orig", req.Name)
+	assert.Equal(t, "1", req.Headers["X-Id"])
+	assert.Equal(t, "200", req.Assertions[0].Expected)
+}
@@ -0,0 +1,592 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+)
+
+// curl flags that take no value and have no effect on the request itself.
+var curlIgnoredSwitches = map[string]bool{
+	"-s": true, "--silent": true, "-S": true, "--show-error": true,
+	"-L": true, "--location": true, "-i": true, "--include": true,
+	"-v": true, "--verbose": true,
+	"-f": true, "--fail": true, "--compressed": true, "-N": true,
+	"--no-buffer": true, "-#": true, "--progress-bar": true,
+}
+
+// curl flags that take a value we do not use.
+var curlIgnoredOptions = map[string]bool{
+	"-o": true, "--output": true, "--connect-timeout": true,
+	"-w": true, "--write-out": true, "--retry": true,
+}
+
+// curl short flags that take a value, used to split "-XPOST" style arguments.
+var curlShortOptions = "XHdubAeoxmwEF"
+
+// ParseCurl builds a request from a curl command line, understanding the
+// method, header, data, form, --json and -G flags, query strings in the URL, the
+// auth flags and the flags that map to client options.
+func ParseCurl(command string, cfg *config.Config) (*Request, error) {
+	args, err := SplitShellWords(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 || args[0] != "curl" {
+		return nil, errors.New("command must start with curl")
+	}
+
+	req := NewRequest()
+	var (
+		method   string
+		rawURL   string
+		data     []string
+		form     []FormField
+		bodyFile string
+		jsonBody bool
+		getData  bool
+		digest   bool
+	)
+
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+
+		if len(arg) > 2 && arg[0] == '-' && arg[1] != '-' {
+			if strings.ContainsRune(curlShortOptions, rune(arg[1])) {
+				args = append(args[:i+1], append([]string{arg[2:]}, args[i+1:]...)...)
+				arg = arg[:2]
+			} else if combined := expandShortSwitches(arg); combined != nil {
+				args = append(args[:i], append(combined, args[i+1:]...)...)
+				arg = args[i]
+			}
+		}
+
+		next := func() (string, error) {
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch {
+		case arg == "-X" || arg == "--request":
+			value, err := next()
+			if err != nil {
+				return nil, err
+			}
+			method = value
+		case arg == "-H" || arg == "--header":
+			value, err := next()
+			if err != nil {
+				return nil, err
+			}
+			key, val, ok := strings.Cut(value, ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid header %q", value)
+			}
+			setCurlHeader(req, strings.TrimSpace(key), strings.TrimSpace(val))
+		case arg == "-d" || arg == "--data" || arg == "--data-raw" || arg == "--data-binary" || arg == "--data-ascii":
+			value, err := next()
+			if err != nil {
+				return nil, err
+			}
+			if path, ok := strings.CutPrefix(value, "@"); ok && arg != "--data-raw" {
+				if path == "" || path == "-" {
+					return nil, fmt.Errorf("%s needs a file path after @", arg)
+				}
+				bodyFile = path
+			} else {
+				data = append(data, value)
+			}
+		case arg == "--data-urlencode":
+			value, err := next()
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, urlencodeCurlData(value))
+		case arg == "-F" || arg == "--form" || arg == "--form-string":
+			value, err := next()
+			if err != nil {
+				return nil, err
+			}
+			field, err := parseCurlFormField(value, arg == "--form-string")
+			if err != nil {
+				return nil, err
+			}
+			form = append(form, field)
+		case arg == "--json":
+			value, err := next()
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, value)
+			jsonBody = true
+		case arg == "-G" || arg == "--get":
+			getData = true
+		case arg == "-u" || arg == "--user":
+			value, err := next()
+			if err != nil {
+				return nil, err
+			}
+			username, password, _ := strings.Cut(value, ":")
+			req.Auth = &Auth{Type: AuthBasic, Username: username, Password: password}
+		case arg == "--digest":
+			digest = true
+		case arg == "--basic":
+		case arg == "--oauth2-bearer":
+			value, err := next()
+			if err != nil {
+				return nil, err
+			}
+			req.Auth = &Auth{Type: AuthBearer, Token: value}
+		case arg == "-A" || arg == "--user-agent":
+			value, err := next()
+			if err != nil {
+				return nil, err
+			}
+			req.Headers["User-Agent"] = value
+		case arg == "-b" || arg == "--cookie":
+			value, err := next()
+			if err != nil {
+				return nil, err
+			}
+			req.Headers["Cookie"] = value
+		case arg == "-e" || arg == "--referer":
+			value, err := next()
+			if err != nil {
+				return nil, err
+			}
+			req.Headers["Referer"] = value
+		case arg == "-I" || arg == "--head":
+			method = "HEAD"
+		case arg == "-k" || arg == "--insecure":
+			insecure := true
+			req.clientOptions().Insecure = &insecure
+		case arg == "-m" || arg == "--max-time":
+			value, err := next()
+			if err != nil {
+				return nil, err
+			}
+			seconds, err := strconv.ParseFloat(value, 64)
+			if err != nil || seconds <= 0 {
+				return nil, fmt.Errorf("invalid max time %q", value)
+			}
+			req.clientOptions().Timeout = time.Duration(seconds * float64(time.Second))
+		case arg == "--max-redirs":
+			value, err := next()
+			if err != nil {
+				return nil, err
+			}
+			maxRedirects, err := strconv.Atoi(value)
+			if err != nil || maxRedirects < 0 {
+				return nil, fmt.Errorf("invalid max redirects %q", value)
+			}
+			req.clientOptions().MaxRedirects = &maxRedirects
+		case arg == "-x" || arg == "--proxy":
+			value, err := next()
+			if err != nil {
+				return nil, err
+			}
+			req.clientOptions().Proxy = value
+		case arg == "--cacert":
+			value, err := next()
+			if err != nil {
+				return nil, err
+			}
+			req.clientOptions().CACert = value
+		case arg == "-E" || arg == "--cert":
+			value, err := next()
+			if err != nil {
+				return nil, err
+			}
+			req.clientOptions().ClientCert = value
+		case arg == "--key":
+			value, err := next()
+			if err != nil {
+				return nil, err
+			}
+			req.clientOptions().ClientKey = value
+		case arg == "--http1.1":
+			req.clientOptions().HTTPVersion = "1.1"
+		case arg == "--http2" || arg == "--http2-prior-knowledge":
+			req.clientOptions().HTTPVersion = "2"
+		case arg == "--url":
+			value, err := next()
+			if err != nil {
+				return nil, err
+			}
+			rawURL = value
+		case curlIgnoredSwitches[arg]:
+		case curlIgnoredOptions[arg]:
+			if _, err := next(); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(arg, "-") && arg != "-":
+			return nil, fmt.Errorf("unsupported curl option %s", arg)
+		default:
+			if rawURL != "" {
+				return nil, fmt.Errorf("multiple urls given: %s and %s", rawURL, arg)
+			}
+			rawURL = arg
+		}
+	}
+
+	if rawURL == "" {
+		return nil, errors.New("curl command has no url")
+	}
+
+	// --digest switches -u credentials wherever it appears on the line
+	if digest && req.Auth != nil && req.Auth.Type == AuthBasic {
+		req.Auth.Type = AuthDigest
+	}
+
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %v", err)
+	}
+	// a query that cannot be decoded is kept as written
+	if rest, err := req.AddQuery(parsedURL.RawQuery); err == nil {
+		parsedURL.RawQuery = rest
+	}
+	parsedURL.Fragment = ""
+
+	if err := req.ParseUrl(cfg, parsedURL.String()); err != nil {
+		return nil, err
+	}
+
+	body := strings.Join(data, "&")
+	switch {
+	case getData && body != "":
+		rest, err := req.AddQuery(body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid data for -G: %v", err)
+		}
+		if rest != "" {
+			separator := "?"
+			if strings.Contains(req.URL, "?") {
+				separator = "&"
+			}
+			req.URL += separator + rest
+		}
+		body = ""
+	case jsonBody:
+		req.ContentType["Content-Type"] = "application/json"
+		if _, ok := req.Headers["Accept"]; !ok {
+			req.Headers["Accept"] = "application/json"
+		}
+	case body != "" && req.ContentType["Content-Type"] == "":
+		req.ContentType["Content-Type"] = "application/x-www-form-urlencoded"
+	}
+	req.Body = body
+
+	if len(form) > 0 {
+		if body != "" {
+			return nil, errors.New("-F cannot be combined with data flags")
+		}
+		req.Form = form
+		req.ContentType["Content-Type"] = ContentTypeMultipart
+	}
+
+	// a file is sent as is, typed from its extension unless a header says
+	// otherwise
+	if bodyFile != "" {
+		if body != "" || len(form) > 0 || getData {
+			return nil, errors.New("data read from a file with @ cannot be combined with other data")
+		}
+		req.BodyFile = bodyFile
+	}
+
+	if method == "" {
+		method = "GET"
+		if body != "" || len(form) > 0 || bodyFile != "" {
+			method = "POST"
+		}
+	}
+	if err := req.ParseMethod(method); err != nil {
+		return nil, err
+	}
+
+	if _, ok := req.Headers["User-Agent"]; !ok {
+		if err := req.ParseHeaders(""); err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
+}
+
+// ToCurl renders the request as a runnable curl command.
+func (req *Request) ToCurl() string {
+	// a GraphQL query is copied as the body or params it is sent as, invalid
+	// variables leave it out
+	if sent, err := req.WithGraphQLBody(); err == nil {
+		req = sent
+	}
+
+	var builder strings.Builder
+
+	builder.WriteString("curl")
+	switch req.Method {
+	case "", "GET":
+	case "HEAD":
+		builder.WriteString(" -I")
+	default:
+		builder.WriteString(" -X " + req.Method)
+	}
+
+	fullURL := req.URL
+	apiKeyInQuery := req.Auth != nil && req.Auth.Type == AuthAPIKey && req.Auth.In == APIKeyInQuery
+	if len(req.Params) > 0 || apiKeyInQuery {
+		query := url.Values{}
+		for key, val := range req.Params {
+			query.Set(key, val)
+		}
+		if apiKeyInQuery {
+			query.Set(req.Auth.Key, req.Auth.Value)
+		}
+		separator := "?"
+		if strings.Contains(fullURL, "?") {
+			separator = "&"
+		}
+		fullURL += separator + query.Encode()
+	}
+	builder.WriteString(" " + ShellQuote(fullURL))
+
+	keys := make([]string, 0, len(req.Headers))
+	for key := range req.Headers {
+		if key != "User-Agent" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		builder.WriteString(" \\\n  -H " + ShellQuote(key+": "+req.Headers[key]))
+	}
+
+	builder.WriteString(curlAuthFlags(req.Auth))
+
+	switch {
+	case req.BodyFile != "":
+		if contentType := req.ContentType["Content-Type"]; contentType != "" {
+			builder.WriteString(" \\\n  -H " + ShellQuote("Content-Type: "+contentType))
+		}
+		builder.WriteString(" \\\n  --data-binary " + ShellQuote("@"+req.BodyFile))
+	case len(req.Form) > 0:
+		builder.WriteString(curlFormFlags(req))
+	case req.Body != "":
+		if contentType := req.ContentType["Content-Type"]; contentType != "" {
+			builder.WriteString(" \\\n  -H " + ShellQuote("Content-Type: "+contentType))
+		}
+		builder.WriteString(" \\\n  --data-raw " + ShellQuote(req.Body))
+	}
+
+	builder.WriteString(curlOptionFlags(req.Options))
+
+	return builder.String()
+}
+
+// parseCurlFormField reads a -F value, name=value or name=@path. File
+// attributes such as ;type= are left to be inferred from the path.
+func parseCurlFormField(value string, literal bool) (FormField, error) {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return FormField{}, fmt.Errorf("invalid form field %q", value)
+	}
+	if literal {
+		return FormField{Key: key, Value: val}, nil
+	}
+
+	if path, ok := strings.CutPrefix(val, "@"); ok {
+		path, _, _ = strings.Cut(path, ";")
+		return FormField{Key: key, Value: path, File: true}, nil
+	}
+	if strings.HasPrefix(val, "<") {
+		return FormField{}, fmt.Errorf("form field %q: reading a value from a file with < is not supported", key)
+	}
+	return FormField{Key: key, Value: val}, nil
+}
+
+// curlFormFlags renders the form as -F flags for multipart bodies and
+// --data-urlencode flags otherwise, where name@path sends the file contents.
+func curlFormFlags(req *Request) string {
+	var builder strings.Builder
+	for _, field := range req.Form {
+		switch {
+		case req.IsMultipart() && field.File:
+			builder.WriteString(" \\\n  -F " + ShellQuote(field.Key+"=@"+field.Value))
+		case req.IsMultipart():
+			builder.WriteString(" \\\n  --form-string " + ShellQuote(field.Key+"="+field.Value))
+		case field.File:
+			builder.WriteString(" \\\n  --data-urlencode " + ShellQuote(field.Key+"@"+field.Value))
+		default:
+			builder.WriteString(" \\\n  --data-urlencode " + ShellQuote(field.Key+"="+field.Value))
+		}
+	}
+	return builder.String()
+}
+
+// curlAuthFlags renders the auth as curl flags. OAuth2 is left out as curl
+// cannot fetch the token itself.
+func curlAuthFlags(auth *Auth) string {
+	if auth == nil {
+		return ""
+	}
+
+	switch auth.Type {
+	case AuthBasic:
+		return " \\\n  -u " + ShellQuote(auth.Username+":"+auth.Password)
+	case AuthDigest:
+		return " \\\n  --digest -u " + ShellQuote(auth.Username+":"+auth.Password)
+	case AuthBearer:
+		return " \\\n  --oauth2-bearer " + ShellQuote(auth.Token)
+	case AuthAPIKey:
+		if auth.In == APIKeyInHeader {
+			return " \\\n  -H " + ShellQuote(auth.Key+": "+auth.Value)
+		}
+	}
+	return ""
+}
+
+func curlOptionFlags(opts *ClientOptions) string {
+	if opts == nil {
+		return ""
+	}
+
+	var builder strings.Builder
+	if opts.Timeout > 0 {
+		builder.WriteString(" \\\n  --max-time " + strconv.FormatFloat(opts.Timeout.Seconds(), 'f', -1, 64))
+	}
+	if opts.MaxRedirects != nil && (opts.FollowRedirects == nil || *opts.FollowRedirects) {
+		fmt.Fprintf(&builder, " \\\n  -L --max-redirs %d", *opts.MaxRedirects)
+	}
+	if opts.Proxy != "" {
+		builder.WriteString(" \\\n  --proxy " + ShellQuote(opts.Proxy))
+	}
+	if opts.CACert != "" {
+		builder.WriteString(" \\\n  --cacert " + ShellQuote(opts.CACert))
+	}
+	if opts.ClientCert != "" {
+		builder.WriteString(" \\\n  --cert " + ShellQuote(opts.ClientCert))
+	}
+	if opts.ClientKey != "" {
+		builder.WriteString(" \\\n  --key " + ShellQuote(opts.ClientKey))
+	}
+	if opts.Insecure != nil && *opts.Insecure {
+		builder.WriteString(" \\\n  --insecure")
+	}
+	switch opts.HTTPVersion {
+	case "1.1":
+		builder.WriteString(" \\\n  --http1.1")
+	case "2":
+		builder.WriteString(" \\\n  --http2")
+	}
+	return builder.String()
+}
+
+func (req *Request) clientOptions() *ClientOptions {
+	if req.Options == nil {
+		req.Options = &ClientOptions{}
+	}
+	return req.Options
+}
+
+func setCurlHeader(req *Request, key, val string) {
+	if strings.EqualFold(key, "Content-Type") {
+		req.ContentType["Content-Type"] = val
+		return
+	}
+	req.Headers[key] = val
+}
+
+func urlencodeCurlData(value string) string {
+	if name, content, ok := strings.Cut(value, "="); ok {
+		return name + "=" + url.QueryEscape(content)
+	}
+	return url.QueryEscape(value)
+}
+
+func expandShortSwitches(arg string) []string {
+	var switches []string
+	for _, r := range arg[1:] {
+		flag := "-" + string(r)
+		if !curlIgnoredSwitches[flag] && flag != "-G" && flag != "-I" && flag != "-k" {
+			return nil
+		}
+		switches = append(switches, flag)
+	}
+	return switches
+}
+
+// ShellQuote quotes s as a single shell word.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// SplitShellWords splits a command line the way a POSIX shell would,
+// honouring single quotes, double quotes, backslash escapes and line
+// continuations.
+func SplitShellWords(command string) ([]string, error) {
+	var (
+		words   []string
+		current strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range command {
+		switch {
+		case escaped:
+			if r != '\n' {
+				current.WriteRune(r)
+				inWord = true
+			}
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				current.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+
+	return words, nil
+}
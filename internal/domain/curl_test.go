@@ -0,0 +1,321 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCurl(t *testing.T) {
+	cfg := &config.Config{App: config.AppConfig{DefaultPort: "8080"}}
+
+	tests := []struct {
+		name        string
+		command     string
+		method      string
+		url         string
+		body        string
+		contentType string
+		headers     map[string]string
+		params      map[string]string
+		expectError bool
+	}{
+		{
+			name:    "Simple GET",
+			command: "curl https://example.com/users",
+			method:  "GET",
+			url:     "https://example.com/users",
+		},
+		{
+			name: "POST with headers and data over several lines",
+			command: `curl -X POST 'https://example.com/users' \
+  -H 'Content-Type: application/json' \
+  -H "X-Token: abc" \
+  --data-raw '{"name":"it''s me"}'`,
+			method:      "POST",
+			url:         "https://example.com/users",
+			body:        `{"name":"its me"}`,
+			contentType: "application/json",
+			headers:     map[string]string{"X-Token": "abc"},
+		},
+		{
+			name:        "Data implies POST and form content type",
+			command:     "curl localhost:3000/login -d user=bob -d pass=secret",
+			method:      "POST",
+			url:         "http://localhost:3000/login",
+			body:        "user=bob&pass=secret",
+			contentType: "application/x-www-form-urlencoded",
+		},
+		{
+			name:        "JSON flag",
+			command:     `curl --json '{"a":1}' https://example.com`,
+			method:      "POST",
+			url:         "https://example.com",
+			body:        `{"a":1}`,
+			contentType: "application/json",
+			headers:     map[string]string{"Accept": "application/json"},
+		},
+		{
+			name:    "Query string and -G data become params",
+			command: "curl -G 'https://example.com/search?q=go&page=2' -d limit=10",
+			method:  "GET",
+			url:     "https://example.com/search",
+			params:  map[string]string{"q": "go", "page": "2", "limit": "10"},
+		},
+		{
+			name:    "Encoded query and -G --data-urlencode are decoded",
+			command: "curl -G 'https://example.com/search?path=a%2Fb%26c' --data-urlencode 'q=go & rust=1'",
+			method:  "GET",
+			url:     "https://example.com/search",
+			params:  map[string]string{"path": "a/b&c", "q": "go & rust=1"},
+		},
+		{
+			name:    "Repeated keys stay in the url",
+			command: "curl -G 'https://example.com/search?tag=a&tag=b%2Fc' -d page=2 -d page=3",
+			method:  "GET",
+			url:     "https://example.com/search?tag=a&tag=b%2Fc&page=2&page=3",
+		},
+		{
+			name:    "Attached method and combined switches",
+			command: "curl -sSL -XDELETE https://example.com/users/1",
+			method:  "DELETE",
+			url:     "https://example.com/users/1",
+		},
+		{
+			name:        "Not a curl command",
+			command:     "wget https://example.com",
+			expectError: true,
+		},
+		{
+			name:        "Missing url",
+			command:     "curl -X POST",
+			expectError: true,
+		},
+		{
+			name:        "Unterminated quote",
+			command:     "curl 'https://example.com",
+			expectError: true,
+		},
+		{
+			name:        "Unsupported option",
+			command:     "curl --upload-file x https://example.com",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := ParseCurl(tt.command, cfg)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.method, req.Method)
+			assert.Equal(t, tt.url, req.URL)
+			assert.Equal(t, tt.body, req.Body)
+			assert.Equal(t, tt.contentType, req.ContentType["Content-Type"])
+			assert.NotEmpty(t, req.Headers["User-Agent"])
+			for k, v := range tt.headers {
+				assert.Equal(t, v, req.Headers[k])
+			}
+			for k, v := range tt.params {
+				assert.Equal(t, v, req.Params[k])
+			}
+			if tt.params == nil {
+				assert.Empty(t, req.Params)
+			}
+		})
+	}
+}
+
+func TestToCurl(t *testing.T) {
+	req := &Request{
+		Method:      "POST",
+		URL:         "https://example.com/users",
+		ContentType: map[string]string{"Content-Type": "application/json"},
+		Body:        `{"name":"it's me"}`,
+		Params:      map[string]string{"page": "2"},
+		Headers: map[string]string{
+			"User-Agent": "Burrow/1.0.0(github.com/ManoloEsS/burrow)",
+			"X-Token":    "abc",
+		},
+	}
+
+	expected := `curl -X POST 'https://example.com/users?page=2' \
+  -H 'X-Token: abc' \
+  -H 'Content-Type: application/json' \
+  --data-raw '{"name":"it'\''s me"}'`
+	assert.Equal(t, expected, req.ToCurl())
+
+	cfg := &config.Config{App: config.AppConfig{DefaultPort: "8080"}}
+	parsed, err := ParseCurl(req.ToCurl(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, req.Method, parsed.Method)
+	assert.Equal(t, req.URL, parsed.URL)
+	assert.Equal(t, req.Body, parsed.Body)
+	assert.Equal(t, req.Params, parsed.Params)
+	assert.Equal(t, "abc", parsed.Headers["X-Token"])
+	assert.Equal(t, "application/json", parsed.ContentType["Content-Type"])
+
+	head := &Request{Method: "HEAD", URL: "https://example.com"}
+	assert.Equal(t, "curl -I 'https://example.com'", head.ToCurl())
+}
+
+func TestCurlClientOptions(t *testing.T) {
+	cfg := &config.Config{App: config.AppConfig{DefaultPort: "8080"}}
+
+	req, err := ParseCurl("curl -sk -m 2.5 -L --max-redirs 3 -x http://proxy:3128 --cacert ca.pem -E c.pem --key k.pem --http1.1 https://example.com", cfg)
+	require.NoError(t, err)
+
+	insecure, three := true, 3
+	assert.Equal(t, &ClientOptions{
+		Timeout:      2500 * time.Millisecond,
+		MaxRedirects: &three,
+		Proxy:        "http://proxy:3128",
+		CACert:       "ca.pem",
+		ClientCert:   "c.pem",
+		ClientKey:    "k.pem",
+		Insecure:     &insecure,
+		HTTPVersion:  "1.1",
+	}, req.Options)
+
+	expected := `curl 'https://example.com' \
+  --max-time 2.5 \
+  -L --max-redirs 3 \
+  --proxy 'http://proxy:3128' \
+  --cacert 'ca.pem' \
+  --cert 'c.pem' \
+  --key 'k.pem' \
+  --insecure \
+  --http1.1`
+	assert.Equal(t, expected, req.ToCurl())
+
+	parsed, err := ParseCurl(req.ToCurl(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, req.Options, parsed.Options)
+
+	_, err = ParseCurl("curl -m soon https://example.com", cfg)
+	assert.ErrorContains(t, err, "invalid max time")
+}
+
+func TestCurlAuth(t *testing.T) {
+	cfg := &config.Config{App: config.AppConfig{DefaultPort: "8080"}}
+
+	tests := []struct {
+		name    string
+		command string
+		auth    *Auth
+		curl    string
+	}{
+		{
+			name:    "basic",
+			command: "curl -u user:pass https://example.com",
+			auth:    &Auth{Type: AuthBasic, Username: "user", Password: "pass"},
+			curl:    "curl 'https://example.com' \\\n  -u 'user:pass'",
+		},
+		{
+			name:    "digest given after the credentials",
+			command: "curl -u user:pass --digest https://example.com",
+			auth:    &Auth{Type: AuthDigest, Username: "user", Password: "pass"},
+			curl:    "curl 'https://example.com' \\\n  --digest -u 'user:pass'",
+		},
+		{
+			name:    "bearer",
+			command: "curl --oauth2-bearer abc https://example.com",
+			auth:    &Auth{Type: AuthBearer, Token: "abc"},
+			curl:    "curl 'https://example.com' \\\n  --oauth2-bearer 'abc'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := ParseCurl(tt.command, cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tt.auth, req.Auth)
+			assert.Empty(t, req.Headers["Authorization"])
+
+			req.Headers = nil
+			assert.Equal(t, tt.curl, req.ToCurl())
+		})
+	}
+
+	apiKey := &Request{Method: "GET", URL: "https://example.com", Auth: &Auth{Type: AuthAPIKey, Key: "api_key", Value: "k1", In: APIKeyInQuery}}
+	assert.Equal(t, "curl 'https://example.com?api_key=k1'", apiKey.ToCurl())
+}
+
+func TestCurlForm(t *testing.T) {
+	cfg := &config.Config{App: config.AppConfig{DefaultPort: "8080"}}
+
+	req, err := ParseCurl(`curl -F name=burrow -F 'avatar=@./me.png;type=image/png' --form-string 'raw=@literal' https://example.com/upload`, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "POST", req.Method)
+	assert.Equal(t, ContentTypeMultipart, req.ContentType["Content-Type"])
+	assert.Equal(t, []FormField{
+		{Key: "name", Value: "burrow"},
+		{Key: "avatar", Value: "./me.png", File: true},
+		{Key: "raw", Value: "@literal"},
+	}, req.Form)
+
+	_, err = ParseCurl("curl -F name=burrow -d a=b https://example.com", cfg)
+	assert.Error(t, err)
+	_, err = ParseCurl("curl -F 'notes=<notes.txt' https://example.com", cfg)
+	assert.Error(t, err)
+
+	multipart := &Request{
+		Method:      "POST",
+		URL:         "https://example.com/upload",
+		ContentType: map[string]string{"Content-Type": ContentTypeMultipart},
+		Form:        []FormField{{Key: "name", Value: "burrow"}, {Key: "avatar", Value: "me.png", File: true}},
+	}
+	assert.Equal(t, `curl -X POST 'https://example.com/upload' \
+  --form-string 'name=burrow' \
+  -F 'avatar=@me.png'`, multipart.ToCurl())
+
+	parsed, err := ParseCurl(multipart.ToCurl(), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, multipart.Form, parsed.Form)
+
+	form := &Request{
+		Method:      "POST",
+		URL:         "https://example.com/login",
+		ContentType: map[string]string{"Content-Type": ContentTypeForm},
+		Form:        []FormField{{Key: "user", Value: "bob smith"}, {Key: "key", Value: "key.pem", File: true}},
+	}
+	assert.Equal(t, `curl -X POST 'https://example.com/login' \
+  --data-urlencode 'user=bob smith' \
+  --data-urlencode 'key@key.pem'`, form.ToCurl())
+}
+
+func TestCurlBodyFile(t *testing.T) {
+	cfg := &config.Config{App: config.AppConfig{DefaultPort: "8080"}}
+
+	req, err := ParseCurl(`curl --data-binary @fixtures/blob.pb -H 'Content-Type: application/x-protobuf' https://example.com/items`, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "POST", req.Method)
+	assert.Equal(t, "fixtures/blob.pb", req.BodyFile)
+	assert.Empty(t, req.Body)
+	assert.Equal(t, "application/x-protobuf", req.ContentType["Content-Type"])
+
+	expected := `curl -X POST 'https://example.com/items' \
+  -H 'Content-Type: application/x-protobuf' \
+  --data-binary '@fixtures/blob.pb'`
+	assert.Equal(t, expected, req.ToCurl())
+
+	raw, err := ParseCurl(`curl --data-raw @literal https://example.com`, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "@literal", raw.Body)
+	assert.Empty(t, raw.BodyFile)
+
+	for _, command := range []string{
+		"curl -d @data.json -d a=b https://example.com",
+		"curl -d @- https://example.com",
+	} {
+		_, err := ParseCurl(command, cfg)
+		assert.Error(t, err, command)
+	}
+}
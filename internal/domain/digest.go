@@ -0,0 +1,140 @@
+package domain
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"slices"
+	"strings"
+)
+
+// DigestChallenge is the WWW-Authenticate challenge of a server using digest
+// authentication (RFC 7616).
+type DigestChallenge struct {
+	Realm     string
+	Nonce     string
+	Opaque    string
+	Algorithm string
+	QOP       []string
+}
+
+// ParseDigestChallenge reads a WWW-Authenticate header value, reporting false
+// when it is not a digest challenge.
+func ParseDigestChallenge(header string) (*DigestChallenge, bool) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	if !strings.EqualFold(scheme, "Digest") {
+		return nil, false
+	}
+
+	params := parseAuthParams(rest)
+	challenge := &DigestChallenge{
+		Realm:     params["realm"],
+		Nonce:     params["nonce"],
+		Opaque:    params["opaque"],
+		Algorithm: params["algorithm"],
+	}
+	for qop := range strings.SplitSeq(params["qop"], ",") {
+		if qop = strings.TrimSpace(qop); qop != "" {
+			challenge.QOP = append(challenge.QOP, qop)
+		}
+	}
+	if challenge.Nonce == "" {
+		return nil, false
+	}
+	return challenge, true
+}
+
+// Authorization computes the Authorization header answering the challenge
+// for the request method and uri. nc counts the requests made with the same
+// nonce, starting at 1.
+func (c *DigestChallenge) Authorization(username, password, method, uri string, nc int, cnonce string) (string, error) {
+	algorithm := c.Algorithm
+	if algorithm == "" {
+		algorithm = "MD5"
+	}
+
+	var newHash func() hash.Hash
+	switch strings.TrimSuffix(strings.ToUpper(algorithm), "-SESS") {
+	case "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return "", fmt.Errorf("unsupported digest algorithm %q", algorithm)
+	}
+	digest := func(parts ...string) string {
+		h := newHash()
+		h.Write([]byte(strings.Join(parts, ":")))
+		return hex.EncodeToString(h.Sum(nil))
+	}
+
+	qop := ""
+	if len(c.QOP) > 0 {
+		if !slices.Contains(c.QOP, "auth") {
+			return "", fmt.Errorf("unsupported digest qop %q", strings.Join(c.QOP, ","))
+		}
+		qop = "auth"
+	}
+	count := fmt.Sprintf("%08x", nc)
+
+	ha1 := digest(username, c.Realm, password)
+	if strings.HasSuffix(strings.ToUpper(algorithm), "-SESS") {
+		ha1 = digest(ha1, c.Nonce, cnonce)
+	}
+	ha2 := digest(method, uri)
+
+	response := digest(ha1, c.Nonce, ha2)
+	if qop != "" {
+		response = digest(ha1, c.Nonce, count, cnonce, qop, ha2)
+	}
+
+	fields := []string{
+		fmt.Sprintf("username=%q", username),
+		fmt.Sprintf("realm=%q", c.Realm),
+		fmt.Sprintf("nonce=%q", c.Nonce),
+		fmt.Sprintf("uri=%q", uri),
+		"algorithm=" + algorithm,
+		fmt.Sprintf("response=%q", response),
+	}
+	if qop != "" {
+		fields = append(fields, "qop="+qop, "nc="+count, fmt.Sprintf("cnonce=%q", cnonce))
+	}
+	if c.Opaque != "" {
+		fields = append(fields, fmt.Sprintf("opaque=%q", c.Opaque))
+	}
+	return "Digest " + strings.Join(fields, ", "), nil
+}
+
+// parseAuthParams splits comma separated key=value pairs whose values may be
+// quoted strings containing commas.
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimLeft(s, ", ") {
+		key, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			var builder strings.Builder
+			i := 1
+			for ; i < len(rest) && rest[i] != '"'; i++ {
+				if rest[i] == '\\' && i+1 < len(rest) {
+					i++
+				}
+				builder.WriteByte(rest[i])
+			}
+			value, s = builder.String(), rest[min(i+1, len(rest)):]
+		} else {
+			value, s, _ = strings.Cut(rest, ",")
+			value = strings.TrimSpace(value)
+		}
+		params[key] = value
+	}
+	return params
+}
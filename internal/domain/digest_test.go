@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDigestChallenge(t *testing.T) {
+	challenge, ok := ParseDigestChallenge(`Digest realm="http-auth@example.org", qop="auth, auth-int", algorithm=SHA-256, nonce="7ypf/xlj9XXwfDPEoM4URrv/xwf94BcCAzFZH4GiTo0v", opaque="FQhe/qaU925kfnzjCev0ciny7QMkPqMAFRtzCUYo5tdS"`)
+	require.True(t, ok)
+	assert.Equal(t, &DigestChallenge{
+		Realm:     "http-auth@example.org",
+		Nonce:     "7ypf/xlj9XXwfDPEoM4URrv/xwf94BcCAzFZH4GiTo0v",
+		Opaque:    "FQhe/qaU925kfnzjCev0ciny7QMkPqMAFRtzCUYo5tdS",
+		Algorithm: "SHA-256",
+		QOP:       []string{"auth", "auth-int"},
+	}, challenge)
+
+	_, ok = ParseDigestChallenge(`Basic realm="x"`)
+	assert.False(t, ok)
+	_, ok = ParseDigestChallenge(`Digest realm="x"`)
+	assert.False(t, ok)
+}
+
+func TestDigestAuthorization(t *testing.T) {
+	// the examples of RFC 7616 section 3.9.1
+	tests := []struct {
+		algorithm string
+		response  string
+	}{
+		{algorithm: "MD5", response: "8ca523f5e9506fed4657c9700eebdbec"},
+		{algorithm: "SHA-256", response: "753927fa0e85d155564e2e272a28d1802ca10daf4496794697cf8db5856cb6c1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			challenge := &DigestChallenge{
+				Realm:     "http-auth@example.org",
+				Nonce:     "7ypf/xlj9XXwfDPEoM4URrv/xwf94BcCAzFZH4GiTo0v",
+				Opaque:    "FQhe/qaU925kfnzjCev0ciny7QMkPqMAFRtzCUYo5tdS",
+				Algorithm: tt.algorithm,
+				QOP:       []string{"auth", "auth-int"},
+			}
+
+			header, err := challenge.Authorization("Mufasa", "Circle of Life", "GET", "/dir/index.html", 1, "f2/wE4q74E6zIJEtWaHKaf5wv/H5QzzpXusqGemxURZJ")
+			require.NoError(t, err)
+
+			params := parseAuthParams(header[len("Digest "):])
+			assert.Equal(t, tt.response, params["response"])
+			assert.Equal(t, "00000001", params["nc"])
+			assert.Equal(t, "auth", params["qop"])
+			assert.Equal(t, challenge.Opaque, params["opaque"])
+		})
+	}
+
+	_, err := (&DigestChallenge{Nonce: "n", Algorithm: "SHA-512"}).Authorization("u", "p", "GET", "/", 1, "c")
+	assert.ErrorContains(t, err, "unsupported digest algorithm")
+	_, err = (&DigestChallenge{Nonce: "n", QOP: []string{"auth-int"}}).Authorization("u", "p", "GET", "/", 1, "c")
+	assert.ErrorContains(t, err, "unsupported digest qop")
+}
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"errors"
+	"maps"
+	"regexp"
+	"strings"
+)
+
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.\-]+)\s*\}\}`)
+
+type Environment struct {
+	Name      string            `json:"name"`
+	Variables map[string]string `json:"variables"`
+}
+
+func NewEnvironment() *Environment {
+	return &Environment{
+		Variables: make(map[string]string),
+	}
+}
+
+func (env *Environment) ParseName(nameStr string) error {
+	name := strings.ToLower(strings.TrimSpace(nameStr))
+	if name == "" {
+		return errors.New("environment name required")
+	}
+	env.Name = name
+	return nil
+}
+
+func (env *Environment) ParseVariables(varsStr string) error {
+	if env.Variables == nil {
+		env.Variables = make(map[string]string)
+	}
+
+	if varsStr == "" {
+		return nil
+	}
+
+	vars := strings.FieldsFunc(varsStr, func(r rune) bool {
+		return r == ',' || r == '\n'
+	})
+	for _, v := range vars {
+		trimmedVar := strings.TrimSpace(v)
+		parsedVar := strings.SplitN(trimmedVar, ":", 2)
+		if len(parsedVar) == 2 {
+			env.Variables[strings.TrimSpace(parsedVar[0])] = strings.TrimSpace(parsedVar[1])
+		}
+	}
+	return nil
+}
+
+func (env *Environment) BuildEnvironment(name, variables string) error {
+	err := env.ParseName(name)
+	if err != nil {
+		return err
+	}
+
+	return env.ParseVariables(variables)
+}
+
+func (env *Environment) Lookup(name string) (string, bool) {
+	if env == nil {
+		return "", false
+	}
+	val, ok := env.Variables[name]
+	return val, ok
+}
+
+// ExpandVariables replaces every {{name}} placeholder in s using lookup.
+// Placeholders lookup cannot resolve are left untouched.
+func ExpandVariables(s string, lookup func(string) (string, bool)) string {
+	if lookup == nil || !strings.Contains(s, "{{") {
+		return s
+	}
+
+	return placeholderPattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := placeholderPattern.FindStringSubmatch(match)[1]
+		if val, ok := lookup(name); ok {
+			return val
+		}
+		return match
+	})
+}
+
+// WithVariables returns a copy of the request with placeholders expanded in
+// the URL, headers, params, body, form, GraphQL query and variables and body
+// file path. The receiver is left unchanged so saved requests keep their
+// templates.
+func (req *Request) WithVariables(lookup func(string) (string, bool)) *Request {
+	resolved := *req
+	resolved.URL = ExpandVariables(req.URL, lookup)
+	resolved.Body = ExpandVariables(req.Body, lookup)
+	resolved.Form = expandForm(req.Form, lookup)
+	resolved.Messages = expandMessages(req.Messages, lookup)
+	resolved.BodyFile = ExpandVariables(req.BodyFile, lookup)
+	if req.GraphQL != nil {
+		resolved.GraphQL = &GraphQLBody{
+			Query:         ExpandVariables(req.GraphQL.Query, lookup),
+			Variables:     ExpandVariables(req.GraphQL.Variables, lookup),
+			OperationName: req.GraphQL.OperationName,
+		}
+	}
+	resolved.ContentType = maps.Clone(req.ContentType)
+	resolved.Headers = expandMap(req.Headers, lookup)
+	resolved.Params = expandMap(req.Params, lookup)
+	resolved.Auth = req.Auth.withVariables(lookup)
+
+	return &resolved
+}
+
+func expandForm(fields []FormField, lookup func(string) (string, bool)) []FormField {
+	if fields == nil {
+		return nil
+	}
+
+	expanded := make([]FormField, len(fields))
+	for i, field := range fields {
+		field.Key = ExpandVariables(field.Key, lookup)
+		field.Value = ExpandVariables(field.Value, lookup)
+		expanded[i] = field
+	}
+	return expanded
+}
+
+func expandMessages(messages []WSMessage, lookup func(string) (string, bool)) []WSMessage {
+	if messages == nil {
+		return nil
+	}
+
+	expanded := make([]WSMessage, len(messages))
+	for i, msg := range messages {
+		msg.Text = ExpandVariables(msg.Text, lookup)
+		expanded[i] = msg
+	}
+	return expanded
+}
+
+func expandMap(m map[string]string, lookup func(string) (string, bool)) map[string]string {
+	if m == nil {
+		return nil
+	}
+
+	expanded := make(map[string]string, len(m))
+	for k, v := range m {
+		expanded[ExpandVariables(k, lookup)] = ExpandVariables(v, lookup)
+	}
+	return expanded
+}
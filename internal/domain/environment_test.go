@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVariables(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string]string
+	}{
+		{
+			name:     "Parse single variable",
+			input:    "host:localhost",
+			expected: map[string]string{"host": "localhost"},
+		},
+		{
+			name:     "Parse variable with url value",
+			input:    "base_url:http://localhost:3000, token:abc",
+			expected: map[string]string{"base_url": "http://localhost:3000", "token": "abc"},
+		},
+		{
+			name:     "Parse newline separated variables",
+			input:    "host:localhost\nport:3000\n",
+			expected: map[string]string{"host": "localhost", "port": "3000"},
+		},
+		{
+			name:     "Parse empty string",
+			input:    "",
+			expected: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := &Environment{}
+			err := env.ParseVariables(tt.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, env.Variables)
+		})
+	}
+}
+
+func TestBuildEnvironment(t *testing.T) {
+	env := NewEnvironment()
+	err := env.BuildEnvironment("  Dev ", "port:3000")
+	assert.NoError(t, err)
+	assert.Equal(t, "dev", env.Name)
+	assert.Equal(t, map[string]string{"port": "3000"}, env.Variables)
+
+	err = NewEnvironment().BuildEnvironment("", "port:3000")
+	assert.Error(t, err)
+}
+
+func TestExpandVariables(t *testing.T) {
+	env := &Environment{Variables: map[string]string{"host": "localhost", "port": "3000"}}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Expand single placeholder",
+			input:    "http://{{host}}",
+			expected: "http://localhost",
+		},
+		{
+			name:     "Expand multiple placeholders",
+			input:    "http://{{host}}:{{ port }}/users",
+			expected: "http://localhost:3000/users",
+		},
+		{
+			name:     "Leave unknown placeholder",
+			input:    "{{missing}}/users",
+			expected: "{{missing}}/users",
+		},
+		{
+			name:     "No placeholders",
+			input:    "plain text",
+			expected: "plain text",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ExpandVariables(tt.input, env.Lookup))
+		})
+	}
+}
+
+func TestWithVariables(t *testing.T) {
+	env := &Environment{Variables: map[string]string{"base": "http://localhost:3000", "token": "secret"}}
+	req := &Request{
+		Method:  "POST",
+		URL:     "{{base}}/users",
+		Headers: map[string]string{"Authorization": "Bearer {{token}}"},
+		Params:  map[string]string{"key": "{{token}}"},
+		Body:    `{"token":"{{token}}"}`,
+	}
+
+	resolved := req.WithVariables(env.Lookup)
+
+	assert.Equal(t, "http://localhost:3000/users", resolved.URL)
+	assert.Equal(t, "Bearer secret", resolved.Headers["Authorization"])
+	assert.Equal(t, "secret", resolved.Params["key"])
+	assert.Equal(t, `{"token":"secret"}`, resolved.Body)
+
+	assert.Equal(t, "{{base}}/users", req.URL)
+	assert.Equal(t, "Bearer {{token}}", req.Headers["Authorization"])
+}
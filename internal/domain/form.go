@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	ContentTypeForm      = "application/x-www-form-urlencoded"
+	ContentTypeMultipart = "multipart/form-data"
+)
+
+// FormField is a field of a form body. The value of a file field is the path
+// of the file sent in its place.
+type FormField struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	File  bool   `json:"file,omitempty"`
+}
+
+// ParseForm reads one key:value field per line. A value starting with @ is
+// the path of a file, as in avatar:@./me.png.
+func ParseForm(formStr string) ([]FormField, error) {
+	var fields []FormField
+	for i, line := range strings.Split(formStr, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("form line %d: expected key:value", i+1)
+		}
+
+		field := FormField{Key: key, Value: strings.TrimSpace(value)}
+		if path, ok := strings.CutPrefix(field.Value, "@"); ok {
+			if path == "" {
+				return nil, fmt.Errorf("form line %d: file path required after @", i+1)
+			}
+			field.Value, field.File = path, true
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// FormatForm writes fields in the format read by ParseForm.
+func FormatForm(fields []FormField) string {
+	lines := make([]string, 0, len(fields))
+	for _, field := range fields {
+		value := field.Value
+		if field.File {
+			value = "@" + value
+		}
+		lines = append(lines, field.Key+":"+value)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FormFromBody reads the fields of a URL encoded body, for requests saved or
+// imported with the form already encoded.
+func FormFromBody(body string) ([]FormField, error) {
+	var fields []FormField
+	for _, pair := range strings.Split(body, "&") {
+		if pair == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(pair, "=")
+		key, err := url.QueryUnescape(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err = url.QueryUnescape(value)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, FormField{Key: key, Value: value})
+	}
+	return fields, nil
+}
+
+func (req *Request) IsMultipart() bool {
+	return strings.HasPrefix(req.ContentType["Content-Type"], ContentTypeMultipart)
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseForm(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    []FormField
+		expectError bool
+	}{
+		{
+			name:  "Text and file fields",
+			input: "name: burrow\n\navatar:@./me.png\nnote:a:b",
+			expected: []FormField{
+				{Key: "name", Value: "burrow"},
+				{Key: "avatar", Value: "./me.png", File: true},
+				{Key: "note", Value: "a:b"},
+			},
+		},
+		{
+			name:     "Empty value",
+			input:    "empty:",
+			expected: []FormField{{Key: "empty", Value: ""}},
+		},
+		{name: "Empty form", input: "  \n"},
+		{name: "Missing separator", input: "name burrow", expectError: true},
+		{name: "Missing key", input: ":burrow", expectError: true},
+		{name: "Missing file path", input: "avatar:@", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, err := ParseForm(tt.input)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, fields)
+
+			reparsed, err := ParseForm(FormatForm(fields))
+			require.NoError(t, err)
+			assert.Equal(t, fields, reparsed)
+		})
+	}
+}
+
+func TestFormFromBody(t *testing.T) {
+	fields, err := FormFromBody("user=bob&note=hello+world%21&empty=&flag")
+	require.NoError(t, err)
+	assert.Equal(t, []FormField{
+		{Key: "user", Value: "bob"},
+		{Key: "note", Value: "hello world!"},
+		{Key: "empty"},
+		{Key: "flag"},
+	}, fields)
+
+	_, err = FormFromBody("bad=%zz")
+	assert.Error(t, err)
+}
+
+func TestWithVariablesForm(t *testing.T) {
+	req := &Request{Form: []FormField{{Key: "token", Value: "{{token}}"}, {Key: "file", Value: "{{dir}}/a.txt", File: true}}}
+	vars := map[string]string{"token": "abc", "dir": "/tmp"}
+
+	resolved := req.WithVariables(func(name string) (string, bool) {
+		val, ok := vars[name]
+		return val, ok
+	})
+
+	assert.Equal(t, []FormField{{Key: "token", Value: "abc"}, {Key: "file", Value: "/tmp/a.txt", File: true}}, resolved.Form)
+	assert.Equal(t, "{{token}}", req.Form[0].Value)
+}
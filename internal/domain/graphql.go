@@ -0,0 +1,443 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// GraphQLBody is the body of a GraphQL request, sent as the JSON of its
+// query, variables and operation name.
+type GraphQLBody struct {
+	Query string `json:"query"`
+	// Variables is the text of a JSON object, kept as written so it can be
+	// edited and hold placeholders.
+	Variables     string `json:"variables,omitempty"`
+	OperationName string `json:"operation_name,omitempty"`
+}
+
+// Payload renders the body sent to the server.
+func (g *GraphQLBody) Payload() ([]byte, error) {
+	variables, err := parseGraphQLVariables(g.Variables)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		Query         string          `json:"query"`
+		Variables     json.RawMessage `json:"variables,omitempty"`
+		OperationName string          `json:"operationName,omitempty"`
+	}{g.Query, variables, g.OperationName})
+}
+
+func parseGraphQLVariables(text string) (json.RawMessage, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+	var variables map[string]any
+	if err := json.Unmarshal([]byte(text), &variables); err != nil || variables == nil {
+		return nil, errors.New("GraphQL variables must be a JSON object")
+	}
+	return json.RawMessage(text), nil
+}
+
+// ParseGraphQLVariables sets the variables of a GraphQL request, which must
+// be a JSON object.
+func (req *Request) ParseGraphQLVariables(text string) error {
+	if req.GraphQL == nil {
+		return nil
+	}
+	if _, err := parseGraphQLVariables(text); err != nil {
+		return err
+	}
+	req.GraphQL.Variables = strings.TrimSpace(text)
+	return nil
+}
+
+// WithGraphQLBody returns a copy of the request carrying its GraphQL query
+// as it is sent, in the query string of a GET and as a JSON body otherwise.
+func (req *Request) WithGraphQLBody() (*Request, error) {
+	if req.GraphQL == nil {
+		return req, nil
+	}
+
+	sent := req.Clone()
+	if req.Method == "GET" {
+		variables, err := parseGraphQLVariables(req.GraphQL.Variables)
+		if err != nil {
+			return nil, err
+		}
+		if sent.Params == nil {
+			sent.Params = make(map[string]string)
+		}
+		sent.Params["query"] = req.GraphQL.Query
+		if variables != nil {
+			sent.Params["variables"] = string(variables)
+		}
+		if req.GraphQL.OperationName != "" {
+			sent.Params["operationName"] = req.GraphQL.OperationName
+		}
+		sent.Body = ""
+		return sent, nil
+	}
+
+	payload, err := req.GraphQL.Payload()
+	if err != nil {
+		return nil, err
+	}
+	sent.Body = string(payload)
+	if sent.ContentType == nil {
+		sent.ContentType = make(map[string]string)
+	}
+	sent.ContentType["Content-Type"] = "application/json"
+	return sent, nil
+}
+
+// GraphQLResult is a GraphQL response, its data apart from its errors.
+type GraphQLResult struct {
+	Data   json.RawMessage `json:"data,omitempty"`
+	Errors []GraphQLError  `json:"errors,omitempty"`
+}
+
+type GraphQLError struct {
+	Message   string            `json:"message"`
+	Path      []any             `json:"path,omitempty"`
+	Locations []GraphQLLocation `json:"locations,omitempty"`
+}
+
+type GraphQLLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// String describes the error with where it happened, such as
+// "not found at user.posts.0 (line 2, column 3)".
+func (e GraphQLError) String() string {
+	text := e.Message
+	if len(e.Path) > 0 {
+		parts := make([]string, len(e.Path))
+		for i, part := range e.Path {
+			parts[i] = fmt.Sprint(part)
+		}
+		text += " at " + strings.Join(parts, ".")
+	}
+	if len(e.Locations) > 0 {
+		text += fmt.Sprintf(" (line %d, column %d)", e.Locations[0].Line, e.Locations[0].Column)
+	}
+	return text
+}
+
+// ParseGraphQLResult reads a GraphQL response body, nil when it is not one.
+func ParseGraphQLResult(body string) *GraphQLResult {
+	var result GraphQLResult
+	if err := json.Unmarshal([]byte(body), &result); err != nil {
+		return nil
+	}
+	if result.Data == nil && result.Errors == nil {
+		return nil
+	}
+	if bytes.Equal(result.Data, []byte("null")) {
+		result.Data = nil
+	}
+	return &result
+}
+
+// DataString renders the data indented, empty when there is none.
+func (r *GraphQLResult) DataString() string {
+	if len(r.Data) == 0 {
+		return ""
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, r.Data, "", "  "); err != nil {
+		return string(r.Data)
+	}
+	return indented.String()
+}
+
+// GraphQLIntrospectionQuery asks a server for the types of its schema and
+// their fields, enough to complete the fields of a query.
+const GraphQLIntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types {
+      kind
+      name
+      fields(includeDeprecated: true) {
+        name
+        description
+        args { name }
+        type { ...TypeRef }
+      }
+    }
+  }
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } }
+}`
+
+// GraphQLSchema is the part of a schema used to complete queries.
+type GraphQLSchema struct {
+	QueryType        string
+	MutationType     string
+	SubscriptionType string
+	Types            map[string]*GraphQLType
+}
+
+type GraphQLType struct {
+	Name   string
+	Kind   string
+	Fields []GraphQLField
+}
+
+type GraphQLField struct {
+	Name        string
+	Description string
+	Args        []string
+	// Type is the type as written in the schema, such as [User!]!, and
+	// TypeName the named type inside it.
+	Type     string
+	TypeName string
+}
+
+type introspectionTypeRef struct {
+	Kind   string                `json:"kind"`
+	Name   string                `json:"name"`
+	OfType *introspectionTypeRef `json:"ofType"`
+}
+
+// render writes the reference as in the schema and returns its named type.
+func (t *introspectionTypeRef) render() (string, string) {
+	if t == nil {
+		return "", ""
+	}
+	inner, name := t.OfType.render()
+	switch t.Kind {
+	case "NON_NULL":
+		return inner + "!", name
+	case "LIST":
+		return "[" + inner + "]", name
+	default:
+		return t.Name, t.Name
+	}
+}
+
+// ParseGraphQLSchema reads the response to GraphQLIntrospectionQuery.
+func ParseGraphQLSchema(body []byte) (*GraphQLSchema, error) {
+	type named struct {
+		Name string `json:"name"`
+	}
+	var response struct {
+		Data *struct {
+			Schema struct {
+				QueryType        *named `json:"queryType"`
+				MutationType     *named `json:"mutationType"`
+				SubscriptionType *named `json:"subscriptionType"`
+				Types            []struct {
+					Kind   string `json:"kind"`
+					Name   string `json:"name"`
+					Fields []struct {
+						Name        string                `json:"name"`
+						Description string                `json:"description"`
+						Args        []named               `json:"args"`
+						Type        *introspectionTypeRef `json:"type"`
+					} `json:"fields"`
+				} `json:"types"`
+			} `json:"__schema"`
+		} `json:"data"`
+		Errors []GraphQLError `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("invalid introspection response: %w", err)
+	}
+	if response.Data == nil || response.Data.Schema.QueryType == nil {
+		if len(response.Errors) > 0 {
+			return nil, fmt.Errorf("introspection failed: %s", response.Errors[0])
+		}
+		return nil, errors.New("introspection failed: the response has no schema")
+	}
+
+	raw := response.Data.Schema
+	schema := &GraphQLSchema{
+		QueryType: raw.QueryType.Name,
+		Types:     make(map[string]*GraphQLType, len(raw.Types)),
+	}
+	if raw.MutationType != nil {
+		schema.MutationType = raw.MutationType.Name
+	}
+	if raw.SubscriptionType != nil {
+		schema.SubscriptionType = raw.SubscriptionType.Name
+	}
+	for _, t := range raw.Types {
+		gqlType := &GraphQLType{Name: t.Name, Kind: t.Kind}
+		for _, f := range t.Fields {
+			field := GraphQLField{Name: f.Name, Description: f.Description}
+			field.Type, field.TypeName = f.Type.render()
+			for _, arg := range f.Args {
+				field.Args = append(field.Args, arg.Name)
+			}
+			gqlType.Fields = append(gqlType.Fields, field)
+		}
+		schema.Types[t.Name] = gqlType
+	}
+	return schema, nil
+}
+
+// Complete returns the fields that can be written at offset in query,
+// those of the selection set the offset is in that start with the word
+// being typed, along with that word.
+func (s *GraphQLSchema) Complete(query string, offset int) (string, []GraphQLField) {
+	offset = min(max(offset, 0), len(query))
+	start := offset
+	for start > 0 && isGraphQLNameByte(query[start-1]) {
+		start--
+	}
+	prefix := query[start:offset]
+
+	typeName, ok := s.selectionType(query[:start])
+	if !ok {
+		return prefix, nil
+	}
+	gqlType := s.Types[typeName]
+	if gqlType == nil {
+		return prefix, nil
+	}
+
+	var fields []GraphQLField
+	for _, field := range gqlType.Fields {
+		if strings.HasPrefix(strings.ToLower(field.Name), strings.ToLower(prefix)) {
+			fields = append(fields, field)
+		}
+	}
+	return prefix, fields
+}
+
+// selectionType follows the selection sets opened in query to the type of
+// the innermost one still open, false when query ends outside of one or
+// inside arguments.
+func (s *GraphQLSchema) selectionType(query string) (string, bool) {
+	var (
+		stack   []string
+		parens  int
+		field   string // the last field named in the open selection set
+		pending string // the type of the next selection set opened
+		skip    bool   // the next name is not a field
+		on      bool   // the next name is a type condition
+	)
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '#':
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+		case c == '"':
+			i = skipGraphQLString(query, i)
+		case c == '(':
+			parens++
+		case c == ')':
+			parens = max(parens-1, 0)
+		case parens > 0:
+		case c == '@' || c == '$':
+			skip = true
+		case c == '{':
+			typeName := pending
+			if typeName == "" && len(stack) == 0 {
+				typeName = s.QueryType
+			}
+			if typeName == "" && len(stack) > 0 {
+				typeName = s.fieldType(stack[len(stack)-1], field)
+			}
+			stack = append(stack, typeName)
+			field, pending = "", ""
+		case c == '}':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			field, pending = "", ""
+		case isGraphQLNameByte(c):
+			end := i
+			for end < len(query) && isGraphQLNameByte(query[end]) {
+				end++
+			}
+			name := query[i:end]
+			i = end - 1
+			switch {
+			case skip:
+				skip = false
+			case on:
+				pending, on = name, false
+			case name == "on":
+				on = true
+			case len(stack) == 0:
+				switch name {
+				case "query":
+					pending = s.QueryType
+				case "mutation":
+					pending = s.MutationType
+				case "subscription":
+					pending = s.SubscriptionType
+				}
+			default:
+				field, pending = name, ""
+			}
+		}
+	}
+	if parens > 0 || len(stack) == 0 {
+		return "", false
+	}
+	return stack[len(stack)-1], true
+}
+
+func (s *GraphQLSchema) fieldType(typeName, fieldName string) string {
+	gqlType := s.Types[typeName]
+	if gqlType == nil {
+		return ""
+	}
+	for _, field := range gqlType.Fields {
+		if field.Name == fieldName {
+			return field.TypeName
+		}
+	}
+	return ""
+}
+
+// skipGraphQLString returns the index of the quote closing the string, or
+// block string, opened at i.
+func skipGraphQLString(query string, i int) int {
+	if strings.HasPrefix(query[i:], `"""`) {
+		if end := strings.Index(query[i+3:], `"""`); end >= 0 {
+			return i + 3 + end + 2
+		}
+		return len(query)
+	}
+	for i++; i < len(query); i++ {
+		switch query[i] {
+		case '\\':
+			i++
+		case '"', '\n':
+			return i
+		}
+	}
+	return i
+}
+
+func isGraphQLNameByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// Signature describes the field as in the schema, such as
+// user(id): User!.
+func (f GraphQLField) Signature() string {
+	name := f.Name
+	if len(f.Args) > 0 {
+		name += "(" + strings.Join(f.Args, ", ") + ")"
+	}
+	return name + ": " + f.Type
+}
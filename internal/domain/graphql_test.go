@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildGraphQLRequest(t *testing.T) {
+	cfg := &config.Config{App: config.AppConfig{DefaultPort: "8080"}}
+
+	req := NewRequest()
+	require.NoError(t, req.BuildRequest("", "POST", "/graphql", "", "", "GraphQL", "query($id: ID!) { user(id: $id) { name } }", cfg))
+	require.NoError(t, req.ParseGraphQLVariables(`{"id": "{{userId}}"}`))
+	assert.Equal(t, "application/json", req.ContentType["Content-Type"])
+	assert.Empty(t, req.Body)
+
+	resolved := req.WithVariables(func(string) (string, bool) { return "7", true })
+	assert.Equal(t, `{"id": "{{userId}}"}`, req.GraphQL.Variables)
+
+	sent, err := resolved.WithGraphQLBody()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"query": "query($id: ID!) { user(id: $id) { name } }", "variables": {"id": "7"}}`, sent.Body)
+
+	resolved.Method = "GET"
+	sent, err = resolved.WithGraphQLBody()
+	require.NoError(t, err)
+	assert.Empty(t, sent.Body)
+	assert.Equal(t, "query($id: ID!) { user(id: $id) { name } }", sent.Params["query"])
+	assert.Equal(t, `{"id": "7"}`, sent.Params["variables"])
+	assert.Empty(t, resolved.Params)
+
+	assert.ErrorContains(t, req.ParseGraphQLVariables(`["id"]`), "must be a JSON object")
+	assert.ErrorContains(t, NewRequest().BuildRequest("", "POST", "/graphql", "", "", "GraphQL", " ", cfg), "query required")
+}
+
+func TestGraphQLRequestToCurl(t *testing.T) {
+	req := &Request{Method: "POST", URL: "https://example.com/graphql", GraphQL: &GraphQLBody{Query: "{ me { id } }"}}
+	assert.Equal(t, `curl -X POST 'https://example.com/graphql' \
+  -H 'Content-Type: application/json' \
+  --data-raw '{"query":"{ me { id } }"}'`, req.ToCurl())
+}
+
+func TestParseGraphQLResult(t *testing.T) {
+	result := ParseGraphQLResult(`{"data": {"user": null}, "errors": [{"message": "not found", "path": ["user", 0], "locations": [{"line": 2, "column": 3}]}]}`)
+	require.NotNil(t, result)
+	assert.Equal(t, "{\n  \"user\": null\n}", result.DataString())
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "not found at user.0 (line 2, column 3)", result.Errors[0].String())
+
+	assert.Empty(t, ParseGraphQLResult(`{"data": null, "errors": [{"message": "boom"}]}`).DataString())
+	assert.Nil(t, ParseGraphQLResult(`{"id": 1}`))
+	assert.Nil(t, ParseGraphQLResult(`not json`))
+
+	resp := &Response{StatusCode: 200, GraphQL: result}
+	assert.False(t, resp.Success())
+}
+
+const introspectionResponse = `{"data": {"__schema": {
+  "queryType": {"name": "Query"},
+  "mutationType": {"name": "Mutation"},
+  "subscriptionType": null,
+  "types": [
+    {"kind": "OBJECT", "name": "Query", "fields": [
+      {"name": "user", "args": [{"name": "id"}], "type": {"kind": "OBJECT", "name": "User"}},
+      {"name": "users", "args": [], "type": {"kind": "NON_NULL", "ofType": {"kind": "LIST", "ofType": {"kind": "NON_NULL", "ofType": {"kind": "OBJECT", "name": "User"}}}}},
+      {"name": "node", "args": [{"name": "id"}], "type": {"kind": "INTERFACE", "name": "Node"}}
+    ]},
+    {"kind": "OBJECT", "name": "Mutation", "fields": [
+      {"name": "createUser", "args": [{"name": "name"}], "type": {"kind": "OBJECT", "name": "User"}}
+    ]},
+    {"kind": "OBJECT", "name": "User", "fields": [
+      {"name": "id", "args": [], "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}},
+      {"name": "name", "description": "Full name", "args": [], "type": {"kind": "SCALAR", "name": "String"}},
+      {"name": "friends", "args": [], "type": {"kind": "LIST", "ofType": {"kind": "OBJECT", "name": "User"}}}
+    ]},
+    {"kind": "INTERFACE", "name": "Node", "fields": [
+      {"name": "id", "args": [], "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}}
+    ]},
+    {"kind": "SCALAR", "name": "String", "fields": null}
+  ]
+}}}`
+
+func TestParseGraphQLSchema(t *testing.T) {
+	schema, err := ParseGraphQLSchema([]byte(introspectionResponse))
+	require.NoError(t, err)
+	assert.Equal(t, "Query", schema.QueryType)
+	assert.Equal(t, "Mutation", schema.MutationType)
+	assert.Empty(t, schema.SubscriptionType)
+
+	users := schema.Types["Query"].Fields[1]
+	assert.Equal(t, "[User!]!", users.Type)
+	assert.Equal(t, "User", users.TypeName)
+	assert.Equal(t, "user(id): User", schema.Types["Query"].Fields[0].Signature())
+
+	_, err = ParseGraphQLSchema([]byte(`{"errors": [{"message": "introspection is disabled"}]}`))
+	assert.ErrorContains(t, err, "introspection failed: introspection is disabled")
+}
+
+func TestGraphQLSchemaComplete(t *testing.T) {
+	schema, err := ParseGraphQLSchema([]byte(introspectionResponse))
+	require.NoError(t, err)
+
+	names := func(fields []GraphQLField) []string {
+		var names []string
+		for _, f := range fields {
+			names = append(names, f.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		prefix   string
+		expected []string
+	}{
+		{name: "anonymous query", query: "{ us|", prefix: "us", expected: []string{"user", "users"}},
+		{name: "nested field", query: "query { user(id: \"1\") { |", expected: []string{"id", "name", "friends"}},
+		{name: "deeper", query: "{ users { friends { na| } } }", prefix: "na", expected: []string{"name"}},
+		{name: "closed selection", query: "{ user { id } | }", expected: []string{"user", "users", "node"}},
+		{name: "alias", query: "{ me: user(id: 1) { fr|", prefix: "fr", expected: []string{"friends"}},
+		{name: "mutation", query: "mutation Create($n: String) { c|", prefix: "c", expected: []string{"createUser"}},
+		{name: "inline fragment", query: "{ node(id: 1) { ... on User { na|", prefix: "na", expected: []string{"name"}},
+		{name: "fragment", query: "fragment F on User { |", expected: []string{"id", "name", "friends"}},
+		{name: "comment and string", query: "{ user(id: \"{\") { # {\n n|", prefix: "n", expected: []string{"name"}},
+		{name: "inside arguments", query: "{ user(i|", prefix: "i"},
+		{name: "outside selection", query: "que|", prefix: "que"},
+		{name: "unknown field", query: "{ missing { |"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// | marks the cursor
+			offset := strings.Index(tt.query, "|")
+			query := strings.Replace(tt.query, "|", "", 1)
+			prefix, fields := schema.Complete(query, offset)
+			assert.Equal(t, tt.prefix, prefix)
+			assert.Equal(t, tt.expected, names(fields))
+		})
+	}
+}
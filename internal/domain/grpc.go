@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// MethodGRPC marks a request that calls a gRPC method instead of sending an
+// HTTP request. URLs starting with grpc:// or grpcs:// do the same.
+const MethodGRPC = "GRPC"
+
+const ContentTypeGRPC = "application/grpc"
+
+// GRPCMethod is a method of a gRPC service, as found by server reflection or
+// in proto files.
+type GRPCMethod struct {
+	Service         string `json:"service"`
+	Name            string `json:"name"`
+	ClientStreaming bool   `json:"client_streaming,omitempty"`
+	ServerStreaming bool   `json:"server_streaming,omitempty"`
+	// Template is the request message as JSON with every field unset
+	Template string `json:"template,omitempty"`
+}
+
+// Path is the method as written after the address in a gRPC URL, such as
+// helloworld.Greeter/SayHello.
+func (m GRPCMethod) Path() string {
+	return m.Service + "/" + m.Name
+}
+
+func (m GRPCMethod) Kind() string {
+	switch {
+	case m.ClientStreaming && m.ServerStreaming:
+		return "bidi streaming"
+	case m.ClientStreaming:
+		return "client streaming"
+	case m.ServerStreaming:
+		return "server streaming"
+	default:
+		return "unary"
+	}
+}
+
+// GRPCStatus is the status message and trailers a gRPC call ended with, the
+// code is in the StatusCode of the response.
+type GRPCStatus struct {
+	Message  string      `json:"message,omitempty"`
+	Trailers http.Header `json:"trailers,omitempty"`
+}
+
+// GRPCTarget is where a gRPC request goes, split from its URL.
+type GRPCTarget struct {
+	Address string
+	TLS     bool
+	// Method is the service and method, empty to list what the server offers
+	Method string
+}
+
+// IsGRPC reports whether the request calls a gRPC method.
+func (req *Request) IsGRPC() bool {
+	return req.Method == MethodGRPC || strings.HasPrefix(req.URL, "grpc://") || strings.HasPrefix(req.URL, "grpcs://")
+}
+
+// GRPCTarget reads the address and method from a URL such as
+// grpc://localhost:50051/helloworld.Greeter/SayHello. http and grpc URLs are
+// sent in plaintext, https and grpcs ones over TLS.
+func (req *Request) GRPCTarget() (GRPCTarget, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return GRPCTarget{}, fmt.Errorf("invalid gRPC url: %w", err)
+	}
+	if u.Host == "" {
+		return GRPCTarget{}, errors.New("gRPC url requires a host")
+	}
+
+	target := GRPCTarget{Address: u.Host, Method: strings.Trim(u.Path, "/")}
+	port := "80"
+	switch u.Scheme {
+	case "http", "grpc":
+	case "https", "grpcs":
+		target.TLS = true
+		port = "443"
+	default:
+		return GRPCTarget{}, fmt.Errorf("unsupported gRPC url scheme %q", u.Scheme)
+	}
+	if u.Port() == "" {
+		target.Address = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	if target.Method != "" {
+		service, method, ok := strings.Cut(target.Method, "/")
+		if !ok || service == "" || method == "" || strings.Contains(method, "/") {
+			return GRPCTarget{}, fmt.Errorf("invalid gRPC method %q, use package.Service/Method", target.Method)
+		}
+	}
+	return target, nil
+}
+
+// GRPCStatusText names the status codes of gRPC.
+func GRPCStatusText(code int) string {
+	if code >= 0 && code < len(grpcStatusTexts) {
+		return grpcStatusTexts[code]
+	}
+	return "Unknown"
+}
+
+var grpcStatusTexts = []string{
+	"OK",
+	"Canceled",
+	"Unknown",
+	"InvalidArgument",
+	"DeadlineExceeded",
+	"NotFound",
+	"AlreadyExists",
+	"PermissionDenied",
+	"ResourceExhausted",
+	"FailedPrecondition",
+	"Aborted",
+	"OutOfRange",
+	"Unimplemented",
+	"Internal",
+	"Unavailable",
+	"DataLoss",
+	"Unauthenticated",
+}
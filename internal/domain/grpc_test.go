@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestGRPCTarget(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected GRPCTarget
+		wantErr  string
+	}{
+		{url: "grpc://localhost:50051/helloworld.Greeter/SayHello", expected: GRPCTarget{Address: "localhost:50051", Method: "helloworld.Greeter/SayHello"}},
+		{url: "http://localhost:50051", expected: GRPCTarget{Address: "localhost:50051"}},
+		{url: "grpcs://api.example.com/shop.Orders/Get", expected: GRPCTarget{Address: "api.example.com:443", TLS: true, Method: "shop.Orders/Get"}},
+		{url: "https://api.example.com:8443/", expected: GRPCTarget{Address: "api.example.com:8443", TLS: true}},
+		{url: "grpc://localhost:50051/Greeter", wantErr: "invalid gRPC method"},
+		{url: "ws://localhost:50051", wantErr: "unsupported gRPC url scheme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			target, err := (&Request{URL: tt.url}).GRPCTarget()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, target)
+		})
+	}
+}
+
+func TestRequestIsGRPC(t *testing.T) {
+	cfg := &config.Config{App: config.AppConfig{DefaultPort: "8080"}}
+
+	req := NewRequest()
+	require.NoError(t, req.BuildRequest("", "GRPC", "localhost:50051/shop.Orders/Get", "", "", "JSON", "{}", cfg))
+	assert.True(t, req.IsGRPC())
+	assert.Equal(t, "http://localhost:50051/shop.Orders/Get", req.URL)
+
+	req = NewRequest()
+	require.NoError(t, req.BuildRequest("", "GET", "grpcs://api.example.com", "", "", "Text", "", cfg))
+	assert.True(t, req.IsGRPC())
+	assert.Equal(t, "grpcs://api.example.com", req.URL)
+}
+
+func TestGRPCResponseSuccess(t *testing.T) {
+	assert.True(t, (&Response{StatusCode: 0, GRPC: &GRPCStatus{}}).Success())
+	assert.False(t, (&Response{StatusCode: 5, GRPC: &GRPCStatus{}}).Success())
+	assert.Equal(t, "Unauthenticated", GRPCStatusText(16))
+	assert.Equal(t, "Unknown", GRPCStatusText(42))
+}
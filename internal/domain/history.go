@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type HistoryEntry struct {
+	ID       int64     `json:"id"`
+	SentAt   time.Time `json:"sent_at"`
+	Request  *Request  `json:"request"`
+	Response *Response `json:"response,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Summary is the one line description used when listing history.
+func (h *HistoryEntry) Summary() string {
+	status := "ERR"
+	if h.Error == "" && h.Response != nil {
+		status = fmt.Sprint(h.Response.StatusCode)
+	}
+	return fmt.Sprintf("%s  %-6s %-3s  %s", h.SentAt.Local().Format("01-02 15:04:05"), h.Request.Method, status, h.Request.URL)
+}
+
+// Promote returns a copy of the recorded request under name, ready to be
+// saved.
+func (h *HistoryEntry) Promote(name string) (*Request, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("name required to save a history entry")
+	}
+
+	req := h.Request.Clone()
+	if err := req.ParseName(name); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
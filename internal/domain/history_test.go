@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryEntryPromote(t *testing.T) {
+	entry := &HistoryEntry{
+		Request: &Request{
+			Name:    "old",
+			Method:  "GET",
+			URL:     "{{base}}/users",
+			Headers: map[string]string{"X-Token": "{{token}}"},
+		},
+	}
+
+	req, err := entry.Promote("  List Users ")
+	require.NoError(t, err)
+	assert.Equal(t, "list users", req.Name)
+	assert.Equal(t, "{{base}}/users", req.URL)
+
+	req.Headers["X-Token"] = "changed"
+	assert.Equal(t, "{{token}}", entry.Request.Headers["X-Token"])
+	assert.Equal(t, "old", entry.Request.Name)
+
+	_, err = entry.Promote(" ")
+	assert.Error(t, err)
+}
+
+func TestHistoryEntrySummary(t *testing.T) {
+	sentAt := time.Date(2024, 5, 6, 7, 8, 9, 0, time.Local)
+	req := &Request{Method: "POST", URL: "http://localhost:8080/users"}
+
+	ok := &HistoryEntry{SentAt: sentAt, Request: req, Response: &Response{StatusCode: 201}}
+	assert.Equal(t, "05-06 07:08:09  POST   201  http://localhost:8080/users", ok.Summary())
+
+	failed := &HistoryEntry{SentAt: sentAt, Request: req, Error: "connection refused"}
+	assert.Contains(t, failed.Summary(), "ERR")
+}
@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+)
+
+// ClientOptions override the configured HTTP client settings for a single
+// request, unset fields keep the configured value.
+type ClientOptions struct {
+	Timeout         time.Duration `json:"timeout,omitempty"`
+	FollowRedirects *bool         `json:"follow_redirects,omitempty"`
+	MaxRedirects    *int          `json:"max_redirects,omitempty"`
+	Proxy           string        `json:"proxy,omitempty"`
+	CACert          string        `json:"ca_cert,omitempty"`
+	ClientCert      string        `json:"client_cert,omitempty"`
+	ClientKey       string        `json:"client_key,omitempty"`
+	Insecure        *bool         `json:"insecure,omitempty"`
+	HTTPVersion     string        `json:"http_version,omitempty"`
+	// Stream shows the response as it arrives instead of waiting for all of it
+	Stream bool `json:"stream,omitempty"`
+	// PingInterval keeps a WebSocket alive with pings sent this often
+	PingInterval time.Duration `json:"ping_interval,omitempty"`
+	// ProtoFiles are the space separated .proto files describing a gRPC
+	// service, used instead of server reflection
+	ProtoFiles string `json:"proto_files,omitempty"`
+}
+
+// ParseOptions reads options written as "key:value, key:value". The keys are
+// timeout, redirects (a maximum, or off), proxy, ca, cert, key, insecure,
+// http, stream, ping and proto.
+func (req *Request) ParseOptions(optionsStr string) error {
+	opts := ClientOptions{}
+
+	for entry := range strings.SplitSeq(optionsStr, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(entry, ":")
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "timeout":
+			timeout, err := time.ParseDuration(value)
+			if err != nil || timeout <= 0 {
+				return fmt.Errorf("invalid timeout %q", value)
+			}
+			opts.Timeout = timeout
+		case "redirects":
+			follow := value != "off"
+			opts.FollowRedirects = &follow
+			if follow {
+				maxRedirects, err := strconv.Atoi(value)
+				if err != nil || maxRedirects < 0 {
+					return fmt.Errorf("invalid redirects %q, use a number or off", value)
+				}
+				opts.MaxRedirects = &maxRedirects
+			}
+		case "proxy":
+			opts.Proxy = value
+		case "ca":
+			opts.CACert = value
+		case "cert":
+			opts.ClientCert = value
+		case "key":
+			opts.ClientKey = value
+		case "insecure":
+			insecure, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid insecure %q, use true or false", value)
+			}
+			opts.Insecure = &insecure
+		case "http":
+			if value != "1.1" && value != "2" {
+				return fmt.Errorf("unsupported http version %q, use 1.1 or 2", value)
+			}
+			opts.HTTPVersion = value
+		case "stream":
+			stream, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid stream %q, use true or false", value)
+			}
+			opts.Stream = stream
+		case "ping":
+			interval, err := time.ParseDuration(value)
+			if err != nil || interval <= 0 {
+				return fmt.Errorf("invalid ping %q", value)
+			}
+			opts.PingInterval = interval
+		case "proto":
+			opts.ProtoFiles = strings.Join(strings.Fields(value), " ")
+		default:
+			return fmt.Errorf("unknown option %q", key)
+		}
+	}
+
+	req.Options = nil
+	if opts != (ClientOptions{}) {
+		req.Options = &opts
+	}
+	return nil
+}
+
+// String renders the options in the form read by ParseOptions.
+func (o *ClientOptions) String() string {
+	if o == nil {
+		return ""
+	}
+
+	var parts []string
+	if o.Timeout > 0 {
+		parts = append(parts, "timeout:"+o.Timeout.String())
+	}
+	switch {
+	case o.FollowRedirects != nil && !*o.FollowRedirects:
+		parts = append(parts, "redirects:off")
+	case o.MaxRedirects != nil:
+		parts = append(parts, fmt.Sprintf("redirects:%d", *o.MaxRedirects))
+	}
+	if o.Proxy != "" {
+		parts = append(parts, "proxy:"+o.Proxy)
+	}
+	if o.CACert != "" {
+		parts = append(parts, "ca:"+o.CACert)
+	}
+	if o.ClientCert != "" {
+		parts = append(parts, "cert:"+o.ClientCert)
+	}
+	if o.ClientKey != "" {
+		parts = append(parts, "key:"+o.ClientKey)
+	}
+	if o.Insecure != nil {
+		parts = append(parts, fmt.Sprintf("insecure:%t", *o.Insecure))
+	}
+	if o.HTTPVersion != "" {
+		parts = append(parts, "http:"+o.HTTPVersion)
+	}
+	if o.Stream {
+		parts = append(parts, "stream:true")
+	}
+	if o.PingInterval > 0 {
+		parts = append(parts, "ping:"+o.PingInterval.String())
+	}
+	if o.ProtoFiles != "" {
+		parts = append(parts, "proto:"+o.ProtoFiles)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Apply returns cfg with the options that are set replacing its values.
+func (o *ClientOptions) Apply(cfg config.HTTPConfig) config.HTTPConfig {
+	if o == nil {
+		return cfg
+	}
+
+	if o.Timeout > 0 {
+		cfg.Timeout = o.Timeout
+	}
+	if o.FollowRedirects != nil {
+		cfg.FollowRedirects = *o.FollowRedirects
+	}
+	if o.MaxRedirects != nil {
+		cfg.MaxRedirects = *o.MaxRedirects
+	}
+	if o.Proxy != "" {
+		cfg.Proxy = o.Proxy
+	}
+	if o.CACert != "" {
+		cfg.CACert = o.CACert
+	}
+	if o.ClientCert != "" {
+		cfg.ClientCert = o.ClientCert
+		cfg.ClientKey = o.ClientKey
+	}
+	if o.Insecure != nil {
+		cfg.Insecure = *o.Insecure
+	}
+	if o.HTTPVersion != "" {
+		cfg.HTTPVersion = o.HTTPVersion
+	}
+	return cfg
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOptions(t *testing.T) {
+	off, on, three := false, true, 3
+
+	tests := []struct {
+		name     string
+		input    string
+		expected *ClientOptions
+		wantErr  string
+	}{
+		{name: "empty", input: "", expected: nil},
+		{name: "timeout", input: "timeout:10s", expected: &ClientOptions{Timeout: 10 * time.Second}},
+		{name: "redirects off", input: "redirects:off", expected: &ClientOptions{FollowRedirects: &off}},
+		{name: "redirects max", input: "redirects:3", expected: &ClientOptions{FollowRedirects: &on, MaxRedirects: &three}},
+		{
+			name:  "all",
+			input: "proxy:http://localhost:3128, ca:ca.pem, cert:c.pem, key:k.pem, insecure:true, http:2",
+			expected: &ClientOptions{
+				Proxy:       "http://localhost:3128",
+				CACert:      "ca.pem",
+				ClientCert:  "c.pem",
+				ClientKey:   "k.pem",
+				Insecure:    &on,
+				HTTPVersion: "2",
+			},
+		},
+		{name: "stream", input: "stream:true", expected: &ClientOptions{Stream: true}},
+		{name: "ping", input: "ping:30s", expected: &ClientOptions{PingInterval: 30 * time.Second}},
+		{name: "proto", input: "proto: api.proto  types.proto", expected: &ClientOptions{ProtoFiles: "api.proto types.proto"}},
+		{name: "bad timeout", input: "timeout:soon", wantErr: "invalid timeout"},
+		{name: "bad redirects", input: "redirects:many", wantErr: "invalid redirects"},
+		{name: "bad version", input: "http:3", wantErr: "unsupported http version"},
+		{name: "bad stream", input: "stream:maybe", wantErr: "invalid stream"},
+		{name: "bad ping", input: "ping:0s", wantErr: "invalid ping"},
+		{name: "unknown key", input: "retries:2", wantErr: "unknown option"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := NewRequest()
+			err := req.ParseOptions(tt.input)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, req.Options)
+
+			// the rendered options parse back to the same value
+			again := NewRequest()
+			assert.NoError(t, again.ParseOptions(req.Options.String()))
+			assert.Equal(t, req.Options, again.Options)
+		})
+	}
+}
+
+func TestClientOptionsApply(t *testing.T) {
+	base := config.HTTPConfig{Timeout: 30 * time.Second, FollowRedirects: true, MaxRedirects: 10}
+
+	var none *ClientOptions
+	assert.Equal(t, base, none.Apply(base))
+
+	off, insecure := false, true
+	opts := &ClientOptions{Timeout: time.Second, FollowRedirects: &off, Insecure: &insecure, HTTPVersion: "1.1"}
+	assert.Equal(t, config.HTTPConfig{
+		Timeout:         time.Second,
+		FollowRedirects: false,
+		MaxRedirects:    10,
+		Insecure:        true,
+		HTTPVersion:     "1.1",
+	}, opts.Apply(base))
+}
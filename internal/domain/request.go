@@ -3,6 +3,9 @@ package domain
 import (
 	"encoding/json"
 	"errors"
+	"maps"
+	"net/url"
+	"slices"
 	"strings"
 
 	"github.com/ManoloEsS/burrow/internal/config"
@@ -14,8 +17,19 @@ type Request struct {
 	URL         string            `json:"url"`
 	ContentType map[string]string `json:"content-type,omitempty"`
 	Body        string            `json:"body,omitempty"`
+	Form        []FormField       `json:"form,omitempty"`
+	BodyFile    string            `json:"body_file,omitempty"`
+	GraphQL     *GraphQLBody      `json:"graphql,omitempty"`
+	Messages    []WSMessage       `json:"messages,omitempty"`
 	Params      map[string]string `json:"params,omitempty"`
 	Headers     map[string]string `json:"headers,omitempty"`
+	Assertions  []Assertion       `json:"assertions,omitempty"`
+	Options     *ClientOptions    `json:"options,omitempty"`
+	Auth        *Auth             `json:"auth,omitempty"`
+
+	// CollectionID is stored alongside the request rather than in its JSON,
+	// zero means the request is not in a collection.
+	CollectionID int64 `json:"-"`
 }
 
 func NewRequest() *Request {
@@ -26,6 +40,30 @@ func NewRequest() *Request {
 	}
 }
 
+// Clone returns a deep copy of the request.
+func (req *Request) Clone() *Request {
+	clone := *req
+	clone.ContentType = maps.Clone(req.ContentType)
+	clone.Headers = maps.Clone(req.Headers)
+	clone.Params = maps.Clone(req.Params)
+	clone.Form = slices.Clone(req.Form)
+	clone.Messages = slices.Clone(req.Messages)
+	clone.Assertions = slices.Clone(req.Assertions)
+	if req.Options != nil {
+		options := *req.Options
+		clone.Options = &options
+	}
+	if req.Auth != nil {
+		auth := *req.Auth
+		clone.Auth = &auth
+	}
+	if req.GraphQL != nil {
+		graphQL := *req.GraphQL
+		clone.GraphQL = &graphQL
+	}
+	return &clone
+}
+
 func (req *Request) ParseMethod(method string) error {
 	if method == "" {
 		return errors.New("method required for http request")
@@ -36,7 +74,9 @@ func (req *Request) ParseMethod(method string) error {
 }
 
 func (req *Request) ParseUrl(cfg *config.Config, url string) error {
-	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "{{") ||
+		strings.HasPrefix(url, "ws://") || strings.HasPrefix(url, "wss://") ||
+		strings.HasPrefix(url, "grpc://") || strings.HasPrefix(url, "grpcs://") {
 		req.URL = url
 		return nil
 	}
@@ -91,9 +131,15 @@ func (req *Request) ParseBodyType(bodyTypeStr string) error {
 	if bodyTypeStr == "Text" {
 		req.ContentType["Content-Type"] = "text/plain; charset=utf-8"
 	}
-	if bodyTypeStr == "JSON" {
+	if bodyTypeStr == "JSON" || bodyTypeStr == "GraphQL" {
 		req.ContentType["Content-Type"] = "application/json"
 	}
+	if bodyTypeStr == "Form" {
+		req.ContentType["Content-Type"] = ContentTypeForm
+	}
+	if bodyTypeStr == "Multipart" {
+		req.ContentType["Content-Type"] = ContentTypeMultipart
+	}
 
 	return nil
 }
@@ -110,6 +156,29 @@ func (req *Request) ParseBody(body, bodyTypeStr string) error {
 		req.Body = body
 		return nil
 	}
+	if bodyTypeStr == "Form" || bodyTypeStr == "Multipart" {
+		form, err := ParseForm(body)
+		if err != nil {
+			return err
+		}
+		req.Form = form
+		return nil
+	}
+	if bodyTypeStr == "GraphQL" {
+		if strings.TrimSpace(body) == "" {
+			return errors.New("query required for GraphQL body")
+		}
+		req.GraphQL = &GraphQLBody{Query: body}
+		return nil
+	}
+	if bodyTypeStr == "File" {
+		path := strings.TrimSpace(body)
+		if path == "" {
+			return errors.New("file path required for file body")
+		}
+		req.BodyFile = path
+		return nil
+	}
 	return nil
 }
 
@@ -133,6 +202,32 @@ func (req *Request) ParseParams(paramsStr string) error {
 	return nil
 }
 
+// AddQuery decodes an encoded query string into the params and returns what
+// has to stay in the url instead. A query repeating a key, or setting one
+// the params already hold, would lose values in them, so it stays whole.
+func (req *Request) AddQuery(query string) (string, error) {
+	if query == "" {
+		return "", nil
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return "", err
+	}
+	for key, vals := range values {
+		if _, ok := req.Params[key]; ok || len(vals) > 1 {
+			return query, nil
+		}
+	}
+
+	if req.Params == nil {
+		req.Params = make(map[string]string)
+	}
+	for key, vals := range values {
+		req.Params[key] = vals[0]
+	}
+	return "", nil
+}
+
 func (req *Request) ParseName(nameStr string) error {
 	if nameStr == "" {
 		return nil
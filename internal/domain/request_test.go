@@ -5,6 +5,7 @@ import (
 
 	"github.com/ManoloEsS/burrow/internal/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseMethod(t *testing.T) {
@@ -111,6 +112,11 @@ func TestParseUrl(t *testing.T) {
 			input:    "localhost:3000",
 			expected: "http://localhost:3000",
 		},
+		{
+			name:     "Parse environment placeholder",
+			input:    "{{base_url}}/users",
+			expected: "{{base_url}}/users",
+		},
 	}
 
 	for _, tt := range tests {
@@ -183,6 +189,18 @@ func TestParseBodyType(t *testing.T) {
 			expected:    "text/plain; charset=utf-8",
 			description: "Should convert Text to plain text with charset",
 		},
+		{
+			name:        "Parse Form body type",
+			input:       "Form",
+			expected:    "application/x-www-form-urlencoded",
+			description: "Should convert Form to a URL encoded form",
+		},
+		{
+			name:        "Parse Multipart body type",
+			input:       "Multipart",
+			expected:    "multipart/form-data",
+			description: "Should convert Multipart to multipart form data",
+		},
 	}
 
 	for _, tt := range tests {
@@ -307,3 +325,14 @@ func TestParseName(t *testing.T) {
 		})
 	}
 }
+
+func TestParseBodyFile(t *testing.T) {
+	req := NewRequest()
+	require.NoError(t, req.BuildRequest("", "POST", "/upload", "", "", "File", "  fixtures/big.json\n", &config.Config{App: config.AppConfig{DefaultPort: "8080"}}))
+	assert.Equal(t, "fixtures/big.json", req.BodyFile)
+	assert.Empty(t, req.Body)
+	assert.Empty(t, req.ContentType["Content-Type"])
+
+	err := NewRequest().ParseBody(" ", "File")
+	assert.Error(t, err)
+}
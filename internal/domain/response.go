@@ -2,41 +2,111 @@ package domain
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 )
 
 type Response struct {
-	Status        string
-	ContentType   string
-	ContentLenght int64
-	Body          string
-	ResponseTime  time.Duration
+	Status        string      `json:"status"`
+	StatusCode    int         `json:"status_code"`
+	ContentType   string      `json:"content_type"`
+	ContentLenght int64       `json:"content_length"`
+	Headers       http.Header `json:"headers,omitempty"`
+	Body          string      `json:"body"`
+	BodySize      int64       `json:"body_size"`
+	Truncated     bool        `json:"truncated,omitempty"`
+	Binary        bool        `json:"binary,omitempty"`
+	// BodyFile holds the whole body when it was too large to keep in Body
+	BodyFile string `json:"-"`
+
+	// raw is the body as received when Body was reformatted
+	raw          []byte
+	ResponseTime time.Duration `json:"response_time"`
+	Timing       *Timing       `json:"timing,omitempty"`
+	Proto        string        `json:"proto,omitempty"`
+	URL          string        `json:"url,omitempty"`
+	Cookies      []Cookie      `json:"cookies,omitempty"`
+	TLS          *TLSInfo      `json:"tls,omitempty"`
+	Redirects    []Redirect    `json:"redirects,omitempty"`
+	Stream       *StreamStats  `json:"stream,omitempty"`
+	// GRPC is set for gRPC calls, whose StatusCode is the gRPC status code
+	GRPC *GRPCStatus `json:"grpc,omitempty"`
+	// GraphQL is the body of a GraphQL response read apart, it is not kept as
+	// the body already holds it
+	GraphQL *GraphQLResult `json:"-"`
 }
 
-func (resp *Response) BuildResponse(httpR *http.Response) error {
-	resp.Status = httpR.Status
-	resp.ContentType = httpR.Header.Get("Content-Type")
-	resp.ContentLenght = httpR.ContentLength
+type Cookie struct {
+	Name     string    `json:"name"`
+	Value    string    `json:"value"`
+	Domain   string    `json:"domain,omitempty"`
+	Path     string    `json:"path,omitempty"`
+	Expires  time.Time `json:"expires,omitzero"`
+	MaxAge   int       `json:"max_age,omitempty"`
+	Secure   bool      `json:"secure,omitempty"`
+	HttpOnly bool      `json:"http_only,omitempty"`
+	SameSite string    `json:"same_site,omitempty"`
+}
+
+type TLSInfo struct {
+	Version            string        `json:"version"`
+	CipherSuite        string        `json:"cipher_suite"`
+	ServerName         string        `json:"server_name,omitempty"`
+	NegotiatedProtocol string        `json:"negotiated_protocol,omitempty"`
+	Certificates       []Certificate `json:"certificates,omitempty"`
+}
+
+type Certificate struct {
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	DNSNames  []string  `json:"dns_names,omitempty"`
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+}
+
+// Redirect is a response that was followed to reach the final URL.
+type Redirect struct {
+	Status     string `json:"status"`
+	StatusCode int    `json:"status_code"`
+	URL        string `json:"url"`
+	Location   string `json:"location"`
+}
+
+func (resp *Response) Success() bool {
+	if resp.GRPC != nil {
+		return resp.StatusCode == 0
+	}
+	if resp.GraphQL != nil && len(resp.GraphQL.Errors) > 0 {
+		return false
+	}
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+func (resp *Response) BuildResponse(httpR *http.Response, opts BodyOptions) error {
+	resp.buildHead(httpR)
 
 	if httpR.Body != nil {
-		bodyBytes, err := io.ReadAll(httpR.Body)
+		bodyBytes, err := resp.readBody(httpR.Body, httpR.ContentLength, opts)
 		if err != nil {
 			resp.Body = fmt.Sprintf("Error reading body: %v", err)
 			return err
 		}
+		resp.Binary = IsBinary(resp.ContentType, bodyBytes)
 
-		if strings.HasPrefix(resp.ContentType, "application/json") {
+		// a truncated document cannot be indented
+		if strings.HasPrefix(resp.ContentType, "application/json") && !resp.Truncated {
 			var prettyJson bytes.Buffer
 			if err := json.Indent(&prettyJson, bodyBytes, "", " "); err != nil {
 				resp.Body = fmt.Sprintf("Error prettyfying JSON body: %v", err)
 				return err
 			}
 			resp.Body = prettyJson.String()
+			resp.raw = bodyBytes
 			return nil
 		}
 
@@ -48,3 +118,93 @@ func (resp *Response) BuildResponse(httpR *http.Response) error {
 
 	return nil
 }
+
+// buildHead fills in everything about the response but its body.
+func (resp *Response) buildHead(httpR *http.Response) {
+	resp.Status = httpR.Status
+	resp.StatusCode = httpR.StatusCode
+	resp.ContentType = httpR.Header.Get("Content-Type")
+	resp.ContentLenght = httpR.ContentLength
+	resp.Headers = httpR.Header.Clone()
+	resp.Proto = httpR.Proto
+	resp.Cookies = buildCookies(httpR.Cookies())
+	resp.TLS = buildTLSInfo(httpR.TLS)
+
+	if httpR.Request != nil {
+		resp.URL = httpR.Request.URL.String()
+		resp.Redirects = buildRedirects(httpR.Request.Response)
+	}
+}
+
+func buildCookies(cookies []*http.Cookie) []Cookie {
+	if len(cookies) == 0 {
+		return nil
+	}
+
+	sameSite := map[http.SameSite]string{
+		http.SameSiteLaxMode:    "Lax",
+		http.SameSiteStrictMode: "Strict",
+		http.SameSiteNoneMode:   "None",
+	}
+
+	result := make([]Cookie, 0, len(cookies))
+	for _, c := range cookies {
+		result = append(result, Cookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Expires:  c.Expires,
+			MaxAge:   c.MaxAge,
+			Secure:   c.Secure,
+			HttpOnly: c.HttpOnly,
+			SameSite: sameSite[c.SameSite],
+		})
+	}
+	return result
+}
+
+func buildTLSInfo(state *tls.ConnectionState) *TLSInfo {
+	if state == nil {
+		return nil
+	}
+
+	info := &TLSInfo{
+		Version:            tls.VersionName(state.Version),
+		CipherSuite:        tls.CipherSuiteName(state.CipherSuite),
+		ServerName:         state.ServerName,
+		NegotiatedProtocol: state.NegotiatedProtocol,
+	}
+	for _, cert := range state.PeerCertificates {
+		info.Certificates = append(info.Certificates, Certificate{
+			Subject:   cert.Subject.String(),
+			Issuer:    cert.Issuer.String(),
+			DNSNames:  cert.DNSNames,
+			NotBefore: cert.NotBefore,
+			NotAfter:  cert.NotAfter,
+		})
+	}
+	return info
+}
+
+// buildRedirects walks back from the response that caused the final request
+// and returns the chain in the order it was followed.
+func buildRedirects(prev *http.Response) []Redirect {
+	var redirects []Redirect
+	for r := prev; r != nil; {
+		redirect := Redirect{
+			Status:     r.Status,
+			StatusCode: r.StatusCode,
+			Location:   r.Header.Get("Location"),
+		}
+		if r.Request == nil {
+			redirects = append(redirects, redirect)
+			break
+		}
+		redirect.URL = r.Request.URL.String()
+		redirects = append(redirects, redirect)
+		r = r.Request.Response
+	}
+	slices.Reverse(redirects)
+	return redirects
+}
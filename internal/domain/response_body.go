@@ -0,0 +1,203 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+// BodyOptions bounds how a response body is read.
+type BodyOptions struct {
+	// MaxMemory is the most of the body kept in Body, a longer body is
+	// written whole to a temporary file. Zero keeps every body in memory.
+	MaxMemory int64
+	// Progress is called as the body downloads with the bytes read so far and
+	// the expected size, -1 when the server did not send one.
+	Progress func(read, total int64)
+}
+
+// readBody reads the body, keeping up to opts.MaxMemory bytes and spilling
+// the rest to BodyFile so a large download never sits in memory.
+func (resp *Response) readBody(body io.Reader, total int64, opts BodyOptions) ([]byte, error) {
+	if opts.Progress != nil {
+		body = &progressReader{r: body, total: total, progress: opts.Progress}
+	}
+
+	if opts.MaxMemory <= 0 {
+		data, err := io.ReadAll(body)
+		resp.BodySize = int64(len(data))
+		return data, err
+	}
+
+	var head bytes.Buffer
+	n, err := io.CopyN(&head, body, opts.MaxMemory+1)
+	resp.BodySize = n
+	if errors.Is(err, io.EOF) {
+		return head.Bytes(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.CreateTemp("", "burrow-response-*")
+	if err != nil {
+		return nil, fmt.Errorf("could not spill body to a temporary file: %w", err)
+	}
+	resp.BodyFile = file.Name()
+	resp.Truncated = true
+
+	written, err := io.Copy(file, io.MultiReader(bytes.NewReader(head.Bytes()), body))
+	resp.BodySize = written
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = resp.RemoveBodyFile()
+		return nil, err
+	}
+	return head.Bytes()[:opts.MaxMemory], nil
+}
+
+// SaveBody writes the whole body as it was received to path, copying it
+// from BodyFile when it did not fit in memory.
+func (resp *Response) SaveBody(path string) error {
+	if resp.BodyFile == "" {
+		data := resp.raw
+		if data == nil {
+			data = []byte(resp.Body)
+		}
+		return os.WriteFile(path, data, 0o644)
+	}
+
+	src, err := os.Open(resp.BodyFile)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+
+	dst, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+// BodyFileName suggests a file name for saving the body, the last segment
+// of the URL or "response" with an extension matching the content type.
+func (resp *Response) BodyFileName() string {
+	if u, err := url.Parse(resp.URL); err == nil {
+		if name := path.Base(u.Path); name != "/" && name != "." && path.Ext(name) != "" {
+			return name
+		}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.ContentType)
+	switch {
+	case strings.HasSuffix(mediaType, "json"):
+		return "response.json"
+	case mediaType == "text/plain":
+		return "response.txt"
+	}
+	if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+		return "response" + exts[0]
+	}
+	return "response.bin"
+}
+
+// RemoveBodyFile deletes the temporary file holding a large body, once the
+// response is no longer shown.
+func (resp *Response) RemoveBodyFile() error {
+	if resp == nil || resp.BodyFile == "" {
+		return nil
+	}
+	err := os.Remove(resp.BodyFile)
+	resp.BodyFile = ""
+	return err
+}
+
+// IsBinary reports whether a body should be previewed as hex rather than
+// shown as text. Text content types are trusted, anything else is text only
+// when it is valid UTF-8 without control characters.
+func IsBinary(contentType string, body []byte) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "json"), strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "xml"), strings.HasSuffix(mediaType, "javascript"),
+		mediaType == ContentTypeForm:
+		return false
+	}
+
+	body = trimPartialRune(body)
+	if !utf8.Valid(body) {
+		return true
+	}
+	return bytes.ContainsFunc(body, func(r rune) bool {
+		return r < 0x20 && r != '\n' && r != '\r' && r != '\t'
+	})
+}
+
+// trimPartialRune drops a rune cut at the end of a truncated body.
+func trimPartialRune(b []byte) []byte {
+	for i := 1; i <= utf8.UTFMax && i <= len(b); i++ {
+		if utf8.RuneStart(b[len(b)-i]) {
+			if !utf8.FullRune(b[len(b)-i:]) {
+				return b[:len(b)-i]
+			}
+			break
+		}
+	}
+	return b
+}
+
+// HexPreviewSize is how much of a binary body HexPreview dumps.
+const HexPreviewSize = 4096
+
+// HexPreview dumps the start of the body in hexdump -C format.
+func (resp *Response) HexPreview() string {
+	body := resp.Body
+	if len(body) > HexPreviewSize {
+		body = body[:HexPreviewSize]
+	}
+	return hex.Dump([]byte(body))
+}
+
+// FormatBytes renders a size for display, such as 512 B or 12.5 MB.
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 3; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGT"[exp])
+}
+
+type progressReader struct {
+	r        io.Reader
+	read     int64
+	total    int64
+	progress func(read, total int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.read += int64(n)
+	p.progress(p.read, p.total)
+	return n, err
+}
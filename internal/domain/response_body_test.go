@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBodyResponse(contentType, body string) *http.Response {
+	httpResp := &http.Response{
+		Status:        "200 OK",
+		StatusCode:    200,
+		Header:        make(http.Header),
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+	}
+	httpResp.Header.Set("Content-Type", contentType)
+	return httpResp
+}
+
+func TestBuildResponseSpillsLargeBody(t *testing.T) {
+	body := strings.Repeat("0123456789", 100)
+
+	var progress []int64
+	resp := &Response{}
+	require.NoError(t, resp.BuildResponse(newBodyResponse("text/plain", body), BodyOptions{
+		MaxMemory: 64,
+		Progress: func(read, total int64) {
+			assert.Equal(t, int64(len(body)), total)
+			progress = append(progress, read)
+		},
+	}))
+	t.Cleanup(func() { _ = resp.RemoveBodyFile() })
+
+	assert.True(t, resp.Truncated)
+	assert.Equal(t, body[:64], resp.Body)
+	assert.Equal(t, int64(len(body)), resp.BodySize)
+	require.NotEmpty(t, progress)
+	assert.Equal(t, int64(len(body)), progress[len(progress)-1])
+
+	spilled, err := os.ReadFile(resp.BodyFile)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(spilled))
+
+	saved := filepath.Join(t.TempDir(), "body.txt")
+	require.NoError(t, resp.SaveBody(saved))
+	data, err := os.ReadFile(saved)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(data))
+
+	path := resp.BodyFile
+	require.NoError(t, resp.RemoveBodyFile())
+	assert.NoFileExists(t, path)
+	assert.Empty(t, resp.BodyFile)
+}
+
+func TestBuildResponseKeepsBodyWithinLimit(t *testing.T) {
+	body := `{"a":1}`
+
+	resp := &Response{}
+	require.NoError(t, resp.BuildResponse(newBodyResponse("application/json", body), BodyOptions{MaxMemory: int64(len(body))}))
+
+	assert.False(t, resp.Truncated)
+	assert.Empty(t, resp.BodyFile)
+	assert.Equal(t, "{\n \"a\": 1\n}", resp.Body)
+	assert.Equal(t, int64(len(body)), resp.BodySize)
+
+	// the body is saved as received, not as displayed
+	saved := filepath.Join(t.TempDir(), "body.json")
+	require.NoError(t, resp.SaveBody(saved))
+	data, err := os.ReadFile(saved)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(data))
+}
+
+func TestBuildResponseTruncatedJSON(t *testing.T) {
+	resp := &Response{}
+	require.NoError(t, resp.BuildResponse(newBodyResponse("application/json", `{"items":[1,2,3,4,5]}`), BodyOptions{MaxMemory: 8}))
+	t.Cleanup(func() { _ = resp.RemoveBodyFile() })
+
+	assert.True(t, resp.Truncated)
+	assert.Equal(t, `{"items"`, resp.Body)
+}
+
+func TestIsBinary(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		expected    bool
+	}{
+		{name: "Plain text", contentType: "text/plain; charset=utf-8", body: []byte("hello\n"), expected: false},
+		{name: "JSON", contentType: "application/problem+json", body: []byte(`{"a":1}`), expected: false},
+		{name: "PNG", contentType: "image/png", body: []byte("\x89PNG\r\n\x1a\n\x00\x00"), expected: true},
+		{name: "Protobuf without type", contentType: "", body: []byte{0x08, 0x96, 0x01}, expected: true},
+		{name: "Text without type", contentType: "", body: []byte("héllo\tworld\r\n"), expected: false},
+		{name: "Rune cut by truncation", contentType: "application/octet-stream", body: []byte("caf\xc3"), expected: false},
+		{name: "Invalid UTF-8", contentType: "application/octet-stream", body: []byte("a\xffb"), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsBinary(tt.contentType, tt.body))
+		})
+	}
+}
+
+func TestHexPreview(t *testing.T) {
+	resp := &Response{Body: string(bytes.Repeat([]byte{0xff}, HexPreviewSize+100))}
+	preview := resp.HexPreview()
+	assert.Equal(t, HexPreviewSize/16, strings.Count(preview, "\n"))
+	assert.True(t, strings.HasPrefix(preview, "00000000  ff ff"))
+}
+
+func TestBodyFileName(t *testing.T) {
+	tests := []struct {
+		url         string
+		contentType string
+		expected    string
+	}{
+		{url: "https://example.com/files/report.pdf?v=2", contentType: "application/pdf", expected: "report.pdf"},
+		{url: "https://example.com/users", contentType: "application/json; charset=utf-8", expected: "response.json"},
+		{url: "https://example.com/", contentType: "text/plain", expected: "response.txt"},
+		{url: "https://example.com/blob", contentType: "", expected: "response.bin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			resp := &Response{URL: tt.url, ContentType: tt.contentType}
+			assert.Equal(t, tt.expected, resp.BodyFileName())
+		})
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", FormatBytes(512))
+	assert.Equal(t, "1.5 KB", FormatBytes(1536))
+	assert.Equal(t, "200.0 MB", FormatBytes(200<<20))
+	assert.Equal(t, "3.0 GB", FormatBytes(3<<30))
+}
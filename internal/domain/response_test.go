@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBuildResponse(t *testing.T) {
@@ -54,10 +56,11 @@ func TestBuildResponse(t *testing.T) {
 			mockHttpResponse.Header.Set("Content-Type", tt.contentType)
 
 			resp := &Response{}
-			err := resp.BuildResponse(mockHttpResponse)
+			err := resp.BuildResponse(mockHttpResponse, BodyOptions{})
 
 			assert.NoError(t, err)
 			assert.Equal(t, tt.expectedStatus, resp.Status)
+			assert.Equal(t, tt.statusCode, resp.StatusCode)
 			assert.Equal(t, tt.expectedType, resp.ContentType)
 			assert.Equal(t, int64(len(tt.body)), resp.ContentLenght)
 			if strings.HasPrefix(tt.contentType, "application/json") {
@@ -73,3 +76,67 @@ func TestBuildResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestResponseSuccess(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		expected   bool
+	}{
+		{name: "OK", statusCode: 200, expected: true},
+		{name: "No content", statusCode: 204, expected: true},
+		{name: "Redirect", statusCode: 301, expected: false},
+		{name: "Not found", statusCode: 404, expected: false},
+		{name: "Server error", statusCode: 500, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &Response{StatusCode: tt.statusCode}
+			assert.Equal(t, tt.expected, resp.Success())
+		})
+	}
+}
+
+func TestBuildResponseMetadata(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/moved", http.StatusMovedPermanently)
+		case "/moved":
+			http.Redirect(w, r, "/final", http.StatusFound)
+		default:
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/", HttpOnly: true, SameSite: http.SameSiteLaxMode})
+			w.Header().Set("X-Request-Id", "42")
+			_, _ = w.Write([]byte("done"))
+		}
+	}))
+	defer srv.Close()
+
+	httpResp, err := srv.Client().Get(srv.URL + "/old")
+	require.NoError(t, err)
+	defer func() { _ = httpResp.Body.Close() }()
+
+	resp := &Response{}
+	require.NoError(t, resp.BuildResponse(httpResp, BodyOptions{}))
+
+	assert.Equal(t, "HTTP/1.1", resp.Proto)
+	assert.Equal(t, srv.URL+"/final", resp.URL)
+	assert.Equal(t, "42", resp.Headers.Get("X-Request-Id"))
+
+	require.Len(t, resp.Cookies, 1)
+	assert.Equal(t, Cookie{Name: "session", Value: "abc", Path: "/", HttpOnly: true, SameSite: "Lax"}, resp.Cookies[0])
+
+	require.Len(t, resp.Redirects, 2)
+	assert.Equal(t, 301, resp.Redirects[0].StatusCode)
+	assert.Equal(t, srv.URL+"/old", resp.Redirects[0].URL)
+	assert.Equal(t, "/moved", resp.Redirects[0].Location)
+	assert.Equal(t, 302, resp.Redirects[1].StatusCode)
+	assert.Equal(t, "/final", resp.Redirects[1].Location)
+
+	require.NotNil(t, resp.TLS)
+	assert.Equal(t, "TLS 1.3", resp.TLS.Version)
+	assert.NotEmpty(t, resp.TLS.CipherSuite)
+	require.NotEmpty(t, resp.TLS.Certificates)
+	assert.True(t, resp.TLS.Certificates[0].NotAfter.After(resp.TLS.Certificates[0].NotBefore))
+}
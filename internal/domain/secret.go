@@ -0,0 +1,278 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// SecretPrefix marks a placeholder as a reference to a secret, as in
+// {{secret.github_token}}.
+const SecretPrefix = "secret."
+
+const (
+	SecretSourceEnv      = "env"
+	SecretSourceFile     = "file"
+	SecretSourceDatabase = "database"
+)
+
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z0-9_\-]+$`)
+
+// Secret describes a stored secret without its value.
+type Secret struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+}
+
+// ErrSecretNotFound is returned when a referenced secret is in none of the
+// stores.
+var ErrSecretNotFound = errors.New("secret not found")
+
+func ValidateSecretName(name string) error {
+	if !secretNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q, use letters, digits, _ and -", name)
+	}
+	return nil
+}
+
+// SecretRef returns the placeholder referencing the secret name.
+func SecretRef(name string) string {
+	return "{{" + SecretPrefix + name + "}}"
+}
+
+// SecretLookup combines the lookup of variables with the lookup of secrets.
+// Variables may themselves reference secrets. The first secret that cannot
+// be resolved is kept so the caller can report it instead of sending the
+// placeholder.
+type SecretLookup struct {
+	Variables func(string) (string, bool)
+	Secrets   func(string) (string, error)
+	Err       error
+}
+
+func (l *SecretLookup) Lookup(name string) (string, bool) {
+	secretName, ok := strings.CutPrefix(name, SecretPrefix)
+	if !ok {
+		if l.Variables == nil {
+			return "", false
+		}
+		val, ok := l.Variables(name)
+		if ok {
+			val = ExpandVariables(val, l.secretsOnly)
+		}
+		return val, ok
+	}
+
+	val, err := l.Secrets(secretName)
+	if err != nil {
+		if l.Err == nil {
+			l.Err = fmt.Errorf("secret %q: %w", secretName, err)
+		}
+		return "", false
+	}
+	return val, true
+}
+
+func (l *SecretLookup) secretsOnly(name string) (string, bool) {
+	if !strings.HasPrefix(name, SecretPrefix) {
+		return "", false
+	}
+	return l.Lookup(name)
+}
+
+// credentialHeaders are header names whose values are credentials.
+var credentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "X-Api-Key", "Api-Key"}
+
+// credentialField is a field of a request that holds a credential.
+type credentialField struct {
+	name  string
+	value string
+	set   func(string)
+	// scheme is set for headers starting with a scheme such as Bearer
+	scheme bool
+}
+
+func (req *Request) credentialFields() []credentialField {
+	var fields []credentialField
+
+	for _, name := range credentialHeaders {
+		for key, val := range req.Headers {
+			if strings.EqualFold(key, name) {
+				fields = append(fields, credentialField{
+					name:   key + " header",
+					value:  val,
+					set:    func(v string) { req.Headers[key] = v },
+					scheme: strings.HasSuffix(strings.ToLower(name), "authorization"),
+				})
+			}
+		}
+	}
+
+	if auth := req.Auth; auth != nil {
+		fields = append(fields,
+			credentialField{name: "auth password", value: auth.Password, set: func(v string) { auth.Password = v }},
+			credentialField{name: "auth token", value: auth.Token, set: func(v string) { auth.Token = v }},
+			credentialField{name: "api key", value: auth.Value, set: func(v string) { auth.Value = v }},
+			credentialField{name: "client secret", value: auth.ClientSecret, set: func(v string) { auth.ClientSecret = v }},
+		)
+	}
+	return fields
+}
+
+// PlaintextCredentials lists the fields of the request holding a credential
+// that is not a {{secret.NAME}} reference, written out or taken from a
+// {{var}}, so it would be saved as is.
+func (req *Request) PlaintextCredentials() []string {
+	var names []string
+	for _, field := range req.credentialFields() {
+		if isPlaintext(field.value) {
+			names = append(names, field.name)
+		}
+	}
+	return names
+}
+
+// MoveCredentials hands every credential written out in the request to store,
+// with a secret name made of the request and field names, and puts a
+// {{secret.NAME}} reference to the name store returns in its place. The
+// scheme of an Authorization header, such as Bearer, stays in the header. A
+// credential taken from a {{var}} cannot be moved and is an error.
+func (req *Request) MoveCredentials(store func(name, value string) (string, error)) error {
+	if err := req.CheckCredentialVariables(); err != nil {
+		return err
+	}
+	for _, field := range req.credentialFields() {
+		if !isPlaintext(field.value) {
+			continue
+		}
+		prefix, value := "", field.value
+		if scheme, rest, ok := strings.Cut(value, " "); field.scheme && ok && strings.TrimSpace(rest) != "" {
+			prefix, value = scheme+" ", strings.TrimSpace(rest)
+		}
+
+		name, err := store(credentialSecretName(req.Name, strings.TrimSuffix(field.name, " header")), value)
+		if err != nil {
+			return fmt.Errorf("could not store the %s: %w", field.name, err)
+		}
+		field.set(prefix + SecretRef(name))
+	}
+	return nil
+}
+
+// CheckCredentialVariables reports a credential taken from a {{var}}
+// instead of a {{secret.NAME}} reference.
+func (req *Request) CheckCredentialVariables() error {
+	for _, field := range req.credentialFields() {
+		if placeholder, ok := variableReference(field.value); ok {
+			return fmt.Errorf("the %s uses %s, reference credentials with {{secret.NAME}}", field.name, placeholder)
+		}
+	}
+	return nil
+}
+
+// credentialSecretName turns the request and field names into a valid
+// secret name, such as create-charge-authorization.
+func credentialSecretName(request, field string) string {
+	if request == "" {
+		request = "request"
+	}
+	name := strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToLower(r)
+		}
+		return '-'
+	}, request+"-"+field)
+	for strings.Contains(name, "--") {
+		name = strings.ReplaceAll(name, "--", "-")
+	}
+	return strings.Trim(name, "-")
+}
+
+// isPlaintext reports whether a credential is held by the request or by a
+// variable rather than referenced from the secret store. Only {{secret.NAME}}
+// references count: the value of a {{var}} sits unencrypted in its
+// environment, so "Bearer {{token}}" is as plain as the token itself.
+func isPlaintext(value string) bool {
+	if value == "" {
+		return false
+	}
+	matches := placeholderPattern.FindAllStringSubmatch(value, -1)
+	if len(matches) == 0 {
+		return true
+	}
+	for _, match := range matches {
+		if !strings.HasPrefix(match[1], SecretPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// variableReference returns the first {{var}} placeholder of value that is
+// not a secret reference.
+func variableReference(value string) (string, bool) {
+	for _, match := range placeholderPattern.FindAllStringSubmatch(value, -1) {
+		if !strings.HasPrefix(match[1], SecretPrefix) {
+			return match[0], true
+		}
+	}
+	return "", false
+}
+
+// ReferenceCredentialsAsSecrets turns the {{var}} placeholders of the
+// credential fields into {{secret.var}} references, for requests coming from
+// collections that name their credentials by variable.
+func (req *Request) ReferenceCredentialsAsSecrets() {
+	for _, field := range req.credentialFields() {
+		value := placeholderPattern.ReplaceAllStringFunc(field.value, func(placeholder string) string {
+			name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+			if strings.HasPrefix(name, SecretPrefix) {
+				return placeholder
+			}
+			return SecretRef(strings.ReplaceAll(name, ".", "_"))
+		})
+		if value != field.value {
+			field.set(value)
+		}
+	}
+}
+
+// redactedValue replaces a credential kept out of history.
+const redactedValue = "[redacted]"
+
+// tokenFieldPattern matches the tokens of an OAuth2 token endpoint response.
+var tokenFieldPattern = regexp.MustCompile(`("(?:access_token|refresh_token|id_token)"\s*:\s*)"[^"]*"`)
+
+// Redacted returns a copy of the request with the credentials written out in
+// it replaced, for keeping it in history. Placeholders and the scheme of an
+// Authorization header stay, they hold no credential.
+func (req *Request) Redacted() *Request {
+	redacted := req.Clone()
+	for _, field := range redacted.credentialFields() {
+		prefix, value := "", field.value
+		if scheme, rest, ok := strings.Cut(value, " "); field.scheme && ok && strings.TrimSpace(rest) != "" {
+			prefix, value = scheme+" ", strings.TrimSpace(rest)
+		}
+		if strings.TrimSpace(placeholderPattern.ReplaceAllString(value, "")) != "" {
+			field.set(prefix + redactedValue)
+		}
+	}
+	return redacted
+}
+
+// Redacted returns a copy of the response without the cookies it sets and
+// the tokens of an OAuth2 token endpoint response, for keeping it in
+// history.
+func (resp *Response) Redacted() *Response {
+	redacted := *resp
+	if values := resp.Headers.Values("Set-Cookie"); len(values) > 0 {
+		redacted.Headers = resp.Headers.Clone()
+		for i := range values {
+			redacted.Headers["Set-Cookie"][i] = redactedValue
+		}
+	}
+	redacted.Body = tokenFieldPattern.ReplaceAllString(resp.Body, `$1"`+redactedValue+`"`)
+	return &redacted
+}
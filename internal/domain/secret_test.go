@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretLookup(t *testing.T) {
+	env := &Environment{Variables: map[string]string{"auth": "Bearer {{secret.token}}", "host": "example.com"}}
+	secrets := map[string]string{"token": "s3cret"}
+	lookup := &SecretLookup{
+		Variables: env.Lookup,
+		Secrets: func(name string) (string, error) {
+			if val, ok := secrets[name]; ok {
+				return val, nil
+			}
+			return "", ErrSecretNotFound
+		},
+	}
+
+	req := &Request{
+		URL:     "https://{{host}}/{{secret.token}}",
+		Headers: map[string]string{"Authorization": "{{auth}}", "X-Missing": "{{secret.other}}"},
+	}
+	resolved := req.WithVariables(lookup.Lookup)
+
+	assert.Equal(t, "https://example.com/s3cret", resolved.URL)
+	assert.Equal(t, "Bearer s3cret", resolved.Headers["Authorization"])
+	assert.True(t, errors.Is(lookup.Err, ErrSecretNotFound))
+	assert.ErrorContains(t, lookup.Err, `secret "other"`)
+}
+
+func TestValidateSecretName(t *testing.T) {
+	assert.NoError(t, ValidateSecretName("github_token-2"))
+	assert.Error(t, ValidateSecretName(""))
+	assert.Error(t, ValidateSecretName("a.b"))
+	assert.Error(t, ValidateSecretName("two words"))
+	assert.Equal(t, "{{secret.token}}", SecretRef("token"))
+}
+
+func TestPlaintextCredentials(t *testing.T) {
+	req := &Request{
+		Headers: map[string]string{
+			"authorization": "Bearer abc",
+			"X-Api-Key":     "{{secret.key}}",
+			"Accept":        "application/json",
+		},
+		Auth: &Auth{Type: AuthOAuth2, ClientID: "id", ClientSecret: "plain", Password: "{{secret.pw}}"},
+	}
+
+	assert.Equal(t, []string{"authorization header", "client secret"}, req.PlaintextCredentials())
+	assert.Empty(t, (&Request{Headers: map[string]string{"Authorization": "Bearer {{secret.token}}"}}).PlaintextCredentials())
+
+	// the value of a variable is stored unencrypted in its environment
+	fromVariable := &Request{Headers: map[string]string{"Authorization": "Bearer {{token}}"}}
+	assert.Equal(t, []string{"Authorization header"}, fromVariable.PlaintextCredentials())
+	assert.ErrorContains(t, fromVariable.CheckCredentialVariables(), "the Authorization header uses {{token}}")
+	assert.ErrorContains(t, fromVariable.MoveCredentials(func(name, value string) (string, error) {
+		t.Fatal("a variable reference is not moved")
+		return "", nil
+	}), "reference credentials with {{secret.NAME}}")
+}
+
+func TestReferenceCredentialsAsSecrets(t *testing.T) {
+	req := &Request{
+		URL:     "{{base}}/users",
+		Headers: map[string]string{"Authorization": "Bearer {{auth.token}}", "Accept": "{{accept}}"},
+		Auth:    &Auth{Type: AuthBasic, Username: "{{user}}", Password: "{{secret.pw}}"},
+	}
+	req.ReferenceCredentialsAsSecrets()
+
+	assert.Equal(t, "Bearer {{secret.auth_token}}", req.Headers["Authorization"])
+	assert.Equal(t, "{{accept}}", req.Headers["Accept"])
+	assert.Equal(t, "{{base}}/users", req.URL)
+	assert.Equal(t, "{{user}}", req.Auth.Username)
+	assert.Equal(t, "{{secret.pw}}", req.Auth.Password)
+	assert.Empty(t, req.PlaintextCredentials())
+}
+
+func TestMoveCredentials(t *testing.T) {
+	req := &Request{
+		Name: "Create Charge",
+		Headers: map[string]string{
+			"Authorization": "Bearer abc",
+			"Cookie":        "session=1; theme=dark",
+			"X-Api-Key":     "{{secret.key}}",
+		},
+		Auth: &Auth{Type: AuthBasic, Username: "me", Password: "hunter2"},
+	}
+
+	stored := make(map[string]string)
+	err := req.MoveCredentials(func(name, value string) (string, error) {
+		stored[name] = value
+		return name, nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"create-charge-authorization": "abc",
+		"create-charge-cookie":        "session=1; theme=dark",
+		"create-charge-auth-password": "hunter2",
+	}, stored)
+	assert.Equal(t, "Bearer {{secret.create-charge-authorization}}", req.Headers["Authorization"])
+	assert.Equal(t, "{{secret.create-charge-cookie}}", req.Headers["Cookie"])
+	assert.Equal(t, "{{secret.key}}", req.Headers["X-Api-Key"])
+	assert.Equal(t, "{{secret.create-charge-auth-password}}", req.Auth.Password)
+	assert.Empty(t, req.PlaintextCredentials())
+
+	req.Headers["Authorization"] = "Bearer new"
+	err = req.MoveCredentials(func(name, value string) (string, error) {
+		return "", errors.New("locked")
+	})
+	assert.ErrorContains(t, err, "could not store the Authorization header: locked")
+	assert.Equal(t, "Bearer new", req.Headers["Authorization"])
+}
+
+func TestRedacted(t *testing.T) {
+	req := &Request{
+		Headers: map[string]string{
+			"Authorization": "Bearer abc",
+			"Cookie":        "session=1",
+			"X-Api-Key":     "{{secret.key}}",
+			"Accept":        "text/plain",
+		},
+		Auth: &Auth{Type: AuthOAuth2, ClientID: "app", ClientSecret: "hunter2"},
+	}
+	redacted := req.Redacted()
+
+	assert.Equal(t, "Bearer [redacted]", redacted.Headers["Authorization"])
+	assert.Equal(t, "[redacted]", redacted.Headers["Cookie"])
+	assert.Equal(t, "{{secret.key}}", redacted.Headers["X-Api-Key"])
+	assert.Equal(t, "text/plain", redacted.Headers["Accept"])
+	assert.Equal(t, "app", redacted.Auth.ClientID)
+	assert.Equal(t, "[redacted]", redacted.Auth.ClientSecret)
+	assert.Equal(t, "Bearer abc", req.Headers["Authorization"])
+	assert.Equal(t, "hunter2", req.Auth.ClientSecret)
+
+	resp := &Response{
+		Headers: http.Header{"Set-Cookie": {"session=1"}, "Content-Type": {"application/json"}},
+		Body:    `{"access_token": "abc", "token_type": "Bearer", "refresh_token":"def"}`,
+	}
+	redactedResp := resp.Redacted()
+
+	assert.Equal(t, "[redacted]", redactedResp.Headers.Get("Set-Cookie"))
+	assert.Equal(t, "application/json", redactedResp.Headers.Get("Content-Type"))
+	assert.Equal(t, `{"access_token": "[redacted]", "token_type": "Bearer", "refresh_token":"[redacted]"}`, redactedResp.Body)
+	assert.Equal(t, "session=1", resp.Headers.Get("Set-Cookie"))
+}
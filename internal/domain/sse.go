@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ContentTypeEventStream = "text/event-stream"
+
+// Event is a Server-Sent Event. A streamed body that is not an event stream
+// arrives as events with only Data set, one per chunk read.
+type Event struct {
+	ID   string `json:"id,omitempty"`
+	Type string `json:"type,omitempty"`
+	Data string `json:"data"`
+	// Retry is the reconnection delay the server last asked for
+	Retry      time.Duration `json:"retry,omitempty"`
+	ReceivedAt time.Time     `json:"received_at"`
+}
+
+// IsChunk reports whether the event is a chunk of a plain streamed body.
+func (e Event) IsChunk() bool {
+	return e.Type == ""
+}
+
+// EventReader parses a text/event-stream body following the HTML standard:
+// lines end in CR, LF or CRLF, lines starting with a colon are comments, data
+// lines are joined with newlines and a blank line dispatches the event.
+type EventReader struct {
+	r       *bufio.Reader
+	started bool
+	skipLF  bool
+	lastID  string
+	retry   time.Duration
+}
+
+func NewEventReader(r io.Reader) *EventReader {
+	return &EventReader{r: bufio.NewReader(r)}
+}
+
+// Next blocks until the next event arrives and returns io.EOF when the
+// stream ends. An event cut off by the end of the stream is dropped.
+func (er *EventReader) Next() (Event, error) {
+	var data strings.Builder
+	var eventType string
+	hasData := false
+
+	for {
+		line, err := er.readLine()
+		if err != nil {
+			return Event{}, err
+		}
+
+		if line == "" {
+			if !hasData {
+				eventType = ""
+				continue
+			}
+			if eventType == "" {
+				eventType = "message"
+			}
+			return Event{
+				ID:         er.lastID,
+				Type:       eventType,
+				Data:       strings.TrimSuffix(data.String(), "\n"),
+				Retry:      er.retry,
+				ReceivedAt: time.Now(),
+			}, nil
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				er.lastID = value
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(value, 10, 32); err == nil {
+				er.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
+
+// readLine reads up to the next line ending. A CR is taken as the end of the
+// line straight away, a LF after it is skipped on the next read, so an event
+// ending in CR is not held back waiting for more data.
+func (er *EventReader) readLine() (string, error) {
+	var line []byte
+	for {
+		b, err := er.r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		if er.skipLF {
+			er.skipLF = false
+			if b == '\n' {
+				continue
+			}
+		}
+		switch b {
+		case '\r':
+			er.skipLF = true
+			return er.firstLine(line), nil
+		case '\n':
+			return er.firstLine(line), nil
+		}
+		line = append(line, b)
+	}
+}
+
+// firstLine drops the byte order mark the stream may start with.
+func (er *EventReader) firstLine(line []byte) string {
+	if !er.started {
+		er.started = true
+		return strings.TrimPrefix(string(line), "\ufeff")
+	}
+	return string(line)
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readAllEvents(t *testing.T, stream string) []Event {
+	t.Helper()
+	reader := NewEventReader(strings.NewReader(stream))
+	var events []Event
+	for {
+		event, err := reader.Next()
+		if err == io.EOF {
+			return events
+		}
+		require.NoError(t, err)
+		event.ReceivedAt = time.Time{}
+		events = append(events, event)
+	}
+}
+
+func TestEventReader(t *testing.T) {
+	tests := []struct {
+		name     string
+		stream   string
+		expected []Event
+	}{
+		{
+			name:     "single data line",
+			stream:   "data: hello\n\n",
+			expected: []Event{{Type: "message", Data: "hello"}},
+		},
+		{
+			name:     "data lines are joined",
+			stream:   "data: first\ndata: second\n\n",
+			expected: []Event{{Type: "message", Data: "first\nsecond"}},
+		},
+		{
+			name:   "fields",
+			stream: "id: 7\nevent: update\nretry: 1500\ndata: {\"n\":1}\n\n",
+			expected: []Event{
+				{ID: "7", Type: "update", Data: `{"n":1}`, Retry: 1500 * time.Millisecond},
+			},
+		},
+		{
+			name:   "id and retry carry over",
+			stream: "id: 1\nretry: 200\ndata: a\n\ndata: b\n\nid\ndata: c\n\n",
+			expected: []Event{
+				{ID: "1", Type: "message", Data: "a", Retry: 200 * time.Millisecond},
+				{ID: "1", Type: "message", Data: "b", Retry: 200 * time.Millisecond},
+				{ID: "", Type: "message", Data: "c", Retry: 200 * time.Millisecond},
+			},
+		},
+		{
+			name:     "comments and unknown fields are ignored",
+			stream:   ": keep-alive\nfoo: bar\ndata: x\n\n",
+			expected: []Event{{Type: "message", Data: "x"}},
+		},
+		{
+			name:     "event without data is not dispatched",
+			stream:   "event: ping\n\ndata: x\n\n",
+			expected: []Event{{Type: "message", Data: "x"}},
+		},
+		{
+			name:     "only one leading space is stripped",
+			stream:   "data:no space\n\ndata:  two spaces\n\n",
+			expected: []Event{{Type: "message", Data: "no space"}, {Type: "message", Data: " two spaces"}},
+		},
+		{
+			name:     "empty data field",
+			stream:   "data\n\n",
+			expected: []Event{{Type: "message", Data: ""}},
+		},
+		{
+			name:     "CR and CRLF line endings",
+			stream:   "data: a\r\rdata: b\r\n\r\n",
+			expected: []Event{{Type: "message", Data: "a"}, {Type: "message", Data: "b"}},
+		},
+		{
+			name:     "byte order mark",
+			stream:   "\ufeffdata: x\n\n",
+			expected: []Event{{Type: "message", Data: "x"}},
+		},
+		{
+			name:     "invalid retry and id with NUL are ignored",
+			stream:   "retry: soon\nid: a\x00b\ndata: x\n\n",
+			expected: []Event{{Type: "message", Data: "x"}},
+		},
+		{
+			name:     "unfinished event is dropped",
+			stream:   "data: x\n\ndata: cut off\n",
+			expected: []Event{{Type: "message", Data: "x"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, readAllEvents(t, tt.stream))
+		})
+	}
+}
+
+func TestEventReaderDoesNotWaitAfterCR(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer func() { _ = pw.Close() }()
+	go func() { _, _ = pw.Write([]byte("data: x\r\r")) }()
+
+	event, err := NewEventReader(pr).Next()
+	require.NoError(t, err)
+	assert.Equal(t, "x", event.Data)
+}
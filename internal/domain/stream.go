@@ -0,0 +1,147 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const streamChunkSize = 32 << 10
+
+// StreamStats sums up a response read as a stream.
+type StreamStats struct {
+	Events   int           `json:"events"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Rate is the number of events received per second.
+func (s *StreamStats) Rate() float64 {
+	return EventRate(s.Events, s.Duration)
+}
+
+func EventRate(events int, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(events) / elapsed.Seconds()
+}
+
+// IsStream reports whether the response should be read as a stream, set
+// with the stream option or by asking for an event stream in Accept.
+func (req *Request) IsStream() bool {
+	if req.Options != nil && req.Options.Stream {
+		return true
+	}
+	for key, value := range req.Headers {
+		if strings.EqualFold(key, "Accept") && strings.Contains(value, ContentTypeEventStream) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEventStream reports whether the response body is a text/event-stream.
+func (resp *Response) IsEventStream() bool {
+	mediaType, _, _ := mime.ParseMediaType(resp.ContentType)
+	return mediaType == ContentTypeEventStream
+}
+
+// BuildStream fills resp like BuildResponse but hands the body to onEvent as
+// it arrives, parsed into events for an event stream and in chunks for any
+// other body. Body keeps the first maxMemory bytes as received, all of them
+// when it is zero. It returns once the server ends the body or reading it
+// fails, Stream holds what arrived either way.
+func (resp *Response) BuildStream(httpR *http.Response, maxMemory int64, onEvent func(Event)) error {
+	resp.buildHead(httpR)
+	stats := &StreamStats{}
+	resp.Stream = stats
+	if httpR.Body == nil {
+		return nil
+	}
+
+	head := &headBuffer{max: maxMemory}
+	body := io.TeeReader(httpR.Body, head)
+	emit := func(event Event) {
+		stats.Events++
+		onEvent(event)
+	}
+
+	start := time.Now()
+	var err error
+	if resp.IsEventStream() {
+		err = readEvents(body, emit)
+	} else {
+		err = readChunks(body, emit)
+	}
+	stats.Duration = time.Since(start)
+
+	resp.BodySize = head.size
+	resp.Truncated = head.size > int64(head.buf.Len())
+	resp.Binary = IsBinary(resp.ContentType, head.buf.Bytes())
+	resp.Body = head.buf.String()
+	if resp.Truncated {
+		resp.Body = string(trimPartialRune(head.buf.Bytes()))
+	}
+
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+func readEvents(body io.Reader, emit func(Event)) error {
+	events := NewEventReader(body)
+	for {
+		event, err := events.Next()
+		if err != nil {
+			return err
+		}
+		emit(event)
+	}
+}
+
+// readChunks passes on whatever each read returns, holding back a rune split
+// between reads so every chunk is valid text.
+func readChunks(body io.Reader, emit func(Event)) error {
+	buf := make([]byte, streamChunkSize)
+	var pending []byte
+	for {
+		n, err := body.Read(buf)
+		if n > 0 {
+			chunk := append(pending, buf[:n]...)
+			complete := trimPartialRune(chunk)
+			pending = bytes.Clone(chunk[len(complete):])
+			if len(complete) > 0 {
+				emit(Event{Data: string(complete), ReceivedAt: time.Now()})
+			}
+		}
+		if err != nil {
+			if len(pending) > 0 {
+				emit(Event{Data: string(pending), ReceivedAt: time.Now()})
+			}
+			return err
+		}
+	}
+}
+
+// headBuffer keeps the first max bytes written to it and counts the rest.
+type headBuffer struct {
+	buf  bytes.Buffer
+	max  int64
+	size int64
+}
+
+func (h *headBuffer) Write(p []byte) (int, error) {
+	h.size += int64(len(p))
+	if h.max <= 0 {
+		return h.buf.Write(p)
+	}
+	if room := h.max - int64(h.buf.Len()); room > 0 {
+		h.buf.Write(p[:min(int64(len(p)), room)])
+	}
+	return len(p), nil
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildStreamEvents(t *testing.T) {
+	stream := "event: tick\ndata: 1\n\nevent: tick\ndata: 2\n\n"
+
+	var events []Event
+	resp := &Response{}
+	require.NoError(t, resp.BuildStream(newBodyResponse("text/event-stream; charset=utf-8", stream), 0, func(e Event) {
+		events = append(events, e)
+	}))
+
+	require.Len(t, events, 2)
+	assert.Equal(t, "tick", events[1].Type)
+	assert.Equal(t, "2", events[1].Data)
+	assert.False(t, events[0].IsChunk())
+	assert.Equal(t, 2, resp.Stream.Events)
+	assert.Equal(t, stream, resp.Body)
+	assert.Equal(t, int64(len(stream)), resp.BodySize)
+	assert.False(t, resp.Truncated)
+}
+
+func TestBuildStreamChunks(t *testing.T) {
+	pr, pw := io.Pipe()
+	httpResp := newBodyResponse("text/plain", "")
+	httpResp.Body = pr
+	go func() {
+		_, _ = pw.Write([]byte("first line\n"))
+		// a rune split across writes is held back until it is whole
+		_, _ = pw.Write([]byte("caf\xc3"))
+		_, _ = pw.Write([]byte("\xa9\n"))
+		_ = pw.Close()
+	}()
+
+	var chunks []string
+	resp := &Response{}
+	require.NoError(t, resp.BuildStream(httpResp, 8, func(e Event) {
+		assert.True(t, e.IsChunk())
+		chunks = append(chunks, e.Data)
+	}))
+
+	assert.Equal(t, []string{"first line\n", "caf", "é\n"}, chunks)
+	assert.Equal(t, "first li", resp.Body)
+	assert.True(t, resp.Truncated)
+	assert.Equal(t, int64(17), resp.BodySize)
+}
+
+func TestRequestIsStream(t *testing.T) {
+	req := NewRequest()
+	assert.False(t, req.IsStream())
+
+	req.Headers["accept"] = "text/event-stream"
+	assert.True(t, req.IsStream())
+
+	req = NewRequest()
+	req.Options = &ClientOptions{Stream: true}
+	assert.True(t, req.IsStream())
+}
+
+func TestEventRate(t *testing.T) {
+	assert.Equal(t, 0.0, EventRate(5, 0))
+	assert.Equal(t, 2.5, (&StreamStats{Events: 5, Duration: 2 * time.Second}).Rate())
+}
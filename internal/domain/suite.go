@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+type TestCase struct {
+	Name     string            `json:"name"`
+	Request  *Request          `json:"request"`
+	Response *Response         `json:"response,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Results  []AssertionResult `json:"results,omitempty"`
+	Duration time.Duration     `json:"duration"`
+}
+
+// Passed reports whether the request succeeded and every assertion held.
+// Requests without assertions pass when the response status is 2xx.
+func (tc *TestCase) Passed() bool {
+	if tc.Error != "" || tc.Response == nil {
+		return false
+	}
+	if len(tc.Results) == 0 {
+		return tc.Response.Success()
+	}
+	return AssertionsPassed(tc.Results)
+}
+
+type SuiteResult struct {
+	Name     string        `json:"name"`
+	Cases    []*TestCase   `json:"cases"`
+	Duration time.Duration `json:"duration"`
+}
+
+func (s *SuiteResult) PassedCount() int {
+	count := 0
+	for _, tc := range s.Cases {
+		if tc.Passed() {
+			count++
+		}
+	}
+	return count
+}
+
+func (s *SuiteResult) FailedCount() int {
+	return len(s.Cases) - s.PassedCount()
+}
+
+func (s *SuiteResult) Passed() bool {
+	return s.FailedCount() == 0
+}
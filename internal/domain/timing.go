@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	PhaseRedirects = "redirects"
+	PhaseDNS       = "dns"
+	PhaseConnect   = "connect"
+	PhaseTLS       = "tls"
+	PhaseTTFB      = "ttfb"
+	PhaseTransfer  = "transfer"
+)
+
+// TimingPhase is one step of a request, Start is the offset from the moment
+// the request was sent.
+type TimingPhase struct {
+	Name     string        `json:"name"`
+	Start    time.Duration `json:"start"`
+	Duration time.Duration `json:"duration"`
+}
+
+func (p TimingPhase) End() time.Duration {
+	return p.Start + p.Duration
+}
+
+// Timing breaks the response time down into phases. Phases that did not
+// happen, like DNS on a reused connection, are left out.
+type Timing struct {
+	Phases     []TimingPhase `json:"phases"`
+	Total      time.Duration `json:"total"`
+	ConnReused bool          `json:"conn_reused,omitempty"`
+}
+
+func (t *Timing) Phase(name string) (TimingPhase, bool) {
+	for _, p := range t.Phases {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return TimingPhase{}, false
+}
+
+func (t *Timing) String() string {
+	parts := make([]string, 0, len(t.Phases))
+	for _, p := range t.Phases {
+		parts = append(parts, fmt.Sprintf("%s %s", p.Name, p.Duration.Round(time.Microsecond)))
+	}
+	if t.ConnReused {
+		parts = append(parts, "connection reused")
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTiming(t *testing.T) {
+	timing := &Timing{
+		Phases: []TimingPhase{
+			{Name: PhaseConnect, Start: 0, Duration: 2 * time.Millisecond},
+			{Name: PhaseTTFB, Start: 2 * time.Millisecond, Duration: 5 * time.Millisecond},
+		},
+		Total:      8 * time.Millisecond,
+		ConnReused: true,
+	}
+
+	ttfb, ok := timing.Phase(PhaseTTFB)
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Millisecond, ttfb.End())
+
+	_, ok = timing.Phase(PhaseDNS)
+	assert.False(t, ok)
+
+	assert.Equal(t, "connect 2ms, ttfb 5ms, connection reused", timing.String())
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// MethodWebSocket marks a request that opens a WebSocket instead of sending
+// an HTTP request. URLs starting with ws:// or wss:// do the same.
+const MethodWebSocket = "WS"
+
+const (
+	FrameText   = "text"
+	FrameBinary = "binary"
+	FramePing   = "ping"
+	FramePong   = "pong"
+	FrameClose  = "close"
+)
+
+// WSMessage is a message sent over a WebSocket, as a text frame unless
+// Binary is set. The Messages of a request are its script, sent in order
+// once it connects.
+type WSMessage struct {
+	Text   string `json:"text,omitempty"`
+	Binary []byte `json:"binary,omitempty"`
+}
+
+func (m WSMessage) IsBinary() bool {
+	return m.Binary != nil
+}
+
+// WSFrame is a frame sent or received on a WebSocket connection.
+type WSFrame struct {
+	Sent bool      `json:"sent"`
+	Type string    `json:"type"`
+	Data []byte    `json:"data,omitempty"`
+	Code int       `json:"code,omitempty"`
+	Time time.Time `json:"time"`
+}
+
+// Label describes the frame without its data, such as "text 12 B" or
+// "close 1000 normal closure".
+func (f WSFrame) Label() string {
+	switch f.Type {
+	case FrameClose:
+		return fmt.Sprintf("close %d %s", f.Code, CloseCodeText(f.Code))
+	case FrameText, FrameBinary:
+		return f.Type + " " + FormatBytes(int64(len(f.Data)))
+	default:
+		return f.Type
+	}
+}
+
+// Payload renders the frame data for display, binary data as a hex dump.
+func (f WSFrame) Payload() string {
+	if f.Type == FrameBinary {
+		return strings.TrimSuffix(hex.Dump(f.Data[:min(len(f.Data), HexPreviewSize)]), "\n")
+	}
+	return string(f.Data)
+}
+
+var closeCodeTexts = map[int]string{
+	1000: "normal closure",
+	1001: "going away",
+	1002: "protocol error",
+	1003: "unsupported data",
+	1005: "no status",
+	1006: "abnormal closure",
+	1007: "invalid payload",
+	1008: "policy violation",
+	1009: "message too big",
+	1010: "missing extension",
+	1011: "internal error",
+	1012: "service restart",
+	1013: "try again later",
+	1015: "TLS handshake failure",
+}
+
+// CloseCodeText names the standard close codes from RFC 6455.
+func CloseCodeText(code int) string {
+	if text, ok := closeCodeTexts[code]; ok {
+		return text
+	}
+	if code >= 4000 && code < 5000 {
+		return "application defined"
+	}
+	return "unknown"
+}
+
+// IsWebSocket reports whether the request opens a WebSocket.
+func (req *Request) IsWebSocket() bool {
+	return req.Method == MethodWebSocket || strings.HasPrefix(req.URL, "ws://") || strings.HasPrefix(req.URL, "wss://")
+}
+
+// WSMessage returns the body as a message to send: Text and JSON bodies as
+// text frames and a File body as a binary frame with the file contents. ok
+// is false when there is nothing to send.
+func (req *Request) WSMessage() (msg WSMessage, ok bool, err error) {
+	switch {
+	case req.BodyFile != "":
+		data, err := os.ReadFile(req.BodyFile)
+		if err != nil {
+			return WSMessage{}, false, fmt.Errorf("could not read message file: %w", err)
+		}
+		return WSMessage{Binary: data}, true, nil
+	case len(req.Form) > 0:
+		return WSMessage{}, false, errors.New("form bodies cannot be sent over a WebSocket")
+	case req.Body != "":
+		return WSMessage{Text: req.Body}, true, nil
+	}
+	return WSMessage{}, false, nil
+}
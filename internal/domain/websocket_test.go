@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWSFrameLabel(t *testing.T) {
+	assert.Equal(t, "text 5 B", WSFrame{Type: FrameText, Data: []byte("hello")}.Label())
+	assert.Equal(t, "close 1000 normal closure", WSFrame{Type: FrameClose, Code: 1000}.Label())
+	assert.Equal(t, "close 4001 application defined", WSFrame{Type: FrameClose, Code: 4001}.Label())
+	assert.Equal(t, "ping", WSFrame{Type: FramePing}.Label())
+	assert.Equal(t, "00000000  00 ff                                             |..|",
+		WSFrame{Type: FrameBinary, Data: []byte{0, 255}}.Payload())
+}
+
+func TestRequestIsWebSocket(t *testing.T) {
+	cfg := &config.Config{App: config.AppConfig{DefaultPort: "8080"}}
+
+	req := NewRequest()
+	require.NoError(t, req.BuildRequest("", "WS", "/socket", "", "", "Text", "", cfg))
+	assert.True(t, req.IsWebSocket())
+	assert.Equal(t, "http://localhost:8080/socket", req.URL)
+
+	req = NewRequest()
+	require.NoError(t, req.BuildRequest("", "GET", "wss://example.com/feed", "", "", "Text", "", cfg))
+	assert.True(t, req.IsWebSocket())
+	assert.Equal(t, "wss://example.com/feed", req.URL)
+
+	req = NewRequest()
+	require.NoError(t, req.BuildRequest("", "GET", "/socket", "", "", "Text", "", cfg))
+	assert.False(t, req.IsWebSocket())
+}
+
+func TestRequestWSMessage(t *testing.T) {
+	req := &Request{}
+	_, ok, err := req.WSMessage()
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	req = &Request{Body: `{"op":"subscribe"}`}
+	msg, ok, err := req.WSMessage()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, WSMessage{Text: `{"op":"subscribe"}`}, msg)
+
+	path := filepath.Join(t.TempDir(), "frame.bin")
+	require.NoError(t, os.WriteFile(path, []byte{1, 2, 3}, 0o644))
+	req = &Request{BodyFile: path}
+	msg, ok, err = req.WSMessage()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, msg.IsBinary())
+	assert.Equal(t, []byte{1, 2, 3}, msg.Binary)
+
+	req = &Request{Form: []FormField{{Key: "a", Value: "b"}}}
+	_, _, err = req.WSMessage()
+	assert.ErrorContains(t, err, "form bodies")
+}
@@ -0,0 +1,186 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatPostman  = "postman"
+	FormatInsomnia = "insomnia"
+	FormatOpenAPI  = "openapi"
+)
+
+type Skipped struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+type Result struct {
+	Format   string            `json:"format"`
+	Requests []*domain.Request `json:"requests"`
+	Skipped  []Skipped         `json:"skipped"`
+
+	names map[string]int
+}
+
+func newResult(format string) *Result {
+	return &Result{
+		Format:   format,
+		Requests: []*domain.Request{},
+		Skipped:  []Skipped{},
+		names:    make(map[string]int),
+	}
+}
+
+func (r *Result) add(req *domain.Request) {
+	req.Name = r.uniqueName(req.Name)
+	r.Requests = append(r.Requests, req)
+}
+
+func (r *Result) skip(name, reason string, args ...any) {
+	r.Skipped = append(r.Skipped, Skipped{Name: name, Reason: fmt.Sprintf(reason, args...)})
+}
+
+// uniqueName lowercases the name like the request form does and suffixes
+// duplicates, since saved requests are keyed by name.
+func (r *Result) uniqueName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = "unnamed"
+	}
+
+	r.names[name]++
+	if r.names[name] == 1 {
+		return name
+	}
+
+	for {
+		candidate := fmt.Sprintf("%s (%d)", name, r.names[name])
+		if _, taken := r.names[candidate]; !taken {
+			r.names[candidate] = 1
+			return candidate
+		}
+		r.names[name]++
+	}
+}
+
+// ImportFile reads a Postman, Insomnia or OpenAPI file and converts it.
+func ImportFile(path string, cfg *config.Config) (*Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read import file: %v", err)
+	}
+	return Import(data, cfg)
+}
+
+// Import detects the format of data and converts it into requests.
+func Import(data []byte, cfg *config.Config) (*Result, error) {
+	format, err := DetectFormat(data)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatPostman:
+		return ImportPostman(data, cfg)
+	case FormatInsomnia:
+		return ImportInsomnia(data, cfg)
+	default:
+		return ImportOpenAPI(data, cfg)
+	}
+}
+
+func DetectFormat(data []byte) (string, error) {
+	var probe struct {
+		Info struct {
+			Schema string `yaml:"schema"`
+		} `yaml:"info"`
+		Type         string `yaml:"_type"`
+		ExportFormat int    `yaml:"__export_format"`
+		OpenAPI      string `yaml:"openapi"`
+		Swagger      string `yaml:"swagger"`
+	}
+
+	// JSON is valid YAML, so one decoder covers every supported format
+	if err := yaml.Unmarshal(data, &probe); err != nil {
+		return "", fmt.Errorf("could not parse import file: %v", err)
+	}
+
+	switch {
+	case strings.Contains(probe.Info.Schema, "schema.getpostman.com"):
+		if !strings.Contains(probe.Info.Schema, "/v2.") {
+			return "", fmt.Errorf("unsupported Postman collection schema %s", probe.Info.Schema)
+		}
+		return FormatPostman, nil
+	case probe.Type == "export":
+		if probe.ExportFormat != 4 {
+			return "", fmt.Errorf("unsupported Insomnia export format %d", probe.ExportFormat)
+		}
+		return FormatInsomnia, nil
+	case strings.HasPrefix(probe.OpenAPI, "3."):
+		return FormatOpenAPI, nil
+	case probe.OpenAPI != "" || probe.Swagger != "":
+		return "", errors.New("only OpenAPI 3 specs are supported")
+	default:
+		return "", errors.New("unrecognized import file, expected a Postman v2.1 collection, an Insomnia export or an OpenAPI 3 spec")
+	}
+}
+
+func newImportedRequest(name, method, rawURL string, cfg *config.Config) (*domain.Request, error) {
+	req := domain.NewRequest()
+	req.Name = name
+
+	if err := req.ParseMethod(method); err != nil {
+		return nil, err
+	}
+
+	base, query, _ := strings.Cut(strings.TrimSpace(rawURL), "?")
+	rest, err := req.AddQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query string: %v", err)
+	}
+	if rest != "" {
+		base += "?" + rest
+	}
+	if err := req.ParseUrl(cfg, base); err != nil {
+		return nil, err
+	}
+
+	if err := req.ParseHeaders(""); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func setHeader(req *domain.Request, key, val string) {
+	if strings.EqualFold(key, "Content-Type") {
+		req.ContentType["Content-Type"] = val
+		return
+	}
+	req.Headers[key] = val
+}
+
+type formField struct {
+	key   string
+	value string
+}
+
+// placeholders survive encoding so environments can still expand them
+var placeholderUnescaper = strings.NewReplacer("%7B", "{", "%7D", "}")
+
+func encodeForm(fields []formField) string {
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		parts = append(parts, placeholderUnescaper.Replace(url.QueryEscape(field.key)+"="+url.QueryEscape(field.value)))
+	}
+	return strings.Join(parts, "&")
+}
@@ -0,0 +1,81 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+var testConfig = &config.Config{App: config.AppConfig{DefaultPort: "8080"}}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:     "Postman v2.1",
+			input:    `{"info": {"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"}}`,
+			expected: FormatPostman,
+		},
+		{
+			name:        "Postman v1",
+			input:       `{"info": {"schema": "https://schema.getpostman.com/json/collection/v1.0.0/collection.json"}}`,
+			expectError: true,
+		},
+		{
+			name:     "Insomnia v4",
+			input:    `{"_type": "export", "__export_format": 4, "resources": []}`,
+			expected: FormatInsomnia,
+		},
+		{
+			name:     "OpenAPI YAML",
+			input:    "openapi: 3.0.3\npaths: {}\n",
+			expected: FormatOpenAPI,
+		},
+		{
+			name:        "Swagger 2",
+			input:       `{"swagger": "2.0"}`,
+			expectError: true,
+		},
+		{
+			name:        "Unknown file",
+			input:       `{"hello": "world"}`,
+			expectError: true,
+		},
+		{
+			name:        "Invalid file",
+			input:       `{"hello": `,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := DetectFormat([]byte(tt.input))
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, format)
+		})
+	}
+}
+
+func TestUniqueName(t *testing.T) {
+	result := newResult(FormatPostman)
+
+	assert.Equal(t, "users", result.uniqueName("Users"))
+	assert.Equal(t, "users (2)", result.uniqueName("users"))
+	assert.Equal(t, "users (3)", result.uniqueName("USERS"))
+	assert.Equal(t, "unnamed", result.uniqueName("  "))
+}
+
+func TestEncodeFormKeepsPlaceholders(t *testing.T) {
+	body := encodeForm([]formField{{key: "user", value: "{{name}}"}, {key: "note", value: "a b&c"}})
+	assert.Equal(t, "user={{name}}&note=a+b%26c", body)
+}
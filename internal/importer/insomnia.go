@@ -0,0 +1,234 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/ManoloEsS/burrow/internal/domain"
+)
+
+// insomnia templates reference environment variables as {{ _.name }}
+var insomniaVariablePattern = regexp.MustCompile(`\{\{\s*_\.([A-Za-z0-9_.\-]+)\s*\}\}`)
+
+// resources that hold no requests and are ignored without being reported
+var insomniaIgnoredTypes = map[string]bool{
+	"workspace":       true,
+	"environment":     true,
+	"cookie_jar":      true,
+	"api_spec":        true,
+	"proto_file":      true,
+	"proto_directory": true,
+	"unit_test_suite": true,
+	"unit_test":       true,
+}
+
+type insomniaExport struct {
+	Resources []insomniaResource `json:"resources"`
+}
+
+type insomniaResource struct {
+	ID             string         `json:"_id"`
+	Type           string         `json:"_type"`
+	ParentID       string         `json:"parentId"`
+	Name           string         `json:"name"`
+	Method         string         `json:"method"`
+	URL            string         `json:"url"`
+	Body           insomniaBody   `json:"body"`
+	Headers        []insomniaPair `json:"headers"`
+	Parameters     []insomniaPair `json:"parameters"`
+	Authentication *insomniaAuth  `json:"authentication"`
+}
+
+type insomniaPair struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled"`
+}
+
+type insomniaBody struct {
+	MimeType string         `json:"mimeType"`
+	Text     string         `json:"text"`
+	Params   []insomniaPair `json:"params"`
+}
+
+type insomniaAuth struct {
+	Type     string `json:"type"`
+	Disabled bool   `json:"disabled"`
+	Token    string `json:"token"`
+	Prefix   string `json:"prefix"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	AddTo    string `json:"addTo"`
+
+	GrantType      string `json:"grantType"`
+	AccessTokenURL string `json:"accessTokenUrl"`
+	ClientID       string `json:"clientId"`
+	ClientSecret   string `json:"clientSecret"`
+	Scope          string `json:"scope"`
+}
+
+// ImportInsomnia converts an Insomnia v4 export, naming requests after
+// their folder path.
+func ImportInsomnia(data []byte, cfg *config.Config) (*Result, error) {
+	var export insomniaExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("could not parse Insomnia export: %v", err)
+	}
+
+	groups := make(map[string]insomniaResource)
+	for _, res := range export.Resources {
+		if res.Type == "request_group" {
+			groups[res.ID] = res
+		}
+	}
+
+	result := newResult(FormatInsomnia)
+	for _, res := range export.Resources {
+		if res.Type == "request_group" || insomniaIgnoredTypes[res.Type] {
+			continue
+		}
+
+		name := insomniaPath(groups, res)
+		if res.Type != "request" {
+			result.skip(name, "unsupported resource type %q", res.Type)
+			continue
+		}
+
+		req, err := convertInsomniaRequest(name, res, cfg)
+		if err != nil {
+			result.skip(name, "%v", err)
+			continue
+		}
+		result.add(req)
+	}
+
+	return result, nil
+}
+
+func insomniaPath(groups map[string]insomniaResource, res insomniaResource) string {
+	parts := []string{res.Name}
+	seen := map[string]bool{}
+	for parent, ok := groups[res.ParentID]; ok && !seen[parent.ID]; parent, ok = groups[parent.ParentID] {
+		seen[parent.ID] = true
+		parts = append([]string{parent.Name}, parts...)
+	}
+	return strings.Join(parts, "/")
+}
+
+func convertInsomniaRequest(name string, res insomniaResource, cfg *config.Config) (*domain.Request, error) {
+	method := res.Method
+	if method == "" {
+		method = "GET"
+	}
+
+	req, err := newImportedRequest(name, method, insomniaTemplate(res.URL), cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range res.Parameters {
+		if !p.Disabled && p.Name != "" {
+			req.Params[p.Name] = insomniaTemplate(p.Value)
+		}
+	}
+
+	for _, h := range res.Headers {
+		if !h.Disabled && h.Name != "" {
+			setHeader(req, h.Name, insomniaTemplate(h.Value))
+		}
+	}
+
+	if err := applyInsomniaAuth(req, res.Authentication); err != nil {
+		return nil, err
+	}
+
+	mimeType := res.Body.MimeType
+	switch {
+	case mimeType == "" && res.Body.Text == "":
+	case mimeType == "application/x-www-form-urlencoded":
+		var fields []formField
+		for _, p := range res.Body.Params {
+			if !p.Disabled {
+				fields = append(fields, formField{key: p.Name, value: insomniaTemplate(p.Value)})
+			}
+		}
+		req.Body = encodeForm(fields)
+		req.ContentType["Content-Type"] = mimeType
+	case mimeType == "multipart/form-data", mimeType == "application/octet-stream":
+		return nil, fmt.Errorf("unsupported body type %q", mimeType)
+	default:
+		req.Body = insomniaTemplate(res.Body.Text)
+		if mimeType == "" {
+			mimeType = "text/plain; charset=utf-8"
+		}
+		if req.ContentType["Content-Type"] == "" {
+			req.ContentType["Content-Type"] = mimeType
+		}
+	}
+
+	return req, nil
+}
+
+func applyInsomniaAuth(req *domain.Request, auth *insomniaAuth) error {
+	if auth == nil || auth.Disabled {
+		return nil
+	}
+
+	switch auth.Type {
+	case "", "none":
+	case "bearer":
+		// a custom prefix is not a bearer token any more, so it stays a header
+		if auth.Prefix != "" && !strings.EqualFold(auth.Prefix, "Bearer") {
+			req.Headers["Authorization"] = auth.Prefix + " " + insomniaTemplate(auth.Token)
+			return nil
+		}
+		req.Auth = &domain.Auth{Type: domain.AuthBearer, Token: insomniaTemplate(auth.Token)}
+	case "basic", "digest":
+		authType := domain.AuthBasic
+		if auth.Type == "digest" {
+			authType = domain.AuthDigest
+		}
+		req.Auth = &domain.Auth{
+			Type:     authType,
+			Username: insomniaTemplate(auth.Username),
+			Password: insomniaTemplate(auth.Password),
+		}
+	case "apikey":
+		in := domain.APIKeyInHeader
+		if auth.AddTo == "queryParams" {
+			in = domain.APIKeyInQuery
+		}
+		req.Auth = &domain.Auth{
+			Type:  domain.AuthAPIKey,
+			Key:   auth.Key,
+			Value: insomniaTemplate(auth.Value),
+			In:    in,
+		}
+	case "oauth2":
+		if auth.GrantType != domain.GrantClientCredentials && auth.GrantType != domain.GrantPassword {
+			return fmt.Errorf("unsupported oauth2 grant %q", auth.GrantType)
+		}
+		req.Auth = &domain.Auth{
+			Type:         domain.AuthOAuth2,
+			Grant:        auth.GrantType,
+			TokenURL:     insomniaTemplate(auth.AccessTokenURL),
+			ClientID:     insomniaTemplate(auth.ClientID),
+			ClientSecret: insomniaTemplate(auth.ClientSecret),
+			Scopes:       insomniaTemplate(auth.Scope),
+			Username:     insomniaTemplate(auth.Username),
+			Password:     insomniaTemplate(auth.Password),
+		}
+	default:
+		return fmt.Errorf("unsupported auth type %q", auth.Type)
+	}
+	return nil
+}
+
+func insomniaTemplate(s string) string {
+	return insomniaVariablePattern.ReplaceAllString(s, "{{$1}}")
+}
@@ -0,0 +1,80 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const insomniaExportJSON = `{
+  "_type": "export",
+  "__export_format": 4,
+  "resources": [
+    {"_id": "wrk_1", "_type": "workspace", "name": "Shop"},
+    {"_id": "env_1", "_type": "environment", "parentId": "wrk_1", "name": "Base"},
+    {"_id": "fld_1", "_type": "request_group", "parentId": "wrk_1", "name": "Orders"},
+    {
+      "_id": "req_1",
+      "_type": "request",
+      "parentId": "fld_1",
+      "name": "Create order",
+      "method": "POST",
+      "url": "{{ _.baseUrl }}/orders",
+      "body": {"mimeType": "application/json", "text": "{\"sku\": \"{{ _.sku }}\"}"},
+      "headers": [{"name": "X-Trace", "value": "on"}, {"name": "X-Off", "value": "1", "disabled": true}],
+      "parameters": [{"name": "notify", "value": "true"}],
+      "authentication": {"type": "apikey", "key": "X-Api-Key", "value": "{{ _.apiKey }}", "addTo": "header"}
+    },
+    {
+      "_id": "req_2",
+      "_type": "request",
+      "parentId": "wrk_1",
+      "name": "Login",
+      "method": "POST",
+      "url": "https://api.example.com/login",
+      "body": {"mimeType": "application/x-www-form-urlencoded", "params": [{"name": "user", "value": "bob"}]},
+      "authentication": {"type": "basic", "username": "bob", "password": "pw"}
+    },
+    {
+      "_id": "req_3",
+      "_type": "request",
+      "parentId": "wrk_1",
+      "name": "Avatar",
+      "method": "PUT",
+      "url": "https://api.example.com/avatar",
+      "body": {"mimeType": "multipart/form-data", "params": []}
+    },
+    {"_id": "grpc_1", "_type": "grpc_request", "parentId": "fld_1", "name": "Stream"}
+  ]
+}`
+
+func TestImportInsomnia(t *testing.T) {
+	result, err := ImportInsomnia([]byte(insomniaExportJSON), testConfig)
+	require.NoError(t, err)
+
+	require.Len(t, result.Requests, 2)
+
+	order := result.Requests[0]
+	assert.Equal(t, "orders/create order", order.Name)
+	assert.Equal(t, "{{baseUrl}}/orders", order.URL)
+	assert.Equal(t, `{"sku": "{{sku}}"}`, order.Body)
+	assert.Equal(t, "application/json", order.ContentType["Content-Type"])
+	assert.Equal(t, "on", order.Headers["X-Trace"])
+	assert.NotContains(t, order.Headers, "X-Off")
+	assert.Equal(t, "true", order.Params["notify"])
+	assert.Equal(t, &domain.Auth{Type: domain.AuthAPIKey, Key: "X-Api-Key", Value: "{{apiKey}}", In: domain.APIKeyInHeader}, order.Auth)
+
+	login := result.Requests[1]
+	assert.Equal(t, "login", login.Name)
+	assert.Equal(t, "user=bob", login.Body)
+	assert.Equal(t, &domain.Auth{Type: domain.AuthBasic, Username: "bob", Password: "pw"}, login.Auth)
+
+	require.Len(t, result.Skipped, 2)
+	assert.Equal(t, "Avatar", result.Skipped[0].Name)
+	assert.Contains(t, result.Skipped[0].Reason, "multipart/form-data")
+	assert.Equal(t, "Orders/Stream", result.Skipped[1].Name)
+	assert.Contains(t, result.Skipped[1].Reason, "grpc_request")
+}
@@ -0,0 +1,485 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// ref chains longer than this are treated as unresolvable
+const maxRefDepth = 8
+
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+var pathParamPattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+type openAPIDoc struct {
+	Servers    []openAPIServer                 `yaml:"servers"`
+	Paths      map[string]map[string]yaml.Node `yaml:"paths"`
+	Security   []map[string][]string           `yaml:"security"`
+	Components struct {
+		Schemas         map[string]*openAPISchema         `yaml:"schemas"`
+		Parameters      map[string]*openAPIParameter      `yaml:"parameters"`
+		RequestBodies   map[string]*openAPIRequestBody    `yaml:"requestBodies"`
+		SecuritySchemes map[string]*openAPISecurityScheme `yaml:"securitySchemes"`
+	} `yaml:"components"`
+}
+
+type openAPIServer struct {
+	URL       string `yaml:"url"`
+	Variables map[string]struct {
+		Default string `yaml:"default"`
+	} `yaml:"variables"`
+}
+
+type openAPIOperation struct {
+	OperationID string                 `yaml:"operationId"`
+	Summary     string                 `yaml:"summary"`
+	Parameters  []*openAPIParameter    `yaml:"parameters"`
+	RequestBody *openAPIRequestBody    `yaml:"requestBody"`
+	Security    *[]map[string][]string `yaml:"security"`
+}
+
+type openAPIParameter struct {
+	Ref      string         `yaml:"$ref"`
+	Name     string         `yaml:"name"`
+	In       string         `yaml:"in"`
+	Required bool           `yaml:"required"`
+	Example  any            `yaml:"example"`
+	Schema   *openAPISchema `yaml:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Ref     string                      `yaml:"$ref"`
+	Content map[string]openAPIMediaType `yaml:"content"`
+}
+
+type openAPIMediaType struct {
+	Schema   *openAPISchema `yaml:"schema"`
+	Example  any            `yaml:"example"`
+	Examples map[string]struct {
+		Value any `yaml:"value"`
+	} `yaml:"examples"`
+}
+
+type openAPISchema struct {
+	Ref        string                    `yaml:"$ref"`
+	Type       any                       `yaml:"type"`
+	Format     string                    `yaml:"format"`
+	Example    any                       `yaml:"example"`
+	Default    any                       `yaml:"default"`
+	Enum       []any                     `yaml:"enum"`
+	Properties map[string]*openAPISchema `yaml:"properties"`
+	Items      *openAPISchema            `yaml:"items"`
+	AllOf      []*openAPISchema          `yaml:"allOf"`
+	OneOf      []*openAPISchema          `yaml:"oneOf"`
+	AnyOf      []*openAPISchema          `yaml:"anyOf"`
+}
+
+type openAPISecurityScheme struct {
+	Type   string `yaml:"type"`
+	Scheme string `yaml:"scheme"`
+	Name   string `yaml:"name"`
+	In     string `yaml:"in"`
+}
+
+// ImportOpenAPI generates one request per operation of an OpenAPI 3 spec,
+// in JSON or YAML. Path parameters without examples and the server url of
+// relative servers become {{placeholders}} for environments to fill in.
+func ImportOpenAPI(data []byte, cfg *config.Config) (*Result, error) {
+	var doc openAPIDoc
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("could not parse OpenAPI spec: %v", err)
+	}
+
+	baseURL := openAPIBaseURL(doc.Servers)
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	result := newResult(FormatOpenAPI)
+	for _, path := range paths {
+		item := doc.Paths[path]
+
+		var shared []*openAPIParameter
+		if node, ok := item["parameters"]; ok {
+			if err := node.Decode(&shared); err != nil {
+				result.skip(path, "invalid path parameters: %v", err)
+				continue
+			}
+		}
+
+		for _, method := range openAPIMethods {
+			node, ok := item[method]
+			if !ok {
+				continue
+			}
+
+			fallbackName := strings.ToUpper(method) + " " + path
+
+			var op openAPIOperation
+			if err := node.Decode(&op); err != nil {
+				result.skip(fallbackName, "invalid operation: %v", err)
+				continue
+			}
+
+			name := op.OperationID
+			if name == "" {
+				name = fallbackName
+			}
+
+			req, err := doc.convertOperation(name, method, baseURL, path, shared, &op, cfg)
+			if err != nil {
+				result.skip(name, "%v", err)
+				continue
+			}
+			result.add(req)
+		}
+	}
+
+	return result, nil
+}
+
+func openAPIBaseURL(servers []openAPIServer) string {
+	if len(servers) == 0 {
+		return "{{baseUrl}}"
+	}
+
+	server := servers[0]
+	base := strings.TrimSuffix(server.URL, "/")
+	for name, variable := range server.Variables {
+		base = strings.ReplaceAll(base, "{"+name+"}", variable.Default)
+	}
+
+	if !strings.HasPrefix(base, "http://") && !strings.HasPrefix(base, "https://") {
+		return "{{baseUrl}}" + base
+	}
+	return base
+}
+
+func (doc *openAPIDoc) convertOperation(name, method, baseURL, path string, shared []*openAPIParameter, op *openAPIOperation, cfg *config.Config) (*domain.Request, error) {
+	params := make(map[string]*openAPIParameter)
+	for _, p := range slices.Concat(shared, op.Parameters) {
+		resolved, err := doc.resolveParameter(p)
+		if err != nil {
+			return nil, err
+		}
+		params[resolved.In+":"+resolved.Name] = resolved
+	}
+
+	path = pathParamPattern.ReplaceAllStringFunc(path, func(match string) string {
+		paramName := match[1 : len(match)-1]
+		if p, ok := params["path:"+paramName]; ok {
+			if value, ok := doc.parameterValue(p); ok {
+				return value
+			}
+		}
+		return "{{" + paramName + "}}"
+	})
+
+	req, err := newImportedRequest(name, method, baseURL+path, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range params {
+		if p.In == "path" {
+			continue
+		}
+		value, hasExample := doc.parameterValue(p)
+		if !p.Required && !hasExample {
+			continue
+		}
+		if !hasExample {
+			value = "{{" + p.Name + "}}"
+		}
+
+		switch p.In {
+		case "query":
+			req.Params[p.Name] = value
+		case "header":
+			setHeader(req, p.Name, value)
+		case "cookie":
+			if cookie := req.Headers["Cookie"]; cookie != "" {
+				req.Headers["Cookie"] = cookie + "; " + p.Name + "=" + value
+			} else {
+				req.Headers["Cookie"] = p.Name + "=" + value
+			}
+		}
+	}
+
+	security := doc.Security
+	if op.Security != nil {
+		security = *op.Security
+	}
+	if err := doc.applySecurity(req, security); err != nil {
+		return nil, err
+	}
+
+	if op.RequestBody != nil {
+		if err := doc.applyRequestBody(req, op.RequestBody); err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
+}
+
+func (doc *openAPIDoc) resolveParameter(p *openAPIParameter) (*openAPIParameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	resolved, ok := doc.Components.Parameters[refName(p.Ref, "parameters")]
+	if !ok {
+		return nil, fmt.Errorf("unresolved reference %s", p.Ref)
+	}
+	return resolved, nil
+}
+
+func (doc *openAPIDoc) parameterValue(p *openAPIParameter) (string, bool) {
+	value := p.Example
+	if value == nil && p.Schema != nil {
+		schema := doc.resolveSchema(p.Schema)
+		value = schema.Example
+		if value == nil {
+			value = schema.Default
+		}
+	}
+	if value == nil {
+		return "", false
+	}
+	return fmt.Sprint(value), true
+}
+
+func (doc *openAPIDoc) applySecurity(req *domain.Request, security []map[string][]string) error {
+	if len(security) == 0 {
+		return nil
+	}
+
+	// the first requirement is enough to get a working request
+	names := make([]string, 0, len(security[0]))
+	for name := range security[0] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		scheme, ok := doc.Components.SecuritySchemes[name]
+		if !ok {
+			return fmt.Errorf("unknown security scheme %q", name)
+		}
+
+		switch {
+		case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "bearer"):
+			req.Headers["Authorization"] = "Bearer {{" + name + "}}"
+		case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+			req.Headers["Authorization"] = "Basic {{" + name + "}}"
+		case scheme.Type == "oauth2", scheme.Type == "openIdConnect":
+			req.Headers["Authorization"] = "Bearer {{" + name + "}}"
+		case scheme.Type == "apiKey" && scheme.In == "query":
+			req.Params[scheme.Name] = "{{" + name + "}}"
+		case scheme.Type == "apiKey" && scheme.In == "header":
+			req.Headers[scheme.Name] = "{{" + name + "}}"
+		case scheme.Type == "apiKey" && scheme.In == "cookie":
+			req.Headers["Cookie"] = scheme.Name + "={{" + name + "}}"
+		default:
+			return fmt.Errorf("unsupported security scheme %q", name)
+		}
+	}
+	return nil
+}
+
+func (doc *openAPIDoc) applyRequestBody(req *domain.Request, body *openAPIRequestBody) error {
+	if body.Ref != "" {
+		resolved, ok := doc.Components.RequestBodies[refName(body.Ref, "requestBodies")]
+		if !ok {
+			return fmt.Errorf("unresolved reference %s", body.Ref)
+		}
+		body = resolved
+	}
+
+	if len(body.Content) == 0 {
+		return nil
+	}
+
+	mediaTypes := make([]string, 0, len(body.Content))
+	for mediaType := range body.Content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+
+	for _, mediaType := range mediaTypes {
+		media := body.Content[mediaType]
+		example := media.Example
+		if example == nil {
+			example = firstExample(media)
+		}
+		if example == nil && media.Schema != nil {
+			example = doc.schemaExample(media.Schema, map[string]bool{})
+		}
+
+		switch {
+		case strings.Contains(mediaType, "json"):
+			encoded, err := json.MarshalIndent(example, "", "  ")
+			if err != nil {
+				return fmt.Errorf("could not encode example body: %v", err)
+			}
+			req.Body = string(encoded)
+		case mediaType == "application/x-www-form-urlencoded":
+			fields, ok := example.(map[string]any)
+			if !ok {
+				continue
+			}
+			keys := make([]string, 0, len(fields))
+			for key := range fields {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			formFields := make([]formField, 0, len(keys))
+			for _, key := range keys {
+				formFields = append(formFields, formField{key: key, value: fmt.Sprint(fields[key])})
+			}
+			req.Body = encodeForm(formFields)
+		case strings.HasPrefix(mediaType, "text/"):
+			if example != nil {
+				req.Body = fmt.Sprint(example)
+			}
+		default:
+			continue
+		}
+
+		req.ContentType["Content-Type"] = mediaType
+		return nil
+	}
+
+	return fmt.Errorf("unsupported request body %s", strings.Join(mediaTypes, ", "))
+}
+
+func firstExample(media openAPIMediaType) any {
+	if len(media.Examples) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(media.Examples))
+	for name := range media.Examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return media.Examples[names[0]].Value
+}
+
+func (doc *openAPIDoc) resolveSchema(schema *openAPISchema) *openAPISchema {
+	for range maxRefDepth {
+		if schema.Ref == "" {
+			return schema
+		}
+		resolved, ok := doc.Components.Schemas[refName(schema.Ref, "schemas")]
+		if !ok {
+			return &openAPISchema{}
+		}
+		schema = resolved
+	}
+	return &openAPISchema{}
+}
+
+// schemaExample builds an example value from a schema, preferring the
+// example, default and enum values it declares. Schemas that reference
+// themselves are cut off at the first repeat.
+func (doc *openAPIDoc) schemaExample(schema *openAPISchema, seen map[string]bool) any {
+	if schema.Ref != "" {
+		if seen[schema.Ref] {
+			return nil
+		}
+		seen = maps.Clone(seen)
+		seen[schema.Ref] = true
+	}
+	schema = doc.resolveSchema(schema)
+
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.Enum) > 0:
+		return schema.Enum[0]
+	case len(schema.AllOf) > 0:
+		merged := map[string]any{}
+		for _, part := range schema.AllOf {
+			if fields, ok := doc.schemaExample(part, seen).(map[string]any); ok {
+				for key, value := range fields {
+					merged[key] = value
+				}
+			}
+		}
+		return merged
+	case len(schema.OneOf) > 0:
+		return doc.schemaExample(schema.OneOf[0], seen)
+	case len(schema.AnyOf) > 0:
+		return doc.schemaExample(schema.AnyOf[0], seen)
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		fields := map[string]any{}
+		for name, prop := range schema.Properties {
+			fields[name] = doc.schemaExample(prop, seen)
+		}
+		return fields
+	case "array":
+		if schema.Items == nil {
+			return []any{}
+		}
+		return []any{doc.schemaExample(schema.Items, seen)}
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	case "string":
+		switch schema.Format {
+		case "date":
+			return "2024-01-01"
+		case "date-time":
+			return "2024-01-01T00:00:00Z"
+		case "email":
+			return "user@example.com"
+		case "uuid":
+			return "00000000-0000-0000-0000-000000000000"
+		default:
+			return "string"
+		}
+	default:
+		return nil
+	}
+}
+
+// schemaType handles both the single type of 3.0 and the type lists of 3.1.
+func schemaType(schema *openAPISchema) string {
+	switch t := schema.Type.(type) {
+	case string:
+		return t
+	case []any:
+		for _, v := range t {
+			if s, ok := v.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	if len(schema.Properties) > 0 {
+		return "object"
+	}
+	return ""
+}
+
+func refName(ref, section string) string {
+	return strings.TrimPrefix(ref, "#/components/"+section+"/")
+}
@@ -0,0 +1,142 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const openAPISpecYAML = `
+openapi: 3.0.3
+info:
+  title: Pets
+  version: "1.0"
+servers:
+  - url: https://{region}.example.com/v1
+    variables:
+      region:
+        default: eu
+security:
+  - token: []
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            default: 20
+        - name: cursor
+          in: query
+          schema:
+            type: string
+    post:
+      operationId: createPet
+      requestBody:
+        $ref: '#/components/requestBodies/NewPet'
+  /pets/{petId}:
+    parameters:
+      - $ref: '#/components/parameters/PetId'
+    get:
+      summary: Show pet
+      security: []
+    delete:
+      operationId: deletePet
+      parameters:
+        - name: X-Reason
+          in: header
+          required: true
+          schema:
+            type: string
+  /pets/{petId}/photo:
+    put:
+      operationId: uploadPhoto
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+components:
+  securitySchemes:
+    token:
+      type: http
+      scheme: bearer
+  parameters:
+    PetId:
+      name: petId
+      in: path
+      required: true
+      schema:
+        type: string
+  requestBodies:
+    NewPet:
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Pet'
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: string
+          example: Rex
+        tags:
+          type: array
+          items:
+            type: string
+        born:
+          type: string
+          format: date
+        owner:
+          $ref: '#/components/schemas/Pet'
+`
+
+func TestImportOpenAPI(t *testing.T) {
+	result, err := ImportOpenAPI([]byte(openAPISpecYAML), testConfig)
+	require.NoError(t, err)
+
+	byName := map[string]int{}
+	for i, req := range result.Requests {
+		byName[req.Name] = i
+	}
+	require.Len(t, result.Requests, 4)
+
+	list := result.Requests[byName["listpets"]]
+	assert.Equal(t, "GET", list.Method)
+	assert.Equal(t, "https://eu.example.com/v1/pets", list.URL)
+	assert.Equal(t, map[string]string{"limit": "20"}, list.Params)
+	assert.Equal(t, "Bearer {{token}}", list.Headers["Authorization"])
+
+	create := result.Requests[byName["createpet"]]
+	assert.Equal(t, "application/json", create.ContentType["Content-Type"])
+	assert.Contains(t, create.Body, `"name": "Rex"`)
+	assert.Contains(t, create.Body, `"born": "2024-01-01"`)
+	assert.Contains(t, create.Body, `"tags": [`)
+	assert.Contains(t, create.Body, `"owner": null`)
+
+	show := result.Requests[byName["get /pets/{petid}"]]
+	assert.Equal(t, "https://eu.example.com/v1/pets/{{petId}}", show.URL)
+	assert.NotContains(t, show.Headers, "Authorization")
+
+	remove := result.Requests[byName["deletepet"]]
+	assert.Equal(t, "DELETE", remove.Method)
+	assert.Equal(t, "{{X-Reason}}", remove.Headers["X-Reason"])
+
+	require.Len(t, result.Skipped, 1)
+	assert.Equal(t, "uploadPhoto", result.Skipped[0].Name)
+	assert.Contains(t, result.Skipped[0].Reason, "multipart/form-data")
+}
+
+func TestImportOpenAPIRelativeServer(t *testing.T) {
+	spec := `{"openapi": "3.1.0", "servers": [{"url": "/api"}], "paths": {"/ping": {"get": {}}}}`
+
+	result, err := ImportOpenAPI([]byte(spec), testConfig)
+	require.NoError(t, err)
+	require.Len(t, result.Requests, 1)
+	assert.Equal(t, "get /ping", result.Requests[0].Name)
+	assert.Equal(t, "{{baseUrl}}/api/ping", result.Requests[0].URL)
+}
@@ -0,0 +1,271 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/ManoloEsS/burrow/internal/domain"
+)
+
+type postmanCollection struct {
+	Info struct {
+		Name string `json:"name"`
+	} `json:"info"`
+	Item []postmanItem `json:"item"`
+	Auth *postmanAuth  `json:"auth"`
+}
+
+type postmanItem struct {
+	Name    string          `json:"name"`
+	Item    []postmanItem   `json:"item"`
+	Request *postmanRequest `json:"request"`
+	Auth    *postmanAuth    `json:"auth"`
+}
+
+type postmanRequest struct {
+	Method string        `json:"method"`
+	Header []postmanPair `json:"header"`
+	URL    postmanURL    `json:"url"`
+	Body   *postmanBody  `json:"body"`
+	Auth   *postmanAuth  `json:"auth"`
+}
+
+type postmanPair struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Type     string `json:"type"`
+	Disabled bool   `json:"disabled"`
+}
+
+type postmanURL struct {
+	Raw   string        `json:"raw"`
+	Query []postmanPair `json:"query"`
+}
+
+type postmanBody struct {
+	Mode       string        `json:"mode"`
+	Raw        string        `json:"raw"`
+	URLEncoded []postmanPair `json:"urlencoded"`
+	Options    struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+	Disabled bool `json:"disabled"`
+}
+
+type postmanAuth struct {
+	Type   string        `json:"type"`
+	Basic  []postmanPair `json:"basic"`
+	Bearer []postmanPair `json:"bearer"`
+	APIKey []postmanPair `json:"apikey"`
+	Digest []postmanPair `json:"digest"`
+	OAuth2 []postmanPair `json:"oauth2"`
+}
+
+// a request may be given as a bare url string
+func (r *postmanRequest) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		r.Method = "GET"
+		r.URL.Raw = raw
+		return nil
+	}
+
+	type plain postmanRequest
+	return json.Unmarshal(data, (*plain)(r))
+}
+
+// a url may be given as a string or as an object with a raw field
+func (u *postmanURL) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &u.Raw); err == nil {
+		return nil
+	}
+
+	type plain postmanURL
+	return json.Unmarshal(data, (*plain)(u))
+}
+
+func postmanValue(pairs []postmanPair, key string) string {
+	for _, p := range pairs {
+		if p.Key == key {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+// ImportPostman converts a Postman v2.1 collection, naming requests after
+// their folder path.
+func ImportPostman(data []byte, cfg *config.Config) (*Result, error) {
+	var collection postmanCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return nil, fmt.Errorf("could not parse Postman collection: %v", err)
+	}
+
+	result := newResult(FormatPostman)
+	importPostmanItems(result, collection.Item, "", collection.Auth, cfg)
+	return result, nil
+}
+
+func importPostmanItems(result *Result, items []postmanItem, prefix string, auth *postmanAuth, cfg *config.Config) {
+	for _, item := range items {
+		name := item.Name
+		if prefix != "" {
+			name = prefix + "/" + item.Name
+		}
+
+		itemAuth := auth
+		if item.Auth != nil && item.Auth.Type != "inherit" {
+			itemAuth = item.Auth
+		}
+
+		if item.Request == nil {
+			importPostmanItems(result, item.Item, name, itemAuth, cfg)
+			continue
+		}
+
+		if item.Request.Auth != nil && item.Request.Auth.Type != "inherit" {
+			itemAuth = item.Request.Auth
+		}
+
+		req, err := convertPostmanRequest(name, item.Request, itemAuth, cfg)
+		if err != nil {
+			result.skip(name, "%v", err)
+			continue
+		}
+		result.add(req)
+	}
+}
+
+func convertPostmanRequest(name string, pr *postmanRequest, auth *postmanAuth, cfg *config.Config) (*domain.Request, error) {
+	method := pr.Method
+	if method == "" {
+		method = "GET"
+	}
+
+	req, err := newImportedRequest(name, method, pr.URL.Raw, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// the structured query list carries the disabled flags, so prefer it.
+	// Its keys and values are written as in the raw url, encoded.
+	if len(pr.URL.Query) > 0 {
+		var pairs []string
+		for _, q := range pr.URL.Query {
+			if !q.Disabled {
+				pairs = append(pairs, q.Key+"="+q.Value)
+			}
+		}
+		req.URL, _, _ = strings.Cut(req.URL, "?")
+		clear(req.Params)
+		rest, err := req.AddQuery(strings.Join(pairs, "&"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid query string: %v", err)
+		}
+		if rest != "" {
+			req.URL += "?" + rest
+		}
+	}
+
+	for _, h := range pr.Header {
+		if !h.Disabled {
+			setHeader(req, h.Key, h.Value)
+		}
+	}
+
+	if err := applyPostmanAuth(req, auth); err != nil {
+		return nil, err
+	}
+
+	if pr.Body == nil || pr.Body.Disabled {
+		return req, nil
+	}
+
+	switch pr.Body.Mode {
+	case "", "none":
+	case "raw":
+		req.Body = pr.Body.Raw
+		if req.ContentType["Content-Type"] == "" {
+			if pr.Body.Options.Raw.Language == "json" {
+				req.ContentType["Content-Type"] = "application/json"
+			} else {
+				req.ContentType["Content-Type"] = "text/plain; charset=utf-8"
+			}
+		}
+	case "urlencoded":
+		var fields []formField
+		for _, f := range pr.Body.URLEncoded {
+			if !f.Disabled {
+				fields = append(fields, formField{key: f.Key, value: f.Value})
+			}
+		}
+		req.Body = encodeForm(fields)
+		req.ContentType["Content-Type"] = "application/x-www-form-urlencoded"
+	default:
+		return nil, fmt.Errorf("unsupported body mode %q", pr.Body.Mode)
+	}
+
+	return req, nil
+}
+
+func applyPostmanAuth(req *domain.Request, auth *postmanAuth) error {
+	if auth == nil {
+		return nil
+	}
+
+	switch auth.Type {
+	case "", "noauth":
+	case "bearer":
+		req.Auth = &domain.Auth{Type: domain.AuthBearer, Token: postmanValue(auth.Bearer, "token")}
+	case "basic":
+		req.Auth = &domain.Auth{
+			Type:     domain.AuthBasic,
+			Username: postmanValue(auth.Basic, "username"),
+			Password: postmanValue(auth.Basic, "password"),
+		}
+	case "digest":
+		req.Auth = &domain.Auth{
+			Type:     domain.AuthDigest,
+			Username: postmanValue(auth.Digest, "username"),
+			Password: postmanValue(auth.Digest, "password"),
+		}
+	case "apikey":
+		in := domain.APIKeyInHeader
+		if strings.EqualFold(postmanValue(auth.APIKey, "in"), "query") {
+			in = domain.APIKeyInQuery
+		}
+		req.Auth = &domain.Auth{
+			Type:  domain.AuthAPIKey,
+			Key:   postmanValue(auth.APIKey, "key"),
+			Value: postmanValue(auth.APIKey, "value"),
+			In:    in,
+		}
+	case "oauth2":
+		var grant string
+		switch postmanGrant := postmanValue(auth.OAuth2, "grant_type"); postmanGrant {
+		case "client_credentials":
+			grant = domain.GrantClientCredentials
+		case "password_credentials":
+			grant = domain.GrantPassword
+		default:
+			return fmt.Errorf("unsupported oauth2 grant %q", postmanGrant)
+		}
+		req.Auth = &domain.Auth{
+			Type:         domain.AuthOAuth2,
+			Grant:        grant,
+			TokenURL:     postmanValue(auth.OAuth2, "accessTokenUrl"),
+			ClientID:     postmanValue(auth.OAuth2, "clientId"),
+			ClientSecret: postmanValue(auth.OAuth2, "clientSecret"),
+			Scopes:       postmanValue(auth.OAuth2, "scope"),
+			Username:     postmanValue(auth.OAuth2, "username"),
+			Password:     postmanValue(auth.OAuth2, "password"),
+		}
+	default:
+		return fmt.Errorf("unsupported auth type %q", auth.Type)
+	}
+	return nil
+}
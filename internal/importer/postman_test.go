@@ -0,0 +1,192 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const postmanCollectionJSON = `{
+  "info": {
+    "name": "Shop",
+    "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+  },
+  "auth": {"type": "bearer", "bearer": [{"key": "token", "value": "{{token}}", "type": "string"}]},
+  "item": [
+    {
+      "name": "Users",
+      "item": [
+        {
+          "name": "List users",
+          "request": {
+            "method": "GET",
+            "header": [
+              {"key": "Accept", "value": "application/json"},
+              {"key": "X-Debug", "value": "1", "disabled": true}
+            ],
+            "url": {
+              "raw": "{{baseUrl}}/users?page=2&draft=true",
+              "query": [
+                {"key": "page", "value": "2"},
+                {"key": "draft", "value": "true", "disabled": true}
+              ]
+            }
+          }
+        },
+        {
+          "name": "Create user",
+          "request": {
+            "method": "POST",
+            "auth": {"type": "noauth"},
+            "url": "https://api.example.com/users",
+            "body": {"mode": "raw", "raw": "{\"name\":\"burrow\"}", "options": {"raw": {"language": "json"}}}
+          }
+        }
+      ]
+    },
+    {
+      "name": "Login",
+      "request": {
+        "method": "POST",
+        "url": "https://api.example.com/login",
+        "body": {"mode": "urlencoded", "urlencoded": [{"key": "user", "value": "bob"}, {"key": "pass", "value": "s&cret"}]}
+      }
+    },
+    {
+      "name": "Upload",
+      "request": {
+        "method": "POST",
+        "url": "https://api.example.com/upload",
+        "body": {"mode": "formdata", "formdata": []}
+      }
+    },
+    {
+      "name": "Legacy",
+      "request": {
+        "method": "GET",
+        "url": "https://api.example.com/legacy",
+        "auth": {"type": "hawk"}
+      }
+    },
+    {"name": "Health", "request": "https://api.example.com/health"}
+  ]
+}`
+
+func TestImportPostman(t *testing.T) {
+	result, err := ImportPostman([]byte(postmanCollectionJSON), testConfig)
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatPostman, result.Format)
+	require.Len(t, result.Requests, 4)
+
+	list := result.Requests[0]
+	assert.Equal(t, "users/list users", list.Name)
+	assert.Equal(t, "GET", list.Method)
+	assert.Equal(t, "{{baseUrl}}/users", list.URL)
+	assert.Equal(t, map[string]string{"page": "2"}, list.Params)
+	assert.Equal(t, "application/json", list.Headers["Accept"])
+	assert.NotContains(t, list.Headers, "X-Debug")
+	assert.Equal(t, &domain.Auth{Type: domain.AuthBearer, Token: "{{token}}"}, list.Auth)
+
+	create := result.Requests[1]
+	assert.Equal(t, "users/create user", create.Name)
+	assert.Equal(t, `{"name":"burrow"}`, create.Body)
+	assert.Equal(t, "application/json", create.ContentType["Content-Type"])
+	assert.Nil(t, create.Auth)
+
+	login := result.Requests[2]
+	assert.Equal(t, "user=bob&pass=s%26cret", login.Body)
+	assert.Equal(t, "application/x-www-form-urlencoded", login.ContentType["Content-Type"])
+
+	health := result.Requests[3]
+	assert.Equal(t, "GET", health.Method)
+	assert.Equal(t, "https://api.example.com/health", health.URL)
+
+	require.Len(t, result.Skipped, 2)
+	assert.Equal(t, "Upload", result.Skipped[0].Name)
+	assert.Contains(t, result.Skipped[0].Reason, "formdata")
+	assert.Equal(t, "Legacy", result.Skipped[1].Name)
+	assert.Contains(t, result.Skipped[1].Reason, "hawk")
+}
+
+func TestImportPostmanOAuth2(t *testing.T) {
+	collection := func(grant string) []byte {
+		return []byte(`{
+  "info": {"name": "Auth", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+  "auth": {"type": "oauth2", "oauth2": [
+    {"key": "grant_type", "value": "` + grant + `"},
+    {"key": "accessTokenUrl", "value": "https://auth.example.com/token"},
+    {"key": "clientId", "value": "burrow"},
+    {"key": "clientSecret", "value": "{{clientSecret}}"},
+    {"key": "scope", "value": "read"}
+  ]},
+  "item": [{"name": "me", "request": {"method": "GET", "url": "https://api.example.com/me"}}]
+}`)
+	}
+
+	result, err := ImportPostman(collection("client_credentials"), testConfig)
+	require.NoError(t, err)
+	require.Len(t, result.Requests, 1)
+	assert.Equal(t, &domain.Auth{
+		Type:         domain.AuthOAuth2,
+		Grant:        domain.GrantClientCredentials,
+		TokenURL:     "https://auth.example.com/token",
+		ClientID:     "burrow",
+		ClientSecret: "{{clientSecret}}",
+		Scopes:       "read",
+	}, result.Requests[0].Auth)
+
+	result, err = ImportPostman(collection("authorization_code"), testConfig)
+	require.NoError(t, err)
+	assert.Empty(t, result.Requests)
+	require.Len(t, result.Skipped, 1)
+	assert.Contains(t, result.Skipped[0].Reason, "authorization_code")
+}
+
+func TestImportPostmanEncodedQuery(t *testing.T) {
+	collection := `{
+  "info": {"name": "Search", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+  "item": [
+    {
+      "name": "Search",
+      "request": {
+        "method": "GET",
+        "url": {
+          "raw": "https://api.example.com/search?q=go%20%26%20rust&path=a%2Fb",
+          "query": [{"key": "q", "value": "go%20%26%20rust"}, {"key": "path", "value": "a%2Fb"}]
+        }
+      }
+    },
+    {
+      "name": "Tags",
+      "request": {
+        "method": "GET",
+        "url": {
+          "raw": "https://api.example.com/items?tag=a&tag=b%2Fc&draft=1",
+          "query": [{"key": "tag", "value": "a"}, {"key": "tag", "value": "b%2Fc"}, {"key": "draft", "value": "1", "disabled": true}]
+        }
+      }
+    },
+    {"name": "Raw", "request": {"method": "GET", "url": "https://api.example.com/raw?tag=a&tag=b&q=x%2By"}}
+  ]
+}`
+	result, err := ImportPostman([]byte(collection), testConfig)
+	require.NoError(t, err)
+	require.Len(t, result.Requests, 3)
+
+	search := result.Requests[0]
+	assert.Equal(t, "https://api.example.com/search", search.URL)
+	assert.Equal(t, map[string]string{"q": "go & rust", "path": "a/b"}, search.Params)
+
+	// repeated keys cannot be held by the params and stay in the url
+	tags := result.Requests[1]
+	assert.Equal(t, "https://api.example.com/items?tag=a&tag=b%2Fc", tags.URL)
+	assert.Empty(t, tags.Params)
+
+	raw := result.Requests[2]
+	assert.Equal(t, "https://api.example.com/raw?tag=a&tag=b&q=x%2By", raw.URL)
+	assert.Empty(t, raw.Params)
+}
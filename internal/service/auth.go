@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+)
+
+// tokens are refreshed this long before they expire so a request never
+// reaches the server with a token that lapsed on the way
+const tokenExpiryMargin = 30 * time.Second
+
+const maxTokenResponse = 1 << 20
+
+type oauth2Token struct {
+	AccessToken  string
+	RefreshToken string
+	Expiry       time.Time
+}
+
+func (t oauth2Token) valid(now time.Time) bool {
+	return t.AccessToken != "" && (t.Expiry.IsZero() || now.Before(t.Expiry.Add(-tokenExpiryMargin)))
+}
+
+// oauth2Token returns an access token for auth, reusing the cached one while
+// it is valid and refreshing it once it expires.
+func (s *httpClientService) oauth2Token(ctx context.Context, client *http.Client, auth domain.Auth) (string, error) {
+	s.tokenMu.Lock()
+	cached, ok := s.tokens[auth]
+	s.tokenMu.Unlock()
+
+	if ok && cached.valid(time.Now()) {
+		return cached.AccessToken, nil
+	}
+
+	var token oauth2Token
+	var err error
+	if ok && cached.RefreshToken != "" {
+		token, err = requestToken(ctx, client, auth, url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {cached.RefreshToken},
+		})
+	}
+	// fall back to the grant when there is nothing to refresh or the
+	// refresh token was rejected
+	if token.AccessToken == "" {
+		form := url.Values{"grant_type": {auth.Grant}}
+		if auth.Grant == domain.GrantPassword {
+			form.Set("username", auth.Username)
+			form.Set("password", auth.Password)
+		}
+		token, err = requestToken(ctx, client, auth, form)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// a refresh response may leave out the refresh token to keep using
+	if token.RefreshToken == "" {
+		token.RefreshToken = cached.RefreshToken
+	}
+
+	s.tokenMu.Lock()
+	if s.tokens == nil {
+		s.tokens = make(map[domain.Auth]oauth2Token)
+	}
+	s.tokens[auth] = token
+	s.tokenMu.Unlock()
+
+	return token.AccessToken, nil
+}
+
+func (s *httpClientService) forgetOAuth2Token(auth domain.Auth) {
+	s.tokenMu.Lock()
+	defer s.tokenMu.Unlock()
+	delete(s.tokens, auth)
+}
+
+func requestToken(ctx context.Context, client *http.Client, auth domain.Auth, form url.Values) (oauth2Token, error) {
+	if auth.Scopes != "" {
+		form.Set("scope", auth.Scopes)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, auth.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return oauth2Token{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.SetBasicAuth(url.QueryEscape(auth.ClientID), url.QueryEscape(auth.ClientSecret))
+
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		return oauth2Token{}, err
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(httpResp.Body, maxTokenResponse))
+	if err != nil {
+		return oauth2Token{}, err
+	}
+
+	var payload struct {
+		AccessToken      string `json:"access_token"`
+		RefreshToken     string `json:"refresh_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	// error responses are not always JSON, the status is reported then
+	_ = json.Unmarshal(body, &payload)
+
+	if httpResp.StatusCode != http.StatusOK {
+		if payload.Error != "" {
+			return oauth2Token{}, fmt.Errorf("token endpoint returned %s: %s %s", httpResp.Status, payload.Error, payload.ErrorDescription)
+		}
+		return oauth2Token{}, fmt.Errorf("token endpoint returned %s", httpResp.Status)
+	}
+	if payload.AccessToken == "" {
+		return oauth2Token{}, fmt.Errorf("token endpoint returned no access token")
+	}
+
+	token := oauth2Token{AccessToken: payload.AccessToken, RefreshToken: payload.RefreshToken}
+	if payload.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(payload.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// digestAuthorization answers the digest challenge of a 401 response. It
+// returns an empty string when the server sent no digest challenge.
+func digestAuthorization(httpResp *http.Response, req *domain.Request) (string, error) {
+	for _, header := range httpResp.Header.Values("WWW-Authenticate") {
+		challenge, ok := domain.ParseDigestChallenge(header)
+		if !ok {
+			continue
+		}
+
+		nonce := make([]byte, 16)
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+		uri := httpResp.Request.URL.RequestURI()
+		return challenge.Authorization(req.Auth.Username, req.Auth.Password, req.Method, uri, 1, hex.EncodeToString(nonce))
+	}
+	return "", nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendRequestAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "%s|%s|%s", r.Header.Get("Authorization"), r.Header.Get("X-Api-Key"), r.URL.RawQuery)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		auth *domain.Auth
+		want string
+	}{
+		{name: "basic", auth: &domain.Auth{Type: domain.AuthBasic, Username: "bob", Password: "pw"}, want: "Basic Ym9iOnB3||"},
+		{name: "bearer", auth: &domain.Auth{Type: domain.AuthBearer, Token: "abc"}, want: "Bearer abc||"},
+		{name: "api key header", auth: &domain.Auth{Type: domain.AuthAPIKey, Key: "X-Api-Key", Value: "k1", In: domain.APIKeyInHeader}, want: "|k1|"},
+		{name: "api key query", auth: &domain.Auth{Type: domain.AuthAPIKey, Key: "key", Value: "k1", In: domain.APIKeyInQuery}, want: "||key=k1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &httpClientService{httpCfg: defaultHTTPConfig()}
+			resp, err := s.SendRequest(context.Background(), &domain.Request{Method: "GET", URL: server.URL, Auth: tt.auth})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, resp.Body)
+		})
+	}
+}
+
+func TestSendRequestInvalidAuth(t *testing.T) {
+	s := &httpClientService{httpCfg: defaultHTTPConfig()}
+	_, err := s.SendRequest(context.Background(), &domain.Request{Method: "GET", URL: "http://localhost", Auth: &domain.Auth{Type: domain.AuthBearer}})
+	assert.ErrorContains(t, err, "token required")
+}
+
+var cnoncePattern = regexp.MustCompile(`cnonce="([^"]+)"`)
+
+func TestSendRequestDigest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		challenge := &domain.DigestChallenge{Realm: "burrow", Nonce: "abc123", QOP: []string{"auth"}}
+		if match := cnoncePattern.FindStringSubmatch(r.Header.Get("Authorization")); match != nil {
+			want, err := challenge.Authorization("bob", "pw", r.Method, r.URL.RequestURI(), 1, match[1])
+			if err == nil && want == r.Header.Get("Authorization") {
+				_, _ = w.Write([]byte("welcome"))
+				return
+			}
+		}
+		w.Header().Set("WWW-Authenticate", `Digest realm="burrow", nonce="abc123", qop="auth"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	s := &httpClientService{httpCfg: defaultHTTPConfig()}
+
+	resp, err := s.SendRequest(context.Background(), &domain.Request{
+		Method: "GET",
+		URL:    server.URL + "/private?page=1",
+		Auth:   &domain.Auth{Type: domain.AuthDigest, Username: "bob", Password: "pw"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "200 OK", resp.Status)
+	assert.Equal(t, "welcome", resp.Body)
+
+	resp, err = s.SendRequest(context.Background(), &domain.Request{
+		Method: "GET",
+		URL:    server.URL + "/private",
+		Auth:   &domain.Auth{Type: domain.AuthDigest, Username: "bob", Password: "wrong"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "401 Unauthorized", resp.Status)
+}
+
+func TestOAuth2TokenCaching(t *testing.T) {
+	var tokenRequests atomic.Int32
+	var lastGrant atomic.Value
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := tokenRequests.Add(1)
+		require.NoError(t, r.ParseForm())
+		lastGrant.Store(r.PostForm.Get("grant_type"))
+
+		id, secret, _ := r.BasicAuth()
+		if id != "client" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  fmt.Sprintf("token-%d", n),
+			"refresh_token": "refresh",
+			"expires_in":    3600,
+		})
+	}))
+	defer tokenServer.Close()
+
+	var reject atomic.Bool
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if reject.Load() {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer api.Close()
+
+	auth := &domain.Auth{Type: domain.AuthOAuth2, Grant: domain.GrantClientCredentials, TokenURL: tokenServer.URL, ClientID: "client", ClientSecret: "s3cret"}
+	s := &httpClientService{httpCfg: defaultHTTPConfig()}
+	send := func() *domain.Response {
+		resp, err := s.SendRequest(context.Background(), &domain.Request{Method: "GET", URL: api.URL, Auth: auth})
+		require.NoError(t, err)
+		return resp
+	}
+
+	assert.Equal(t, "Bearer token-1", send().Body)
+	assert.Equal(t, "Bearer token-1", send().Body)
+	assert.Equal(t, int32(1), tokenRequests.Load())
+	assert.Equal(t, domain.GrantClientCredentials, lastGrant.Load())
+
+	// an expired token is refreshed with the refresh token
+	s.tokenMu.Lock()
+	cached := s.tokens[*auth]
+	cached.Expiry = cached.Expiry.Add(-2 * time.Hour)
+	s.tokens[*auth] = cached
+	s.tokenMu.Unlock()
+
+	assert.Equal(t, "Bearer token-2", send().Body)
+	assert.Equal(t, "refresh_token", lastGrant.Load())
+
+	// a rejected token is dropped so the next request fetches a new one
+	reject.Store(true)
+	assert.Equal(t, "401 Unauthorized", send().Status)
+	reject.Store(false)
+	assert.Equal(t, "Bearer token-3", send().Body)
+	assert.Equal(t, domain.GrantClientCredentials, lastGrant.Load())
+
+	bad := *auth
+	bad.ClientSecret = "wrong"
+	_, err := s.SendRequest(context.Background(), &domain.Request{Method: "GET", URL: api.URL, Auth: &bad})
+	assert.ErrorContains(t, err, "invalid_client")
+}
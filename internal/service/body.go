@@ -0,0 +1,80 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+)
+
+// requestBody is a body streamed as the request is sent. open builds it anew
+// for every attempt so redirects and auth retries can send it again.
+type requestBody struct {
+	contentType string
+	length      int64
+	open        func() (io.ReadCloser, error)
+}
+
+// newFileBody streams the file at path, typed from its extension unless
+// contentType is given.
+func newFileBody(path, contentType string) (*requestBody, error) {
+	size, err := fileSize(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read body file: %w", err)
+	}
+	if contentType == "" {
+		contentType = fileContentType(path)
+	}
+
+	return &requestBody{
+		contentType: contentType,
+		length:      size,
+		open: func() (io.ReadCloser, error) {
+			file, err := os.Open(path)
+			if err != nil {
+				return nil, fmt.Errorf("could not read body file: %w", err)
+			}
+			return file, nil
+		},
+	}, nil
+}
+
+// fileSize returns the size of the regular file at path, so its length is
+// known before it is streamed.
+func fileSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	if !info.Mode().IsRegular() {
+		return 0, fmt.Errorf("%s is not a regular file", path)
+	}
+	return info.Size(), nil
+}
+
+func copyFile(w io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	_, err = io.Copy(w, file)
+	return err
+}
+
+// fileContentType infers the type of a file from its extension.
+func fileContentType(path string) string {
+	if contentType := mime.TypeByExtension(filepath.Ext(path)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+type countingWriter int64
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	*c += countingWriter(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendRequestBodyFile(t *testing.T) {
+	dir := t.TempDir()
+	fixture := filepath.Join(dir, "fixture.json")
+	require.NoError(t, os.WriteFile(fixture, []byte(`{"items":[1,2,3]}`), 0o600))
+	blob := filepath.Join(dir, "blob.pb")
+	require.NoError(t, os.WriteFile(blob, []byte{0x08, 0x96, 0x01}, 0o600))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved" {
+			http.Redirect(w, r, "/items", http.StatusPermanentRedirect)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = fmt.Fprintf(w, "%s|%d|%v|%x", r.Header.Get("Content-Type"), r.ContentLength, r.TransferEncoding, body)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		path        string
+		url         string
+		contentType string
+		want        string
+	}{
+		{name: "type from extension", path: fixture, url: "/items", want: "application/json|17|[]|" + fmt.Sprintf("%x", `{"items":[1,2,3]}`)},
+		{name: "unknown extension", path: blob, url: "/items", want: "application/octet-stream|3|[]|089601"},
+		{name: "explicit type", path: blob, url: "/items", contentType: "application/x-protobuf", want: "application/x-protobuf|3|[]|089601"},
+		{name: "sent again on redirect", path: blob, url: "/moved", want: "application/octet-stream|3|[]|089601"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &domain.Request{
+				Method:      "POST",
+				URL:         server.URL + tt.url,
+				BodyFile:    tt.path,
+				ContentType: map[string]string{},
+			}
+			if tt.contentType != "" {
+				req.ContentType["Content-Type"] = tt.contentType
+			}
+
+			s := &httpClientService{httpCfg: defaultHTTPConfig()}
+			resp, err := s.SendRequest(context.Background(), req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, resp.Body)
+		})
+	}
+}
+
+func TestSendRequestBodyFileMissing(t *testing.T) {
+	s := &httpClientService{httpCfg: defaultHTTPConfig()}
+
+	_, err := s.SendRequest(context.Background(), &domain.Request{Method: "POST", URL: "http://localhost", BodyFile: filepath.Join(t.TempDir(), "missing.json")})
+	assert.ErrorContains(t, err, "could not read body file")
+
+	_, err = s.SendRequest(context.Background(), &domain.Request{Method: "POST", URL: "http://localhost", BodyFile: t.TempDir()})
+	assert.ErrorContains(t, err, "not a regular file")
+}
+
+func TestSaveRequestKeepsBodyFileReference(t *testing.T) {
+	s := newHistoryTestService(t, config.HistoryConfig{})
+	path := filepath.Join(t.TempDir(), "secret-payload.bin")
+	require.NoError(t, os.WriteFile(path, []byte("file contents"), 0o600))
+
+	require.NoError(t, s.SaveRequest(&domain.Request{Name: "upload", Method: "POST", URL: "http://localhost", BodyFile: path}))
+
+	saved, err := s.GetRequest("upload")
+	require.NoError(t, err)
+	assert.Equal(t, path, saved.BodyFile)
+	assert.Empty(t, saved.Body)
+
+	row, err := s.requestRepo.Queries.GetRequest(context.Background(), "upload")
+	require.NoError(t, err)
+	assert.NotContains(t, string(row.RequestJson.([]byte)), "file contents")
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// diagnosticPattern matches the file:line:col: message lines of the go
+// compiler and vet, the column being optional.
+var diagnosticPattern = regexp.MustCompile(`^(.+?\.go):(\d+)(?::(\d+))?: (.+)$`)
+
+// Diagnostic is an error the compiler reported at a place in a file.
+type Diagnostic struct {
+	// File is absolute
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	if d.Column == 0 {
+		return fmt.Sprintf("%s:%d: %s", d.File, d.Line, d.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", d.File, d.Line, d.Column, d.Message)
+}
+
+// ParseBuildOutput picks the diagnostics out of what go build wrote, paths
+// being relative to dir. Indented lines that follow a diagnostic, such as the
+// have and want of a mismatched call, are added to its message.
+func ParseBuildOutput(output, dir string) []Diagnostic {
+	var diagnostics []Diagnostic
+	for line := range strings.Lines(output) {
+		line = strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(line, "\t") && len(diagnostics) > 0 {
+			last := &diagnostics[len(diagnostics)-1]
+			last.Message += "\n" + strings.TrimSpace(line)
+			continue
+		}
+		match := diagnosticPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		file := match[1]
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		lineNumber, _ := strconv.Atoi(match[2])
+		column, _ := strconv.Atoi(match[3])
+		diagnostics = append(diagnostics, Diagnostic{File: file, Line: lineNumber, Column: column, Message: match[4]})
+	}
+	return diagnostics
+}
+
+// BuildError is a failed go build with what the compiler reported.
+type BuildError struct {
+	Output      string
+	Diagnostics []Diagnostic
+}
+
+func (e *BuildError) Error() string {
+	switch len(e.Diagnostics) {
+	case 0:
+		for line := range strings.Lines(e.Output) {
+			// skip the # package headers
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				return "build failed: " + line
+			}
+		}
+		return "build failed"
+	case 1:
+		return "build failed: 1 error"
+	default:
+		return fmt.Sprintf("build failed: %d errors", len(e.Diagnostics))
+	}
+}
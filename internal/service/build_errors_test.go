@@ -0,0 +1,40 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const buildOutput = `# command-line-arguments
+./main.go:12:2: undefined: handler
+./main.go:20:15: not enough arguments in call to serve
+	have (string)
+	want (string, int)
+/src/shop/store.go:7: syntax error: unexpected newline
+main.go:30:1: too many errors
+`
+
+func TestParseBuildOutput(t *testing.T) {
+	diagnostics := ParseBuildOutput(buildOutput, "/src/shop/cmd/api")
+	require.Len(t, diagnostics, 4)
+
+	assert.Equal(t, Diagnostic{File: "/src/shop/cmd/api/main.go", Line: 12, Column: 2, Message: "undefined: handler"}, diagnostics[0])
+	assert.Equal(t, "not enough arguments in call to serve\nhave (string)\nwant (string, int)", diagnostics[1].Message)
+	assert.Equal(t, "/src/shop/store.go:7: syntax error: unexpected newline", diagnostics[2].String())
+	assert.Equal(t, 30, diagnostics[3].Line)
+
+	assert.Empty(t, ParseBuildOutput("go: cannot find main module\n", "/src"))
+}
+
+func TestBuildErrorMessage(t *testing.T) {
+	err := &BuildError{Output: buildOutput, Diagnostics: ParseBuildOutput(buildOutput, "/src")}
+	assert.EqualError(t, err, "build failed: 4 errors")
+
+	err.Diagnostics = err.Diagnostics[:1]
+	assert.EqualError(t, err, "build failed: 1 error")
+
+	err = &BuildError{Output: "# example.com/api\ngo: updates to go.mod needed\n"}
+	assert.EqualError(t, err, "build failed: go: updates to go.mod needed")
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+)
+
+func newFormBody(req *domain.Request) (*requestBody, error) {
+	if req.IsMultipart() {
+		return newMultipartBody(req.Form)
+	}
+	return newURLEncodedBody(req.Form)
+}
+
+// newURLEncodedBody encodes the fields in order, file fields are sent with
+// the contents of the file as their value.
+func newURLEncodedBody(fields []domain.FormField) (*requestBody, error) {
+	pairs := make([]string, 0, len(fields))
+	for _, field := range fields {
+		value := field.Value
+		if field.File {
+			data, err := os.ReadFile(field.Value)
+			if err != nil {
+				return nil, fmt.Errorf("could not read form file: %w", err)
+			}
+			value = string(data)
+		}
+		pairs = append(pairs, url.QueryEscape(field.Key)+"="+url.QueryEscape(value))
+	}
+
+	encoded := []byte(strings.Join(pairs, "&"))
+	return &requestBody{
+		contentType: domain.ContentTypeForm,
+		length:      int64(len(encoded)),
+		open: func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(encoded)), nil
+		},
+	}, nil
+}
+
+// newMultipartBody streams the parts through a pipe so files are copied from
+// disk as the request is sent instead of being held in memory. The length is
+// worked out up front from the part headers and the file sizes, so the body
+// is not sent chunked.
+func newMultipartBody(fields []domain.FormField) (*requestBody, error) {
+	boundary := multipart.NewWriter(nil).Boundary()
+
+	var length countingWriter
+	err := writeMultipart(&length, boundary, fields, func(_ io.Writer, path string) error {
+		size, err := fileSize(path)
+		if err != nil {
+			return fmt.Errorf("could not read form file: %w", err)
+		}
+		length += countingWriter(size)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &requestBody{
+		contentType: mime.FormatMediaType(domain.ContentTypeMultipart, map[string]string{"boundary": boundary}),
+		length:      int64(length),
+		open: func() (io.ReadCloser, error) {
+			pr, pw := io.Pipe()
+			go func() {
+				_ = pw.CloseWithError(writeMultipart(pw, boundary, fields, copyFile))
+			}()
+			return pr, nil
+		},
+	}, nil
+}
+
+func writeMultipart(w io.Writer, boundary string, fields []domain.FormField, writeFile func(io.Writer, string) error) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(boundary); err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		if !field.File {
+			if err := mw.WriteField(field.Key, field.Value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", multipart.FileContentDisposition(field.Key, filepath.Base(field.Value)))
+		header.Set("Content-Type", fileContentType(field.Value))
+		part, err := mw.CreatePart(header)
+		if err != nil {
+			return err
+		}
+		if err := writeFile(part, field.Value); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
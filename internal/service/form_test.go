@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendRequestMultipart(t *testing.T) {
+	dir := t.TempDir()
+	avatar := filepath.Join(dir, "avatar.png")
+	require.NoError(t, os.WriteFile(avatar, []byte("\x89PNG fake image"), 0o600))
+	notes := filepath.Join(dir, "notes")
+	require.NoError(t, os.WriteFile(notes, []byte("line one\nline two"), 0o600))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved" {
+			http.Redirect(w, r, "/upload", http.StatusTemporaryRedirect)
+			return
+		}
+		if len(r.TransferEncoding) > 0 {
+			http.Error(w, "chunked body", http.StatusBadRequest)
+			return
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var builder strings.Builder
+		fmt.Fprintf(&builder, "name=%s\n", r.FormValue("name"))
+		for _, key := range []string{"avatar", "notes"} {
+			file, header, err := r.FormFile(key)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			data, _ := io.ReadAll(file)
+			_ = file.Close()
+			fmt.Fprintf(&builder, "%s=%s|%s|%s\n", key, header.Filename, header.Header.Get("Content-Type"), data)
+		}
+		_, _ = w.Write([]byte(builder.String()))
+	}))
+	defer server.Close()
+
+	req := &domain.Request{
+		Method:      "POST",
+		URL:         server.URL + "/moved",
+		ContentType: map[string]string{"Content-Type": domain.ContentTypeMultipart},
+		Form: []domain.FormField{
+			{Key: "name", Value: "burrow"},
+			{Key: "avatar", Value: avatar, File: true},
+			{Key: "notes", Value: notes, File: true},
+		},
+	}
+
+	// the redirect makes the client send the streamed body a second time
+	s := &httpClientService{httpCfg: defaultHTTPConfig()}
+	resp, err := s.SendRequest(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "200 OK", resp.Status, resp.Body)
+	assert.Equal(t, "name=burrow\n"+
+		"avatar=avatar.png|image/png|\x89PNG fake image\n"+
+		"notes=notes|application/octet-stream|line one\nline two\n", resp.Body)
+}
+
+func TestSendRequestURLEncodedForm(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key.txt")
+	require.NoError(t, os.WriteFile(keyFile, []byte("a&b=c"), 0o600))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = fmt.Fprintf(w, "%s|%d|%s", r.Header.Get("Content-Type"), r.ContentLength, body)
+	}))
+	defer server.Close()
+
+	s := &httpClientService{httpCfg: defaultHTTPConfig()}
+	resp, err := s.SendRequest(context.Background(), &domain.Request{
+		Method:      "POST",
+		URL:         server.URL,
+		ContentType: map[string]string{"Content-Type": domain.ContentTypeForm},
+		Form: []domain.FormField{
+			{Key: "user", Value: "bob smith"},
+			{Key: "key", Value: keyFile, File: true},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "application/x-www-form-urlencoded|28|user=bob+smith&key=a%26b%3Dc", resp.Body)
+}
+
+func TestSendRequestFormMissingFile(t *testing.T) {
+	for _, contentType := range []string{domain.ContentTypeForm, domain.ContentTypeMultipart} {
+		t.Run(contentType, func(t *testing.T) {
+			s := &httpClientService{httpCfg: defaultHTTPConfig()}
+			_, err := s.SendRequest(context.Background(), &domain.Request{
+				Method:      "POST",
+				URL:         "http://localhost",
+				ContentType: map[string]string{"Content-Type": contentType},
+				Form:        []domain.FormField{{Key: "file", Value: filepath.Join(t.TempDir(), "missing"), File: true}},
+			})
+			assert.ErrorContains(t, err, "could not read form file")
+		})
+	}
+}
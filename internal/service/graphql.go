@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+)
+
+// IntrospectGraphQL asks the GraphQL endpoint of req for its schema, sending
+// the introspection query with the headers, auth and options of req. It is
+// not recorded in the history.
+func (s *httpClientService) IntrospectGraphQL(ctx context.Context, req *domain.Request) (*domain.GraphQLSchema, error) {
+	lookup := &domain.SecretLookup{Variables: s.GetActiveEnvironment().Lookup, Secrets: s.lookupSecret}
+	introspect := req.WithVariables(lookup.Lookup)
+	if lookup.Err != nil {
+		return nil, lookup.Err
+	}
+	introspect.Method = "POST"
+	introspect.GraphQL = &domain.GraphQLBody{Query: domain.GraphQLIntrospectionQuery}
+
+	resp, err := s.send(ctx, introspect, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.RemoveBodyFile() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("introspection failed: %s", resp.Status)
+	}
+
+	body := []byte(resp.Body)
+	if resp.Truncated {
+		// large schemas do not fit in the body kept in memory
+		if body, err = os.ReadFile(resp.BodyFile); err != nil {
+			return nil, err
+		}
+	}
+	return domain.ParseGraphQLSchema(body)
+}
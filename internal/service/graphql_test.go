@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGraphQLServer answers introspection with a small schema and any other
+// query with the user it asks for, or an error when there is none.
+func newGraphQLServer(t *testing.T) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var payload struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(payload.Query, "__schema"):
+			_, _ = w.Write([]byte(`{"data": {"__schema": {"queryType": {"name": "Query"}, "types": [
+				{"kind": "OBJECT", "name": "Query", "fields": [{"name": "user", "args": [{"name": "id"}], "type": {"kind": "OBJECT", "name": "User"}}]},
+				{"kind": "OBJECT", "name": "User", "fields": [{"name": "name", "args": [], "type": {"kind": "SCALAR", "name": "String"}}]}
+			]}}}`))
+		case payload.Variables["id"] == "1":
+			_, _ = w.Write([]byte(`{"data": {"user": {"name": "Ada"}}}`))
+		default:
+			_, _ = w.Write([]byte(`{"data": {"user": null}, "errors": [{"message": "user not found", "path": ["user"]}]}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func graphQLRequest(url, variables string) *domain.Request {
+	return &domain.Request{
+		Method:  "POST",
+		URL:     url,
+		Auth:    &domain.Auth{Type: domain.AuthBearer, Token: "secret"},
+		GraphQL: &domain.GraphQLBody{Query: "query($id: ID) { user(id: $id) { name } }", Variables: variables},
+	}
+}
+
+func TestSendGraphQLRequest(t *testing.T) {
+	url := newGraphQLServer(t)
+	s := &httpClientService{httpCfg: defaultHTTPConfig()}
+
+	resp, err := s.SendRequest(context.Background(), graphQLRequest(url, `{"id": "1"}`))
+	require.NoError(t, err)
+	require.NotNil(t, resp.GraphQL)
+	assert.JSONEq(t, `{"user": {"name": "Ada"}}`, string(resp.GraphQL.Data))
+	assert.Empty(t, resp.GraphQL.Errors)
+	assert.True(t, resp.Success())
+
+	resp, err = s.SendRequest(context.Background(), graphQLRequest(url, `{"id": "2"}`))
+	require.NoError(t, err)
+	require.Len(t, resp.GraphQL.Errors, 1)
+	assert.Equal(t, "user not found at user", resp.GraphQL.Errors[0].String())
+	assert.False(t, resp.Success())
+
+	_, err = s.SendRequest(context.Background(), graphQLRequest(url, `[1]`))
+	assert.ErrorContains(t, err, "GraphQL variables must be a JSON object")
+}
+
+func TestIntrospectGraphQL(t *testing.T) {
+	url := newGraphQLServer(t)
+	s := &httpClientService{httpCfg: defaultHTTPConfig()}
+
+	schema, err := s.IntrospectGraphQL(context.Background(), graphQLRequest(url, ""))
+	require.NoError(t, err)
+	assert.Equal(t, "Query", schema.QueryType)
+	_, fields := schema.Complete("{ user { n", 10)
+	require.Len(t, fields, 1)
+	assert.Equal(t, "name", fields[0].Name)
+
+	req := graphQLRequest(url, "")
+	req.Auth = nil
+	_, err = s.IntrospectGraphQL(context.Background(), req)
+	assert.ErrorContains(t, err, "introspection failed: 401 Unauthorized")
+}
@@ -0,0 +1,391 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// ListGRPCMethods lists the methods of the services offered by the server in
+// req's URL, found by server reflection or in the files of the proto option.
+func (s *httpClientService) ListGRPCMethods(ctx context.Context, req *domain.Request) ([]domain.GRPCMethod, error) {
+	lookup := &domain.SecretLookup{Variables: s.GetActiveEnvironment().Lookup, Secrets: s.lookupSecret}
+	resolved := req.WithVariables(lookup.Lookup)
+	if lookup.Err != nil {
+		return nil, lookup.Err
+	}
+
+	target, err := resolved.GRPCTarget()
+	if err != nil {
+		return nil, err
+	}
+	cfg := resolved.Options.Apply(s.httpCfg)
+	if cfg.Timeout > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	client, err := s.dialGRPC(ctx, resolved, target, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer client.close()
+
+	services, err := client.source.services(client.context(ctx))
+	if err != nil {
+		return nil, err
+	}
+	var methods []domain.GRPCMethod
+	for _, service := range services {
+		for i := range service.Methods().Len() {
+			methods = append(methods, grpcMethod(service.Methods().Get(i)))
+		}
+	}
+	return methods, nil
+}
+
+// CallGRPC calls the method in req's URL with the Body as its request
+// message, handing every response message to onMessage as JSON while it
+// arrives. Unary and server streaming methods can be called. A call ending
+// with an error status is not an error, the status is in the response. For a
+// server stream the timeout only bounds the wait for its headers, and
+// cancelling ctx once it has started stops it without an error.
+func (s *httpClientService) CallGRPC(ctx context.Context, req *domain.Request, onMessage func(domain.Event)) (*domain.Response, error) {
+	lookup := &domain.SecretLookup{Variables: s.GetActiveEnvironment().Lookup, Secrets: s.lookupSecret}
+	resolved := req.WithVariables(lookup.Lookup)
+
+	sentAt := time.Now()
+	resp, err := &domain.Response{}, lookup.Err
+	if err == nil {
+		resp, err = s.callGRPC(ctx, resolved, onMessage)
+	}
+	s.recordHistory(req, resp, err, sentAt)
+	return resp, err
+}
+
+func (s *httpClientService) callGRPC(ctx context.Context, req *domain.Request, onMessage func(domain.Event)) (*domain.Response, error) {
+	target, err := req.GRPCTarget()
+	if err != nil {
+		return &domain.Response{}, err
+	}
+	if target.Method == "" {
+		return &domain.Response{}, errors.New("gRPC url requires a method such as package.Service/Method")
+	}
+	cfg := req.Options.Apply(s.httpCfg)
+
+	// finding the method is bounded by the timeout whatever kind it is
+	findCtx, cancelFind := ctx, func() {}
+	if cfg.Timeout > 0 {
+		findCtx, cancelFind = context.WithTimeout(ctx, cfg.Timeout)
+	}
+	defer cancelFind()
+
+	client, err := s.dialGRPC(findCtx, req, target, cfg)
+	if err != nil {
+		return &domain.Response{}, err
+	}
+	defer client.close()
+
+	method, err := findGRPCMethod(client.context(findCtx), client.source, target.Method)
+	if err != nil {
+		return &domain.Response{}, err
+	}
+	if method.IsStreamingClient() {
+		return &domain.Response{}, fmt.Errorf("%s is a %s method, only unary and server streaming methods can be called", target.Method, grpcMethod(method).Kind())
+	}
+	in := dynamicpb.NewMessage(method.Input())
+	if strings.TrimSpace(req.Body) != "" {
+		if err := protojson.Unmarshal([]byte(req.Body), in); err != nil {
+			return &domain.Response{}, fmt.Errorf("invalid %s message: %w", method.Input().FullName(), err)
+		}
+	}
+
+	streaming := method.IsStreamingServer()
+	headersReceived := func() {}
+	if streaming {
+		var cancel func()
+		ctx, headersReceived, cancel = withHeaderTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	} else if cfg.Timeout > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	body := &grpcBody{maxMemory: s.responseCfg.MaxBodyBytes()}
+	received := 0
+	var header, trailer metadata.MD
+
+	stream, err := client.conn.NewStream(client.context(ctx), &grpc.StreamDesc{ServerStreams: streaming}, "/"+target.Method)
+	if err == nil {
+		err = sendGRPCRequest(stream, in)
+	}
+	started := false
+	if err == nil {
+		header, err = stream.Header()
+		started = err == nil
+		headersReceived()
+		for err == nil {
+			out := dynamicpb.NewMessage(method.Output())
+			if err = stream.RecvMsg(out); err != nil {
+				break
+			}
+			data, err := formatMessage(out, false)
+			if err != nil {
+				return &domain.Response{}, err
+			}
+			received++
+			body.add(data)
+			if onMessage != nil {
+				onMessage(domain.Event{Type: string(method.Output().FullName()), Data: string(data), ReceivedAt: time.Now()})
+			}
+			if !streaming {
+				break
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+		trailer = stream.Trailer()
+	}
+	if err != nil && ctx.Err() != nil {
+		switch {
+		case errors.Is(context.Cause(ctx), errHeaderTimeout):
+			return &domain.Response{}, errHeaderTimeout
+		case errors.Is(ctx.Err(), context.Canceled) && (!streaming || !started):
+			// cancelling is how a stream is stopped once it has started,
+			// before that it cancels the call
+			return &domain.Response{}, context.Canceled
+		}
+	}
+
+	st := status.Convert(err)
+	resp := &domain.Response{
+		Status:        fmt.Sprintf("%d %s", st.Code(), domain.GRPCStatusText(int(st.Code()))),
+		StatusCode:    int(st.Code()),
+		ContentType:   domain.ContentTypeGRPC,
+		ContentLenght: body.size,
+		Headers:       metadataHeader(header),
+		Body:          body.String(),
+		BodySize:      body.size,
+		Truncated:     body.truncated,
+		ResponseTime:  time.Since(start),
+		Proto:         "HTTP/2.0",
+		URL:           req.URL,
+		GRPC:          &domain.GRPCStatus{Message: st.Message(), Trailers: metadataHeader(trailer)},
+	}
+	if streaming {
+		resp.Stream = &domain.StreamStats{Events: received, Duration: resp.ResponseTime}
+	}
+	return resp, nil
+}
+
+func sendGRPCRequest(stream grpc.ClientStream, in proto.Message) error {
+	if err := stream.SendMsg(in); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	// a failed send leaves its reason for RecvMsg to return
+	return stream.CloseSend()
+}
+
+// grpcClient is a connection to a gRPC server with the metadata sent on
+// every call and where its service descriptions come from.
+type grpcClient struct {
+	conn     *grpc.ClientConn
+	metadata metadata.MD
+	source   descriptorSource
+}
+
+func (c *grpcClient) context(ctx context.Context) context.Context {
+	return metadata.NewOutgoingContext(ctx, c.metadata)
+}
+
+func (c *grpcClient) close() {
+	_ = c.conn.Close()
+}
+
+func (s *httpClientService) dialGRPC(ctx context.Context, req *domain.Request, target domain.GRPCTarget, cfg config.HTTPConfig) (*grpcClient, error) {
+	if err := req.Auth.Validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	creds := insecure.NewCredentials()
+	if target.TLS {
+		transport, err := s.transport(cfg)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(transport.TLSClientConfig)
+	}
+
+	md, err := s.grpcMetadata(ctx, req, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var source descriptorSource
+	if req.Options != nil && req.Options.ProtoFiles != "" {
+		source, err = compileProtoFiles(ctx, strings.Fields(req.Options.ProtoFiles))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	options := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if userAgent := req.Headers["User-Agent"]; userAgent != "" {
+		options = append(options, grpc.WithUserAgent(userAgent))
+	}
+	conn, err := grpc.NewClient(target.Address, options...)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		source = newReflectionSource(conn)
+	}
+	return &grpcClient{conn: conn, metadata: md, source: source}, nil
+}
+
+// grpcMetadata turns the headers and auth of req into the metadata sent with
+// every call. gRPC sets its own user agent and content type.
+func (s *httpClientService) grpcMetadata(ctx context.Context, req *domain.Request, cfg config.HTTPConfig) (metadata.MD, error) {
+	md := metadata.MD{}
+	for key, value := range req.Headers {
+		switch strings.ToLower(key) {
+		case "user-agent", "content-type":
+			continue
+		}
+		md.Append(strings.ToLower(key), value)
+	}
+
+	auth := req.Auth
+	if auth == nil {
+		return md, nil
+	}
+	switch {
+	case auth.Type == domain.AuthDigest:
+		return nil, errors.New("digest auth cannot be used with gRPC")
+	case auth.Type == domain.AuthAPIKey && auth.In == domain.APIKeyInQuery:
+		return nil, errors.New("gRPC calls have no query, send the api key in a header")
+	case auth.Type == domain.AuthOAuth2:
+		client, err := s.httpClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+		token, err := s.oauth2Token(ctx, client, *auth)
+		if err != nil {
+			return nil, fmt.Errorf("could not get oauth2 token: %w", err)
+		}
+		auth = &domain.Auth{Type: domain.AuthBearer, Token: token}
+	}
+
+	// the auth is applied the way it is to an HTTP request and its headers
+	// are sent as metadata
+	httpReq := &http.Request{Header: http.Header{}, URL: &url.URL{}}
+	auth.Apply(httpReq)
+	for key, values := range httpReq.Header {
+		md.Set(strings.ToLower(key), values...)
+	}
+	return md, nil
+}
+
+func findGRPCMethod(ctx context.Context, source descriptorSource, path string) (protoreflect.MethodDescriptor, error) {
+	serviceName, methodName, _ := strings.Cut(path, "/")
+	service, err := source.findService(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	method := service.Methods().ByName(protoreflect.Name(methodName))
+	if method == nil {
+		return nil, fmt.Errorf("service %s has no method %s", serviceName, methodName)
+	}
+	return method, nil
+}
+
+func grpcMethod(method protoreflect.MethodDescriptor) domain.GRPCMethod {
+	template, _ := formatMessage(dynamicpb.NewMessage(method.Input()), true)
+	return domain.GRPCMethod{
+		Service:         string(method.Parent().FullName()),
+		Name:            string(method.Name()),
+		ClientStreaming: method.IsStreamingClient(),
+		ServerStreaming: method.IsStreamingServer(),
+		Template:        string(template),
+	}
+}
+
+// formatMessage writes msg as indented JSON. protojson varies its spacing on
+// purpose, so its output is indented again to read the same every time.
+func formatMessage(msg proto.Message, emitUnpopulated bool) ([]byte, error) {
+	data, err := protojson.MarshalOptions{EmitUnpopulated: emitUnpopulated}.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode %s: %w", msg.ProtoReflect().Descriptor().FullName(), err)
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// metadataHeader converts gRPC metadata for display, binary values are
+// base64 encoded as they are on the wire.
+func metadataHeader(md metadata.MD) http.Header {
+	if len(md) == 0 {
+		return nil
+	}
+	header := http.Header{}
+	for key, values := range md {
+		for _, value := range values {
+			if strings.HasSuffix(key, "-bin") {
+				value = base64.RawStdEncoding.EncodeToString([]byte(value))
+			}
+			header.Add(key, value)
+		}
+	}
+	return header
+}
+
+// grpcBody keeps the response messages, one after the other, up to
+// maxMemory bytes.
+type grpcBody struct {
+	strings.Builder
+	maxMemory int64
+	size      int64
+	truncated bool
+}
+
+func (b *grpcBody) add(message []byte) {
+	if b.size > 0 {
+		message = append([]byte("\n"), message...)
+	}
+	b.size += int64(len(message))
+	if b.maxMemory > 0 && int64(b.Len()+len(message)) > b.maxMemory {
+		b.truncated = true
+		return
+	}
+	b.Write(message)
+}
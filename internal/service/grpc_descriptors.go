@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/bufbuild/protocompile/linker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// descriptorSource describes the services of a gRPC server.
+type descriptorSource interface {
+	// services lists what the server offers, leaving out server reflection
+	services(ctx context.Context) ([]protoreflect.ServiceDescriptor, error)
+	findService(ctx context.Context, name string) (protoreflect.ServiceDescriptor, error)
+}
+
+// protoFiles describes services with .proto files compiled when the call is
+// made.
+type protoFiles struct {
+	files linker.Files
+}
+
+// compileProtoFiles compiles the files at paths, each one's directory is
+// searched for the files it imports along with the well known types.
+func compileProtoFiles(ctx context.Context, paths []string) (*protoFiles, error) {
+	var importPaths, names []string
+	for _, path := range paths {
+		dir, name := filepath.Split(path)
+		if dir == "" {
+			dir = "."
+		}
+		if !slices.Contains(importPaths, dir) {
+			importPaths = append(importPaths, dir)
+		}
+		names = append(names, name)
+	}
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{ImportPaths: importPaths}),
+	}
+	files, err := compiler.Compile(ctx, names...)
+	if err != nil {
+		return nil, fmt.Errorf("could not compile proto files: %w", err)
+	}
+	return &protoFiles{files: files}, nil
+}
+
+func (p *protoFiles) services(context.Context) ([]protoreflect.ServiceDescriptor, error) {
+	var services []protoreflect.ServiceDescriptor
+	for _, file := range p.files {
+		for i := range file.Services().Len() {
+			services = append(services, file.Services().Get(i))
+		}
+	}
+	return services, nil
+}
+
+func (p *protoFiles) findService(_ context.Context, name string) (protoreflect.ServiceDescriptor, error) {
+	descriptor, err := p.files.AsResolver().FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, fmt.Errorf("service %s not found in the proto files", name)
+	}
+	service, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", name)
+	}
+	return service, nil
+}
+
+const (
+	reflectionV1      = "/grpc.reflection.v1.ServerReflection/ServerReflectionInfo"
+	reflectionV1Alpha = "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo"
+)
+
+// reflectionSource asks the server for its services with server reflection.
+// Both versions of the reflection service share their messages, so servers
+// only offering v1alpha are asked with the same ones.
+type reflectionSource struct {
+	conn *grpc.ClientConn
+	// method is the reflection version the server answered
+	method string
+	files  map[string]*descriptorpb.FileDescriptorProto
+}
+
+func newReflectionSource(conn *grpc.ClientConn) *reflectionSource {
+	return &reflectionSource{conn: conn, files: make(map[string]*descriptorpb.FileDescriptorProto)}
+}
+
+func (r *reflectionSource) services(ctx context.Context) ([]protoreflect.ServiceDescriptor, error) {
+	resp, err := r.ask(ctx, &reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{ListServices: "*"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var services []protoreflect.ServiceDescriptor
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		if strings.HasPrefix(service.GetName(), "grpc.reflection.") {
+			continue
+		}
+		descriptor, err := r.findService(ctx, service.GetName())
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, descriptor)
+	}
+	return services, nil
+}
+
+func (r *reflectionSource) findService(ctx context.Context, name string) (protoreflect.ServiceDescriptor, error) {
+	resp, err := r.ask(ctx, &reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: name},
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil, fmt.Errorf("service %s not found on the server", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := r.addFiles(resp); err != nil {
+		return nil, err
+	}
+	if err := r.addDependencies(ctx); err != nil {
+		return nil, err
+	}
+
+	files, err := protodesc.NewFiles(&descriptorpb.FileDescriptorSet{File: slices.Collect(maps.Values(r.files))})
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptors from the server: %w", err)
+	}
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, fmt.Errorf("service %s not found on the server", name)
+	}
+	service, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", name)
+	}
+	return service, nil
+}
+
+func (r *reflectionSource) addFiles(resp *reflectionpb.ServerReflectionResponse) error {
+	for _, data := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		file := &descriptorpb.FileDescriptorProto{}
+		if err := proto.Unmarshal(data, file); err != nil {
+			return fmt.Errorf("invalid descriptor from the server: %w", err)
+		}
+		r.files[file.GetName()] = file
+	}
+	return nil
+}
+
+// addDependencies asks for the imported files the server has not sent yet.
+func (r *reflectionSource) addDependencies(ctx context.Context) error {
+	for {
+		missing := r.missingDependency()
+		if missing == "" {
+			return nil
+		}
+
+		resp, err := r.ask(ctx, &reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_FileByFilename{FileByFilename: missing},
+		})
+		if err == nil {
+			err = r.addFiles(resp)
+		}
+		if _, ok := r.files[missing]; ok {
+			continue
+		}
+
+		// servers may leave out the well known types they were built with
+		file, findErr := protoregistry.GlobalFiles.FindFileByPath(missing)
+		if findErr != nil {
+			if err == nil {
+				err = fmt.Errorf("server did not send %s", missing)
+			}
+			return fmt.Errorf("could not load %s: %w", missing, err)
+		}
+		r.files[missing] = protodesc.ToFileDescriptorProto(file)
+	}
+}
+
+func (r *reflectionSource) missingDependency() string {
+	for _, file := range r.files {
+		for _, dependency := range file.GetDependency() {
+			if _, ok := r.files[dependency]; !ok {
+				return dependency
+			}
+		}
+	}
+	return ""
+}
+
+func (r *reflectionSource) ask(ctx context.Context, req *reflectionpb.ServerReflectionRequest) (*reflectionpb.ServerReflectionResponse, error) {
+	methods := []string{reflectionV1, reflectionV1Alpha}
+	if r.method != "" {
+		methods = []string{r.method}
+	}
+
+	var err error
+	for _, method := range methods {
+		var resp *reflectionpb.ServerReflectionResponse
+		resp, err = r.askWith(ctx, method, req)
+		if status.Code(err) == codes.Unimplemented {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		r.method = method
+		if errResp := resp.GetErrorResponse(); errResp != nil {
+			return nil, status.Error(codes.Code(errResp.GetErrorCode()), errResp.GetErrorMessage())
+		}
+		return resp, nil
+	}
+	return nil, fmt.Errorf("server reflection is not available, load the service with the proto option: %w", err)
+}
+
+func (r *reflectionSource) askWith(ctx context.Context, method string, req *reflectionpb.ServerReflectionRequest) (*reflectionpb.ServerReflectionResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := r.conn.NewStream(ctx, &grpc.StreamDesc{ClientStreams: true, ServerStreams: true}, method)
+	if err != nil {
+		return nil, err
+	}
+	if err := sendGRPCRequest(stream, req); err != nil {
+		return nil, err
+	}
+	resp := &reflectionpb.ServerReflectionResponse{}
+	if err := stream.RecvMsg(resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// newGRPCServer serves the health service with reflection, calls without an
+// x-token of secret are refused.
+func newGRPCServer(t *testing.T) (string, *health.Server) {
+	t.Helper()
+	checkToken := func(ctx context.Context) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if tokens := md.Get("x-token"); len(tokens) == 0 || tokens[0] != "secret" {
+			return status.Error(codes.Unauthenticated, "missing token")
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs("x-served-by", "test"))
+		_ = grpc.SetTrailer(ctx, metadata.Pairs("x-checked", "yes"))
+		return nil
+	}
+
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if err := checkToken(ctx); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if info.FullMethod != reflectionV1 && info.FullMethod != reflectionV1Alpha {
+				if err := checkToken(ss.Context()); err != nil {
+					return err
+				}
+			}
+			return handler(srv, ss)
+		}),
+	)
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("payments", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return "grpc://" + listener.Addr().String(), healthServer
+}
+
+func grpcRequest(url, body string) *domain.Request {
+	return &domain.Request{Method: domain.MethodGRPC, URL: url, Body: body, Headers: map[string]string{"X-Token": "secret"}}
+}
+
+func TestListGRPCMethodsWithReflection(t *testing.T) {
+	url, _ := newGRPCServer(t)
+
+	s := &httpClientService{httpCfg: defaultHTTPConfig()}
+	methods, err := s.ListGRPCMethods(context.Background(), grpcRequest(url, ""))
+	require.NoError(t, err)
+
+	byPath := make(map[string]domain.GRPCMethod)
+	for _, m := range methods {
+		byPath[m.Path()] = m
+	}
+	require.Contains(t, byPath, "grpc.health.v1.Health/Check")
+	assert.Equal(t, "unary", byPath["grpc.health.v1.Health/Check"].Kind())
+	assert.JSONEq(t, `{"service": ""}`, byPath["grpc.health.v1.Health/Check"].Template)
+	assert.Equal(t, "server streaming", byPath["grpc.health.v1.Health/Watch"].Kind())
+}
+
+func TestCallGRPCUnary(t *testing.T) {
+	url, _ := newGRPCServer(t)
+	s := &httpClientService{httpCfg: defaultHTTPConfig()}
+
+	var messages []domain.Event
+	resp, err := s.CallGRPC(context.Background(), grpcRequest(url+"/grpc.health.v1.Health/Check", `{"service":"payments"}`), func(e domain.Event) {
+		messages = append(messages, e)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "0 OK", resp.Status)
+	assert.True(t, resp.Success())
+	assert.JSONEq(t, `{"status":"SERVING"}`, resp.Body)
+	assert.Equal(t, "test", resp.Headers.Get("X-Served-By"))
+	assert.Equal(t, "yes", resp.GRPC.Trailers.Get("X-Checked"))
+	require.Len(t, messages, 1)
+	assert.Equal(t, "grpc.health.v1.HealthCheckResponse", messages[0].Type)
+	assert.Nil(t, resp.Stream)
+
+	resp, err = s.CallGRPC(context.Background(), grpcRequest(url+"/grpc.health.v1.Health/Check", `{"service":"missing"}`), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "5 NotFound", resp.Status)
+	assert.False(t, resp.Success())
+	assert.Equal(t, "unknown service", resp.GRPC.Message)
+
+	req := grpcRequest(url+"/grpc.health.v1.Health/Check", "")
+	delete(req.Headers, "X-Token")
+	resp, err = s.CallGRPC(context.Background(), req, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 16, resp.StatusCode)
+	assert.Equal(t, "missing token", resp.GRPC.Message)
+}
+
+func TestCallGRPCErrors(t *testing.T) {
+	url, _ := newGRPCServer(t)
+	s := &httpClientService{httpCfg: defaultHTTPConfig()}
+
+	_, err := s.CallGRPC(context.Background(), grpcRequest(url+"/grpc.health.v1.Health/Check", `{"nope":1}`), nil)
+	assert.ErrorContains(t, err, "invalid grpc.health.v1.HealthCheckRequest message")
+
+	_, err = s.CallGRPC(context.Background(), grpcRequest(url+"/grpc.health.v1.Health/Missing", ""), nil)
+	assert.ErrorContains(t, err, "service grpc.health.v1.Health has no method Missing")
+
+	_, err = s.CallGRPC(context.Background(), grpcRequest(url+"/shop.Orders/Get", ""), nil)
+	assert.ErrorContains(t, err, "service shop.Orders not found on the server")
+}
+
+func TestCallGRPCServerStreamStop(t *testing.T) {
+	url, healthServer := newGRPCServer(t)
+	s := &httpClientService{httpCfg: defaultHTTPConfig()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := make(chan string, 2)
+	done := make(chan *domain.Response, 1)
+	go func() {
+		resp, err := s.CallGRPC(ctx, grpcRequest(url+"/grpc.health.v1.Health/Watch", `{"service":"payments"}`), func(e domain.Event) {
+			updates <- e.Data
+		})
+		assert.NoError(t, err)
+		done <- resp
+	}()
+
+	assert.JSONEq(t, `{"status":"SERVING"}`, <-updates)
+	healthServer.SetServingStatus("payments", healthpb.HealthCheckResponse_NOT_SERVING)
+	assert.JSONEq(t, `{"status":"NOT_SERVING"}`, <-updates)
+	cancel()
+
+	resp := <-done
+	assert.Equal(t, "1 Canceled", resp.Status)
+	assert.Equal(t, 2, resp.Stream.Events)
+	assert.Equal(t, "test", resp.Headers.Get("X-Served-By"))
+}
+
+func TestListGRPCMethodsWithProtoFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "types.proto"), []byte(`syntax = "proto3";
+package shop;
+import "google/protobuf/timestamp.proto";
+message Order { string id = 1; google.protobuf.Timestamp created = 2; }
+`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "orders.proto"), []byte(`syntax = "proto3";
+package shop;
+import "types.proto";
+message GetOrderRequest { string id = 1; }
+service Orders {
+  rpc Get(GetOrderRequest) returns (Order);
+  rpc Follow(GetOrderRequest) returns (stream Order);
+  rpc Upload(stream Order) returns (Order);
+}
+`), 0o644))
+
+	req := grpcRequest("grpc://127.0.0.1:1", "")
+	req.Options = &domain.ClientOptions{ProtoFiles: filepath.Join(dir, "orders.proto")}
+
+	s := &httpClientService{httpCfg: defaultHTTPConfig()}
+	methods, err := s.ListGRPCMethods(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, methods, 3)
+	assert.Equal(t, "shop.Orders/Get", methods[0].Path())
+	assert.JSONEq(t, `{"id": ""}`, methods[0].Template)
+	assert.Equal(t, "server streaming", methods[1].Kind())
+	assert.Equal(t, "client streaming", methods[2].Kind())
+
+	req.URL += "/shop.Orders/Upload"
+	_, err = s.CallGRPC(context.Background(), req, nil)
+	assert.ErrorContains(t, err, "only unary and server streaming methods can be called")
+
+	req.Options.ProtoFiles = filepath.Join(dir, "missing.proto")
+	_, err = s.ListGRPCMethods(context.Background(), req)
+	assert.ErrorContains(t, err, "could not compile proto files")
+}
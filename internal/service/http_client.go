@@ -0,0 +1,114 @@
+package service
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+)
+
+// httpClient returns a client for cfg. Transports are kept per configuration
+// so connections are reused between requests with the same settings.
+func (s *httpClientService) httpClient(cfg config.HTTPConfig) (*http.Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	transport, err := s.transport(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{
+		Transport:     transport,
+		Timeout:       cfg.Timeout,
+		CheckRedirect: checkRedirect(cfg),
+	}, nil
+}
+
+func (s *httpClientService) transport(cfg config.HTTPConfig) (*http.Transport, error) {
+	// timeout and redirects are handled by the client, not the connection
+	cfg.Timeout, cfg.FollowRedirects, cfg.MaxRedirects = 0, false, 0
+
+	s.transportMu.Lock()
+	defer s.transportMu.Unlock()
+
+	if transport, ok := s.transports[cfg]; ok {
+		return transport, nil
+	}
+
+	transport, err := newTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if s.transports == nil {
+		s.transports = make(map[config.HTTPConfig]*http.Transport)
+	}
+	s.transports[cfg] = transport
+	return transport, nil
+}
+
+func newTransport(cfg config.HTTPConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy %q", cfg.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.Insecure}
+	if cfg.CACert != "" {
+		pem, err := os.ReadFile(cfg.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("could not read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	switch cfg.HTTPVersion {
+	case "1.1":
+		transport.Protocols = new(http.Protocols)
+		transport.Protocols.SetHTTP1(true)
+	case "2":
+		// plain http URLs use HTTP/2 with prior knowledge
+		transport.Protocols = new(http.Protocols)
+		transport.Protocols.SetHTTP2(true)
+		transport.Protocols.SetUnencryptedHTTP2(true)
+	}
+
+	return transport, nil
+}
+
+func checkRedirect(cfg config.HTTPConfig) func(*http.Request, []*http.Request) error {
+	return func(_ *http.Request, via []*http.Request) error {
+		if !cfg.FollowRedirects {
+			return http.ErrUseLastResponse
+		}
+		if len(via) > cfg.MaxRedirects {
+			return fmt.Errorf("stopped after %d redirects", cfg.MaxRedirects)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ManoloEsS/burrow/internal/database"
+	"github.com/ManoloEsS/burrow/internal/domain"
+)
+
+func (s *httpClientService) CreateCollection(name string, parentID int64) (*domain.Collection, error) {
+	collection := &domain.Collection{ParentID: parentID}
+	if err := collection.ParseName(name); err != nil {
+		return nil, err
+	}
+
+	tree, err := s.collectionTree()
+	if err != nil {
+		return nil, err
+	}
+	parent := tree.Node(parentID)
+	if parent == nil {
+		return nil, fmt.Errorf("collection %d not found", parentID)
+	}
+	if err := checkCollectionNameFree(parent, collection.Name, 0); err != nil {
+		return nil, err
+	}
+
+	row, err := s.requestRepo.Queries.CreateCollection(context.Background(), database.CreateCollectionParams{
+		Name:     collection.Name,
+		ParentID: nullCollectionID(parentID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not save collection: %v", err)
+	}
+
+	return collectionRowToStruct(row), nil
+}
+
+func (s *httpClientService) GetCollections() ([]*domain.Collection, error) {
+	rows, err := s.requestRepo.Queries.ListCollections(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve collections from database: %w", err)
+	}
+
+	collections := make([]*domain.Collection, 0, len(rows))
+	for _, row := range rows {
+		collections = append(collections, collectionRowToStruct(row))
+	}
+	return collections, nil
+}
+
+func (s *httpClientService) RenameCollection(id int64, name string) error {
+	collection, err := s.getCollection(id)
+	if err != nil {
+		return err
+	}
+	if err := collection.ParseName(name); err != nil {
+		return err
+	}
+
+	tree, err := s.collectionTree()
+	if err != nil {
+		return err
+	}
+	if err := checkCollectionNameFree(tree.Node(id).Parent, collection.Name, id); err != nil {
+		return err
+	}
+
+	return s.updateCollection(collection)
+}
+
+// MoveCollection moves a collection, along with everything in it, under
+// parentID. A parentID of zero moves it to the top level.
+func (s *httpClientService) MoveCollection(id, parentID int64) error {
+	collection, err := s.getCollection(id)
+	if err != nil {
+		return err
+	}
+
+	tree, err := s.collectionTree()
+	if err != nil {
+		return err
+	}
+	parent := tree.Node(parentID)
+	if parent == nil {
+		return fmt.Errorf("collection %d not found", parentID)
+	}
+	if tree.Node(id).Node(parentID) != nil {
+		return errors.New("cannot move a collection into itself")
+	}
+	if err := checkCollectionNameFree(parent, collection.Name, id); err != nil {
+		return err
+	}
+
+	collection.ParentID = parentID
+	return s.updateCollection(collection)
+}
+
+// DeleteCollection removes a collection together with its sub collections
+// and the requests saved in them.
+func (s *httpClientService) DeleteCollection(id int64) error {
+	if _, err := s.getCollection(id); err != nil {
+		return err
+	}
+
+	collections, err := s.GetCollections()
+	if err != nil {
+		return err
+	}
+	reqs, err := s.GetSavedRequests()
+	if err != nil {
+		return err
+	}
+	node := domain.BuildCollectionTree(collections, reqs).Node(id)
+
+	// the contents are removed explicitly so nothing is left behind when the
+	// connection does not enforce foreign keys
+	for _, req := range node.AllRequests() {
+		if err := s.requestRepo.Queries.DeleteRequest(context.Background(), req.Name); err != nil {
+			return fmt.Errorf("could not delete request %s: %v", req.Name, err)
+		}
+	}
+	return s.deleteCollectionNode(node)
+}
+
+// deleteCollectionNode deletes the collections below node, then node itself.
+func (s *httpClientService) deleteCollectionNode(node *domain.CollectionNode) error {
+	for _, child := range node.Children {
+		if err := s.deleteCollectionNode(child); err != nil {
+			return err
+		}
+	}
+	if err := s.requestRepo.Queries.DeleteCollection(context.Background(), node.ID()); err != nil {
+		return fmt.Errorf("could not delete collection from database: %v", err)
+	}
+	return nil
+}
+
+// MoveRequest files a saved request into a collection, zero moves it out of
+// any collection.
+func (s *httpClientService) MoveRequest(name string, collectionID int64) error {
+	if _, err := s.GetRequest(name); err != nil {
+		return err
+	}
+	if collectionID != 0 {
+		if _, err := s.getCollection(collectionID); err != nil {
+			return err
+		}
+	}
+
+	err := s.requestRepo.Queries.MoveRequest(context.Background(), database.MoveRequestParams{
+		CollectionID: nullCollectionID(collectionID),
+		Name:         name,
+	})
+	if err != nil {
+		return fmt.Errorf("could not move request: %v", err)
+	}
+	return nil
+}
+
+func (s *httpClientService) RenameRequest(oldName, newName string) error {
+	req, err := s.GetRequest(oldName)
+	if err != nil {
+		return err
+	}
+	if err := req.ParseName(newName); err != nil {
+		return err
+	}
+	if req.Name == "" {
+		return errors.New("request name required")
+	}
+	if req.Name == oldName {
+		return nil
+	}
+	if _, err := s.GetRequest(req.Name); err == nil {
+		return fmt.Errorf("request %q already exists", req.Name)
+	}
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("could not marshal request: %v", err)
+	}
+
+	err = s.requestRepo.Queries.RenameRequest(context.Background(), database.RenameRequestParams{
+		NewName:     req.Name,
+		RequestJson: jsonData,
+		Name:        oldName,
+	})
+	if err != nil {
+		return fmt.Errorf("could not rename request: %v", err)
+	}
+	return nil
+}
+
+// DuplicateRequest saves a copy of a request in the same collection under
+// the first free "<name> copy" name.
+func (s *httpClientService) DuplicateRequest(name string) (*domain.Request, error) {
+	req, err := s.GetRequest(name)
+	if err != nil {
+		return nil, err
+	}
+
+	dup := req.Clone()
+	for i := 1; ; i++ {
+		dup.Name = name + " copy"
+		if i > 1 {
+			dup.Name = fmt.Sprintf("%s copy %d", name, i)
+		}
+
+		_, err := s.requestRepo.Queries.GetRequest(context.Background(), dup.Name)
+		if errors.Is(err, sql.ErrNoRows) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve request from database: %w", err)
+		}
+	}
+
+	if err := s.SaveRequest(dup); err != nil {
+		return nil, err
+	}
+	return dup, nil
+}
+
+func (s *httpClientService) getCollection(id int64) (*domain.Collection, error) {
+	row, err := s.requestRepo.Queries.GetCollection(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("collection %d not found", id)
+		}
+		return nil, fmt.Errorf("could not retrieve collection from database: %w", err)
+	}
+	return collectionRowToStruct(row), nil
+}
+
+func (s *httpClientService) updateCollection(collection *domain.Collection) error {
+	err := s.requestRepo.Queries.UpdateCollection(context.Background(), database.UpdateCollectionParams{
+		Name:     collection.Name,
+		ParentID: nullCollectionID(collection.ParentID),
+		ID:       collection.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("could not update collection: %v", err)
+	}
+	return nil
+}
+
+func (s *httpClientService) collectionTree() (*domain.CollectionNode, error) {
+	collections, err := s.GetCollections()
+	if err != nil {
+		return nil, err
+	}
+	return domain.BuildCollectionTree(collections, nil), nil
+}
+
+// sibling collections need distinct names so paths stay unambiguous
+func checkCollectionNameFree(parent *domain.CollectionNode, name string, id int64) error {
+	for _, child := range parent.Children {
+		if child.Collection.Name == name && child.Collection.ID != id {
+			if parent.Collection == nil {
+				return fmt.Errorf("collection %q already exists", name)
+			}
+			return fmt.Errorf("collection %q already exists in %s", name, parent.Path())
+		}
+	}
+	return nil
+}
+
+func collectionRowToStruct(row database.Collection) *domain.Collection {
+	return &domain.Collection{
+		ID:       row.ID,
+		Name:     row.Name,
+		ParentID: row.ParentID.Int64,
+	}
+}
+
+func nullCollectionID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionCRUD(t *testing.T) {
+	s := newTestService(t)
+
+	payments, err := s.CreateCollection(" Payments ", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "payments", payments.Name)
+
+	refunds, err := s.CreateCollection("refunds", payments.ID)
+	require.NoError(t, err)
+	assert.Equal(t, payments.ID, refunds.ParentID)
+
+	_, err = s.CreateCollection("payments", 0)
+	assert.ErrorContains(t, err, "already exists")
+	_, err = s.CreateCollection("other", 99)
+	assert.Error(t, err)
+
+	users, err := s.CreateCollection("users", 0)
+	require.NoError(t, err)
+
+	require.NoError(t, s.RenameCollection(users.ID, "accounts"))
+	assert.ErrorContains(t, s.RenameCollection(users.ID, "payments"), "already exists")
+
+	require.NoError(t, s.MoveCollection(users.ID, refunds.ID))
+	assert.ErrorContains(t, s.MoveCollection(payments.ID, users.ID), "into itself")
+	assert.ErrorContains(t, s.MoveCollection(payments.ID, payments.ID), "into itself")
+
+	collections, err := s.GetCollections()
+	require.NoError(t, err)
+	tree := domain.BuildCollectionTree(collections, nil)
+	assert.Equal(t, []string{"payments", "payments/refunds", "payments/refunds/accounts"}, tree.Paths())
+
+	require.NoError(t, s.MoveCollection(users.ID, 0))
+	collections, err = s.GetCollections()
+	require.NoError(t, err)
+	tree = domain.BuildCollectionTree(collections, nil)
+	assert.Equal(t, []string{"accounts", "payments", "payments/refunds"}, tree.Paths())
+}
+
+func TestDeleteCollectionRemovesContents(t *testing.T) {
+	s := newTestService(t)
+
+	payments, err := s.CreateCollection("payments", 0)
+	require.NoError(t, err)
+	refunds, err := s.CreateCollection("refunds", payments.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, s.SaveRequest(&domain.Request{Name: "charge", Method: "POST", URL: "http://localhost", CollectionID: payments.ID}))
+	require.NoError(t, s.SaveRequest(&domain.Request{Name: "refund", Method: "POST", URL: "http://localhost", CollectionID: refunds.ID}))
+	require.NoError(t, s.SaveRequest(&domain.Request{Name: "health", Method: "GET", URL: "http://localhost"}))
+
+	require.NoError(t, s.DeleteCollection(payments.ID))
+
+	collections, err := s.GetCollections()
+	require.NoError(t, err)
+	assert.Empty(t, collections)
+
+	reqs, err := s.GetSavedRequests()
+	require.NoError(t, err)
+	require.Len(t, reqs, 1)
+	assert.Equal(t, "health", reqs[0].Name)
+
+	assert.Error(t, s.DeleteCollection(payments.ID))
+}
+
+func TestDeleteCollectionWithoutForeignKeys(t *testing.T) {
+	s := &httpClientService{requestRepo: openTestDatabase(t, "")}
+
+	payments, err := s.CreateCollection("payments", 0)
+	require.NoError(t, err)
+	refunds, err := s.CreateCollection("refunds", payments.ID)
+	require.NoError(t, err)
+	require.NoError(t, s.SaveRequest(&domain.Request{Name: "charge", Method: "POST", URL: "http://localhost", CollectionID: payments.ID}))
+	require.NoError(t, s.SaveRequest(&domain.Request{Name: "refund", Method: "POST", URL: "http://localhost", CollectionID: refunds.ID}))
+
+	require.NoError(t, s.DeleteCollection(payments.ID))
+
+	collections, err := s.GetCollections()
+	require.NoError(t, err)
+	assert.Empty(t, collections)
+
+	reqs, err := s.GetSavedRequests()
+	require.NoError(t, err)
+	assert.Empty(t, reqs)
+}
+
+func TestMoveRenameAndDuplicateRequest(t *testing.T) {
+	s := newTestService(t)
+
+	payments, err := s.CreateCollection("payments", 0)
+	require.NoError(t, err)
+	require.NoError(t, s.SaveRequest(&domain.Request{Name: "charge", Method: "POST", URL: "http://localhost", Body: "{}"}))
+	require.NoError(t, s.SaveRequest(&domain.Request{Name: "health", Method: "GET", URL: "http://localhost"}))
+
+	require.NoError(t, s.MoveRequest("charge", payments.ID))
+	assert.Error(t, s.MoveRequest("charge", 99))
+	assert.Error(t, s.MoveRequest("missing", payments.ID))
+
+	req, err := s.GetRequest("charge")
+	require.NoError(t, err)
+	assert.Equal(t, payments.ID, req.CollectionID)
+
+	require.NoError(t, s.RenameRequest("charge", "Create Charge"))
+	assert.ErrorContains(t, s.RenameRequest("create charge", "health"), "already exists")
+	_, err = s.GetRequest("charge")
+	assert.Error(t, err)
+
+	req, err = s.GetRequest("create charge")
+	require.NoError(t, err)
+	assert.Equal(t, "create charge", req.Name)
+	assert.Equal(t, payments.ID, req.CollectionID)
+
+	dup, err := s.DuplicateRequest("create charge")
+	require.NoError(t, err)
+	assert.Equal(t, "create charge copy", dup.Name)
+	dup, err = s.DuplicateRequest("create charge")
+	require.NoError(t, err)
+	assert.Equal(t, "create charge copy 2", dup.Name)
+
+	req, err = s.GetRequest("create charge copy 2")
+	require.NoError(t, err)
+	assert.Equal(t, "{}", req.Body)
+	assert.Equal(t, payments.ID, req.CollectionID)
+
+	require.NoError(t, s.MoveRequest("create charge copy 2", 0))
+	req, err = s.GetRequest("create charge copy 2")
+	require.NoError(t, err)
+	assert.Zero(t, req.CollectionID)
+}
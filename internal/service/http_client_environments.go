@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/ManoloEsS/burrow/internal/database"
+	"github.com/ManoloEsS/burrow/internal/domain"
+)
+
+func (s *httpClientService) SaveEnvironment(env *domain.Environment) error {
+	jsonData, err := json.Marshal(env.Variables)
+	if err != nil {
+		return fmt.Errorf("could not marshal environment variables: %v", err)
+	}
+
+	envParams := database.UpsertEnvironmentParams{
+		Name:          env.Name,
+		VariablesJson: string(jsonData),
+	}
+	_, err = s.requestRepo.Queries.UpsertEnvironment(context.Background(), envParams)
+	if err != nil {
+		return fmt.Errorf("could not save environment: %v", err)
+	}
+
+	s.envMu.Lock()
+	defer s.envMu.Unlock()
+	if s.activeEnv != nil && s.activeEnv.Name == env.Name {
+		s.activeEnv = env
+	}
+
+	return nil
+}
+
+func (s *httpClientService) DeleteEnvironment(envName string) error {
+	err := s.requestRepo.Queries.DeleteEnvironment(context.Background(), envName)
+	if err != nil {
+		log.Printf("could not delete environment from database: %v", err)
+		return fmt.Errorf("could not delete environment from database: %v", err)
+	}
+
+	s.envMu.Lock()
+	defer s.envMu.Unlock()
+	if s.activeEnv != nil && s.activeEnv.Name == envName {
+		s.activeEnv = nil
+	}
+
+	return nil
+}
+
+func (s *httpClientService) GetEnvironments() ([]*domain.Environment, error) {
+	envRows, err := s.requestRepo.Queries.ListEnvironments(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve environments from database: %w", err)
+	}
+
+	var envs []*domain.Environment
+
+	for _, e := range envRows {
+		env, err := environmentRowToStruct(e)
+		if err != nil {
+			log.Printf("could not parse environment %s: %v", e.Name, err)
+			continue
+		}
+		envs = append(envs, env)
+	}
+	return envs, nil
+}
+
+func (s *httpClientService) SetActiveEnvironment(envName string) error {
+	if envName == "" {
+		s.envMu.Lock()
+		s.activeEnv = nil
+		s.envMu.Unlock()
+		return nil
+	}
+
+	row, err := s.requestRepo.Queries.GetEnvironment(context.Background(), envName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("environment %q not found", envName)
+		}
+		return fmt.Errorf("could not retrieve environment: %w", err)
+	}
+
+	env, err := environmentRowToStruct(row)
+	if err != nil {
+		return err
+	}
+
+	s.envMu.Lock()
+	s.activeEnv = env
+	s.envMu.Unlock()
+
+	return nil
+}
+
+func (s *httpClientService) GetActiveEnvironment() *domain.Environment {
+	s.envMu.RLock()
+	defer s.envMu.RUnlock()
+	return s.activeEnv
+}
+
+func environmentRowToStruct(row database.Environment) (*domain.Environment, error) {
+	env := domain.NewEnvironment()
+	env.Name = row.Name
+
+	if err := json.Unmarshal([]byte(row.VariablesJson), &env.Variables); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal variables: %w", err)
+	}
+
+	return env, nil
+}
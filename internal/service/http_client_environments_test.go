@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ManoloEsS/burrow/internal/database"
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironmentRowToStruct(t *testing.T) {
+	tests := []struct {
+		name        string
+		row         database.Environment
+		expectError bool
+		expected    map[string]string
+	}{
+		{
+			name:     "Valid variables JSON",
+			row:      database.Environment{Name: "dev", VariablesJson: `{"port":"3000"}`},
+			expected: map[string]string{"port": "3000"},
+		},
+		{
+			name:        "Invalid variables JSON",
+			row:         database.Environment{Name: "dev", VariablesJson: `{"port":`},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, err := environmentRowToStruct(tt.row)
+			if tt.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, env)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.row.Name, env.Name)
+			assert.Equal(t, tt.expected, env.Variables)
+		})
+	}
+}
+
+func TestSendRequestResolvesActiveEnvironment(t *testing.T) {
+	var gotPath, gotHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotHeader = r.Header.Get("X-Token")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	s := &httpClientService{
+		activeEnv: &domain.Environment{
+			Name:      "test",
+			Variables: map[string]string{"base": server.URL, "token": "abc"},
+		},
+	}
+
+	req := &domain.Request{
+		Method:  "GET",
+		URL:     "{{base}}/users",
+		Headers: map[string]string{"X-Token": "{{token}}"},
+	}
+
+	resp, err := s.SendRequest(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "200 OK", resp.Status)
+	assert.Equal(t, "/users", gotPath)
+	assert.Equal(t, "abc", gotHeader)
+	assert.Equal(t, "{{base}}/users", req.URL)
+}
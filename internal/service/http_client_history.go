@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/database"
+	"github.com/ManoloEsS/burrow/internal/domain"
+)
+
+func (s *httpClientService) recordHistory(req *domain.Request, resp *domain.Response, sendErr error, sentAt time.Time) {
+	if s.requestRepo == nil {
+		return
+	}
+
+	requestJSON, err := json.Marshal(req.Redacted())
+	if err != nil {
+		log.Printf("could not marshal request for history: %v", err)
+		return
+	}
+
+	params := database.CreateHistoryEntryParams{
+		SentAt:       sentAt.UTC(),
+		Method:       req.Method,
+		Url:          req.URL,
+		RequestJson:  string(requestJSON),
+		ResponseJson: "null",
+	}
+
+	if sendErr != nil {
+		params.Error = sendErr.Error()
+	} else {
+		responseJSON, err := json.Marshal(resp.Redacted())
+		if err != nil {
+			log.Printf("could not marshal response for history: %v", err)
+			return
+		}
+		params.StatusCode = int64(resp.StatusCode)
+		params.ResponseJson = string(responseJSON)
+	}
+	params.Size = int64(len(params.RequestJson) + len(params.ResponseJson))
+
+	_, err = s.requestRepo.Queries.CreateHistoryEntry(context.Background(), params)
+	if err != nil {
+		log.Printf("could not save history entry: %v", err)
+		return
+	}
+
+	if err := s.pruneHistory(); err != nil {
+		log.Printf("could not prune history: %v", err)
+	}
+}
+
+func (s *httpClientService) pruneHistory() error {
+	ctx := context.Background()
+	queries := s.requestRepo.Queries
+
+	if s.historyCfg.MaxAge > 0 {
+		if err := queries.DeleteHistoryBefore(ctx, time.Now().Add(-s.historyCfg.MaxAge).UTC()); err != nil {
+			return err
+		}
+	}
+	if s.historyCfg.MaxEntries > 0 {
+		if err := queries.DeleteHistoryBeyondCount(ctx, int64(s.historyCfg.MaxEntries)); err != nil {
+			return err
+		}
+	}
+	if s.historyCfg.MaxSizeMB > 0 {
+		if err := queries.DeleteHistoryBeyondSize(ctx, int64(s.historyCfg.MaxSizeMB)*1024*1024); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// likeEscaper makes the wildcards of a search match themselves in a LIKE
+// pattern with ESCAPE '\'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (s *httpClientService) GetHistory(query string, limit int) ([]*domain.HistoryEntry, error) {
+	var (
+		rows []database.History
+		err  error
+	)
+	if query == "" {
+		rows, err = s.requestRepo.Queries.ListHistory(context.Background(), int64(limit))
+	} else {
+		rows, err = s.requestRepo.Queries.SearchHistory(context.Background(), database.SearchHistoryParams{
+			Pattern: "%" + likeEscaper.Replace(query) + "%",
+			Limit:   int64(limit),
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve history from database: %w", err)
+	}
+
+	var entries []*domain.HistoryEntry
+	for _, row := range rows {
+		entry, err := historyRowToStruct(row)
+		if err != nil {
+			log.Printf("could not parse history entry %d: %v", row.ID, err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (s *httpClientService) GetHistoryEntry(id int64) (*domain.HistoryEntry, error) {
+	row, err := s.requestRepo.Queries.GetHistoryEntry(context.Background(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("history entry %d not found", id)
+		}
+		return nil, fmt.Errorf("could not retrieve history entry from database: %w", err)
+	}
+
+	return historyRowToStruct(row)
+}
+
+func (s *httpClientService) DeleteHistoryEntry(id int64) error {
+	err := s.requestRepo.Queries.DeleteHistoryEntry(context.Background(), id)
+	if err != nil {
+		return fmt.Errorf("could not delete history entry from database: %v", err)
+	}
+	return nil
+}
+
+func (s *httpClientService) ClearHistory() error {
+	err := s.requestRepo.Queries.ClearHistory(context.Background())
+	if err != nil {
+		return fmt.Errorf("could not clear history: %v", err)
+	}
+	return nil
+}
+
+func historyRowToStruct(row database.History) (*domain.HistoryEntry, error) {
+	entry := &domain.HistoryEntry{
+		ID:     row.ID,
+		SentAt: row.SentAt,
+		Error:  row.Error,
+	}
+
+	if err := json.Unmarshal([]byte(row.RequestJson), &entry.Request); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal request JSON: %w", err)
+	}
+	if err := json.Unmarshal([]byte(row.ResponseJson), &entry.Response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response JSON: %w", err)
+	}
+
+	return entry, nil
+}
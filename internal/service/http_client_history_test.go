@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/ManoloEsS/burrow/internal/database"
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newHistoryTestService(t *testing.T, historyCfg config.HistoryConfig) *httpClientService {
+	t.Helper()
+
+	dbPath := filepath.Join(t.TempDir(), "burrow.db")
+	db, err := database.NewDatabase(dbPath, "file:"+dbPath+"?_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	return &httpClientService{requestRepo: db, historyCfg: historyCfg}
+}
+
+func TestHistoryRowToStruct(t *testing.T) {
+	tests := []struct {
+		name        string
+		row         database.History
+		expectError bool
+	}{
+		{
+			name: "Entry with response",
+			row:  database.History{ID: 1, RequestJson: `{"method":"GET","url":"http://localhost"}`, ResponseJson: `{"status_code":200}`},
+		},
+		{
+			name: "Entry with transport error",
+			row:  database.History{ID: 2, RequestJson: `{"method":"GET"}`, ResponseJson: "null", Error: "refused"},
+		},
+		{
+			name:        "Invalid request JSON",
+			row:         database.History{ID: 3, RequestJson: `{"method":`, ResponseJson: "null"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := historyRowToStruct(tt.row)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.row.ID, entry.ID)
+			assert.Equal(t, tt.row.Error, entry.Error)
+			assert.Equal(t, tt.row.Error == "", entry.Response != nil)
+		})
+	}
+}
+
+func TestSendRequestRecordsHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Server", "test")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("made"))
+	}))
+	defer server.Close()
+
+	s := newHistoryTestService(t, config.HistoryConfig{})
+	s.activeEnv = &domain.Environment{Name: "dev", Variables: map[string]string{"base": server.URL, "token": "secret"}}
+
+	req := &domain.Request{Method: "POST", URL: "{{base}}/users", Headers: map[string]string{"X-Token": "{{token}}"}}
+	_, err := s.SendRequest(context.Background(), req)
+	require.NoError(t, err)
+
+	_, err = s.SendRequest(context.Background(), &domain.Request{Method: "GET", URL: "http://127.0.0.1:1/down"})
+	require.Error(t, err)
+
+	entries, err := s.GetHistory("", 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	failed, sent := entries[0], entries[1]
+	assert.NotEmpty(t, failed.Error)
+	assert.Nil(t, failed.Response)
+
+	assert.Equal(t, "{{base}}/users", sent.Request.URL)
+	assert.Equal(t, "{{token}}", sent.Request.Headers["X-Token"])
+	require.NotNil(t, sent.Response)
+	assert.Equal(t, 201, sent.Response.StatusCode)
+	assert.Equal(t, "made", sent.Response.Body)
+	assert.Equal(t, "test", sent.Response.Headers.Get("X-Server"))
+	assert.WithinDuration(t, time.Now(), sent.SentAt, time.Minute)
+
+	found, err := s.GetHistory("users", 10)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, sent.ID, found[0].ID)
+
+	byStatus, err := s.GetHistory("201", 10)
+	require.NoError(t, err)
+	assert.Len(t, byStatus, 1)
+
+	entry, err := s.GetHistoryEntry(sent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "POST", entry.Request.Method)
+
+	require.NoError(t, s.DeleteHistoryEntry(sent.ID))
+	_, err = s.GetHistoryEntry(sent.ID)
+	assert.Error(t, err)
+
+	require.NoError(t, s.ClearHistory())
+	entries, err = s.GetHistory("", 10)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestSendRequestRedactsHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"issued-token","token_type":"Bearer"}`))
+	}))
+	defer server.Close()
+
+	s := newHistoryTestService(t, config.HistoryConfig{})
+	req := &domain.Request{Method: "POST", URL: server.URL, Headers: map[string]string{"Authorization": "Bearer plain-token"}}
+	_, err := s.SendRequest(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer plain-token", req.Headers["Authorization"])
+
+	rows, err := s.requestRepo.Queries.ListHistory(context.Background(), 10)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.NotContains(t, rows[0].RequestJson, "plain-token")
+	assert.NotContains(t, rows[0].ResponseJson, "issued-token")
+
+	entry, err := s.GetHistoryEntry(rows[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer [redacted]", entry.Request.Headers["Authorization"])
+}
+
+func TestPruneHistory(t *testing.T) {
+	record := func(s *httpClientService, n int, sentAt time.Time) {
+		for i := range n {
+			req := &domain.Request{Method: "GET", URL: fmt.Sprintf("http://localhost/%d", i)}
+			s.recordHistory(req, &domain.Response{StatusCode: 200, Body: strings.Repeat("a", 300*1024)}, nil, sentAt)
+		}
+	}
+
+	t.Run("Max entries", func(t *testing.T) {
+		s := newHistoryTestService(t, config.HistoryConfig{MaxEntries: 3})
+		record(s, 5, time.Now())
+
+		entries, err := s.GetHistory("", 10)
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		assert.Equal(t, "http://localhost/4", entries[0].Request.URL)
+	})
+
+	t.Run("Max age", func(t *testing.T) {
+		s := newHistoryTestService(t, config.HistoryConfig{MaxAge: time.Hour})
+		record(s, 2, time.Now().Add(-2*time.Hour))
+		record(s, 1, time.Now())
+
+		entries, err := s.GetHistory("", 10)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("Max size", func(t *testing.T) {
+		s := newHistoryTestService(t, config.HistoryConfig{MaxSizeMB: 1})
+		record(s, 6, time.Now())
+
+		entries, err := s.GetHistory("", 10)
+		require.NoError(t, err)
+		assert.Len(t, entries, 3)
+
+		s.recordHistory(&domain.Request{Method: "GET", URL: "http://localhost/big"}, &domain.Response{Body: strings.Repeat("a", 2*1024*1024)}, nil, time.Now())
+		entries, err = s.GetHistory("", 10)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "http://localhost/big", entries[0].Request.URL)
+	})
+}
+
+func TestSearchHistoryWildcards(t *testing.T) {
+	s := newHistoryTestService(t, config.HistoryConfig{})
+	for _, url := range []string{"http://localhost/a%20b", "http://localhost/user_id", "http://localhost/users"} {
+		s.recordHistory(&domain.Request{Method: "GET", URL: url}, &domain.Response{StatusCode: 200}, nil, time.Now())
+	}
+
+	for query, want := range map[string]string{"%": "http://localhost/a%20b", "_": "http://localhost/user_id"} {
+		entries, err := s.GetHistory(query, 10)
+		require.NoError(t, err)
+		require.Len(t, entries, 1, query)
+		assert.Equal(t, want, entries[0].Request.URL)
+	}
+}
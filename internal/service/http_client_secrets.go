@@ -0,0 +1,289 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ManoloEsS/burrow/internal/database"
+	"github.com/ManoloEsS/burrow/internal/domain"
+)
+
+// secretKeyIterations is the PBKDF2 work factor turning the passphrase into
+// the key of the database store.
+var secretKeyIterations = 600_000
+
+const secretSaltSize = 16
+
+type secretKeyID struct {
+	salt       string
+	passphrase string
+}
+
+// lookupSecret resolves a secret from the environment, the secrets file or
+// the encrypted database store, in that order.
+func (s *httpClientService) lookupSecret(name string) (string, error) {
+	if val, ok := s.envSecret(name); ok {
+		return val, nil
+	}
+
+	fileSecrets, err := readSecretsFile(s.secretsCfg.File)
+	if err != nil {
+		return "", err
+	}
+	if val, ok := fileSecrets[name]; ok {
+		return val, nil
+	}
+
+	if s.requestRepo == nil {
+		return "", domain.ErrSecretNotFound
+	}
+	row, err := s.requestRepo.Queries.GetSecret(context.Background(), name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", domain.ErrSecretNotFound
+		}
+		return "", fmt.Errorf("could not retrieve secret: %w", err)
+	}
+	return s.decryptSecret(row)
+}
+
+// SaveSecret encrypts value with the passphrase and stores it in the database.
+func (s *httpClientService) SaveSecret(name, value string) error {
+	if err := domain.ValidateSecretName(name); err != nil {
+		return err
+	}
+
+	passphrase, err := s.passphrase()
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, secretSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	gcm, err := s.secretCipher(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	err = s.requestRepo.Queries.UpsertSecret(context.Background(), database.UpsertSecretParams{
+		Name:  name,
+		Salt:  salt,
+		Nonce: nonce,
+		// the name is authenticated so a ciphertext cannot be moved to another secret
+		Ciphertext: gcm.Seal(nil, nonce, []byte(value), []byte(name)),
+	})
+	if err != nil {
+		return fmt.Errorf("could not save secret: %v", err)
+	}
+	return nil
+}
+
+// storeCredential saves a credential of a request in the database store
+// under name, or under name-2, name-3... when name already resolves to
+// another value in one of the stores. It returns the name used.
+func (s *httpClientService) storeCredential(name, value string) (string, error) {
+	base := name
+	for i := 2; ; i++ {
+		existing, err := s.lookupSecret(name)
+		if errors.Is(err, domain.ErrSecretNotFound) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if existing == value {
+			return name, nil
+		}
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+	return name, s.SaveSecret(name, value)
+}
+
+func (s *httpClientService) DeleteSecret(name string) error {
+	deleted, err := s.requestRepo.Queries.DeleteSecret(context.Background(), name)
+	if err != nil {
+		return fmt.Errorf("could not delete secret from database: %v", err)
+	}
+	if deleted == 0 {
+		return fmt.Errorf("secret %q not found in the database", name)
+	}
+	return nil
+}
+
+// GetSecrets lists the secrets of every store by name. A name found in
+// several stores is reported with the store it is resolved from.
+func (s *httpClientService) GetSecrets() ([]*domain.Secret, error) {
+	sources := make(map[string]string)
+	envKeys := make(map[string]bool)
+	add := func(name, source string) {
+		if _, ok := sources[name]; ok {
+			return
+		}
+		if _, ok := s.envSecret(name); ok {
+			source = domain.SecretSourceEnv
+			envKeys[s.secretEnvKey(name)] = true
+		}
+		sources[name] = source
+	}
+
+	fileSecrets, err := readSecretsFile(s.secretsCfg.File)
+	if err != nil {
+		return nil, err
+	}
+	for name := range fileSecrets {
+		add(name, domain.SecretSourceFile)
+	}
+
+	names, err := s.requestRepo.Queries.ListSecretNames(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve secrets from database: %w", err)
+	}
+	for _, name := range names {
+		add(name, domain.SecretSourceDatabase)
+	}
+
+	// variables not overriding a stored name are listed by their lower cased
+	// name, which is how they are referenced
+	if s.secretsCfg.EnvPrefix != "" {
+		for _, entry := range os.Environ() {
+			key, _, _ := strings.Cut(entry, "=")
+			name, ok := strings.CutPrefix(key, s.secretsCfg.EnvPrefix)
+			if ok && name != "" && !envKeys[key] {
+				add(strings.ToLower(name), domain.SecretSourceEnv)
+			}
+		}
+	}
+
+	secrets := make([]*domain.Secret, 0, len(sources))
+	for name, source := range sources {
+		secrets = append(secrets, &domain.Secret{Name: name, Source: source})
+	}
+	slices.SortFunc(secrets, func(a, b *domain.Secret) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return secrets, nil
+}
+
+func (s *httpClientService) envSecret(name string) (string, bool) {
+	if s.secretsCfg.EnvPrefix == "" {
+		return "", false
+	}
+	return os.LookupEnv(s.secretEnvKey(name))
+}
+
+// secretEnvKey names the variable of a secret, BURROW_SECRET_API_KEY for
+// api-key with the default prefix.
+func (s *httpClientService) secretEnvKey(name string) string {
+	return s.secretsCfg.EnvPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+func (s *httpClientService) passphrase() (string, error) {
+	passphrase := os.Getenv(s.secretsCfg.PassphraseEnv)
+	if s.secretsCfg.PassphraseEnv == "" || passphrase == "" {
+		return "", fmt.Errorf("set %s to unlock the secret store", s.secretsCfg.PassphraseEnv)
+	}
+	return passphrase, nil
+}
+
+func (s *httpClientService) decryptSecret(row database.Secret) (string, error) {
+	passphrase, err := s.passphrase()
+	if err != nil {
+		return "", err
+	}
+	gcm, err := s.secretCipher(passphrase, row.Salt)
+	if err != nil {
+		return "", err
+	}
+	value, err := gcm.Open(nil, row.Nonce, row.Ciphertext, []byte(row.Name))
+	if err != nil {
+		return "", errors.New("wrong passphrase or corrupted secret")
+	}
+	return string(value), nil
+}
+
+// secretCipher derives the key for salt, remembering it as the derivation is
+// deliberately slow.
+func (s *httpClientService) secretCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	id := secretKeyID{salt: string(salt), passphrase: passphrase}
+
+	s.secretKeyMu.Lock()
+	key, ok := s.secretKeys[id]
+	s.secretKeyMu.Unlock()
+
+	if !ok {
+		var err error
+		key, err = pbkdf2.Key(sha256.New, passphrase, salt, secretKeyIterations, 32)
+		if err != nil {
+			return nil, err
+		}
+
+		s.secretKeyMu.Lock()
+		if s.secretKeys == nil {
+			s.secretKeys = make(map[secretKeyID][]byte)
+		}
+		s.secretKeys[id] = key
+		s.secretKeyMu.Unlock()
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// readSecretsFile reads NAME=value lines, skipping blank lines and # comments.
+// A missing file has no secrets.
+func readSecretsFile(path string) (map[string]string, error) {
+	secrets := make(map[string]string)
+	if path == "" {
+		return secrets, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return secrets, nil
+		}
+		return nil, fmt.Errorf("could not read secrets file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("secrets file line %d: expected NAME=value", lineNo)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		secrets[strings.TrimSpace(name)] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read secrets file: %w", err)
+	}
+	return secrets, nil
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSecretsTestService(t *testing.T) *httpClientService {
+	t.Helper()
+
+	iterations := secretKeyIterations
+	secretKeyIterations = 1000
+	t.Cleanup(func() { secretKeyIterations = iterations })
+
+	s := newHistoryTestService(t, config.HistoryConfig{})
+	s.httpCfg = defaultHTTPConfig()
+	s.secretsCfg = config.SecretsConfig{
+		File:          filepath.Join(t.TempDir(), "secrets.env"),
+		EnvPrefix:     "BURROW_TEST_SECRET_",
+		PassphraseEnv: "BURROW_TEST_PASSPHRASE",
+	}
+	return s
+}
+
+func TestSecretStoreEncryptsAtRest(t *testing.T) {
+	s := newSecretsTestService(t)
+
+	t.Setenv("BURROW_TEST_PASSPHRASE", "")
+	assert.ErrorContains(t, s.SaveSecret("token", "s3cret"), "set BURROW_TEST_PASSPHRASE")
+
+	t.Setenv("BURROW_TEST_PASSPHRASE", "correct horse")
+	assert.ErrorContains(t, s.SaveSecret("bad name", "s3cret"), "invalid secret name")
+	require.NoError(t, s.SaveSecret("token", "s3cret"))
+
+	row, err := s.requestRepo.Queries.GetSecret(context.Background(), "token")
+	require.NoError(t, err)
+	assert.NotContains(t, string(row.Ciphertext), "s3cret")
+
+	val, err := s.lookupSecret("token")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", val)
+
+	t.Setenv("BURROW_TEST_PASSPHRASE", "wrong")
+	_, err = s.lookupSecret("token")
+	assert.ErrorContains(t, err, "wrong passphrase")
+
+	require.NoError(t, s.DeleteSecret("token"))
+	_, err = s.lookupSecret("token")
+	assert.ErrorIs(t, err, domain.ErrSecretNotFound)
+	assert.ErrorContains(t, s.DeleteSecret("token"), "not found")
+}
+
+func TestLookupSecretOrder(t *testing.T) {
+	s := newSecretsTestService(t)
+	t.Setenv("BURROW_TEST_PASSPHRASE", "pass")
+	require.NoError(t, s.SaveSecret("api-key", "from-db"))
+	require.NoError(t, s.SaveSecret("db-only", "db"))
+	require.NoError(t, os.WriteFile(s.secretsCfg.File, []byte("# team secrets\napi-key = \"from-file\"\nfile-only=file\n"), 0600))
+	t.Setenv("BURROW_TEST_SECRET_API_KEY", "from-env")
+
+	val, err := s.lookupSecret("api-key")
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", val)
+
+	val, err = s.lookupSecret("file-only")
+	require.NoError(t, err)
+	assert.Equal(t, "file", val)
+
+	secrets, err := s.GetSecrets()
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Secret{
+		{Name: "api-key", Source: domain.SecretSourceEnv},
+		{Name: "db-only", Source: domain.SecretSourceDatabase},
+		{Name: "file-only", Source: domain.SecretSourceFile},
+	}, secrets)
+}
+
+func TestReadSecretsFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected map[string]string
+		wantErr  bool
+	}{
+		{name: "Comments and quotes", content: "# comment\n\nA=1\nB = 'two words'\nC=\"x=y\"\n", expected: map[string]string{"A": "1", "B": "two words", "C": "x=y"}},
+		{name: "Missing separator", content: "A\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "secrets.env")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0600))
+
+			secrets, err := readSecretsFile(path)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, secrets)
+		})
+	}
+
+	secrets, err := readSecretsFile(filepath.Join(t.TempDir(), "missing.env"))
+	require.NoError(t, err)
+	assert.Empty(t, secrets)
+}
+
+func TestSendRequestResolvesSecrets(t *testing.T) {
+	var gotHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	s := newSecretsTestService(t)
+	t.Setenv("BURROW_TEST_PASSPHRASE", "pass")
+	require.NoError(t, s.SaveSecret("token", "s3cret"))
+	s.activeEnv = &domain.Environment{Name: "dev", Variables: map[string]string{"auth": "Bearer {{secret.token}}"}}
+
+	req := &domain.Request{Method: "GET", URL: server.URL, Headers: map[string]string{"Authorization": "{{auth}}"}}
+	_, err := s.SendRequest(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer s3cret", gotHeader)
+
+	entries, err := s.GetHistory("", 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "{{auth}}", entries[0].Request.Headers["Authorization"])
+
+	gotHeader = ""
+	missing := &domain.Request{Method: "GET", URL: server.URL, Auth: &domain.Auth{Type: domain.AuthBearer, Token: "{{secret.missing}}"}}
+	_, err = s.SendRequest(context.Background(), missing)
+	assert.ErrorIs(t, err, domain.ErrSecretNotFound)
+	assert.ErrorContains(t, err, `secret "missing"`)
+	assert.Empty(t, gotHeader)
+}
+
+func TestSaveRequestMovesCredentials(t *testing.T) {
+	s := newSecretsTestService(t)
+	newReq := func() *domain.Request {
+		return &domain.Request{
+			Name:    "charge",
+			Method:  "POST",
+			URL:     "http://localhost",
+			Headers: map[string]string{"Authorization": "Bearer abc123"},
+			Auth:    &domain.Auth{Type: domain.AuthBasic, Username: "me", Password: "hunter2"},
+		}
+	}
+
+	t.Setenv("BURROW_TEST_PASSPHRASE", "")
+	err := s.SaveRequest(newReq())
+	assert.ErrorContains(t, err, "plaintext Authorization header, auth password not saved: set BURROW_TEST_PASSPHRASE")
+	_, err = s.GetRequest("charge")
+	assert.Error(t, err)
+
+	t.Setenv("BURROW_TEST_PASSPHRASE", "pass")
+	// the name is taken by another value, so the token gets a name of its own
+	t.Setenv("BURROW_TEST_SECRET_CHARGE_AUTHORIZATION", "other")
+	req := newReq()
+	require.NoError(t, s.SaveRequest(req))
+	assert.Equal(t, "Bearer {{secret.charge-authorization-2}}", req.Headers["Authorization"])
+	assert.Equal(t, "{{secret.charge-auth-password}}", req.Auth.Password)
+
+	row, err := s.requestRepo.Queries.GetRequest(context.Background(), "charge")
+	require.NoError(t, err)
+	saved, ok := row.RequestJson.([]byte)
+	require.True(t, ok)
+	assert.Contains(t, string(saved), "charge-auth-password")
+	assert.NotContains(t, string(saved), "abc123")
+	assert.NotContains(t, string(saved), "hunter2")
+
+	token, err := s.lookupSecret("charge-authorization-2")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", token)
+
+	// saving the same credential again reuses its secret
+	require.NoError(t, s.DeleteRequest("charge"))
+	req = newReq()
+	require.NoError(t, s.SaveRequest(req))
+	assert.Equal(t, "{{secret.charge-auth-password}}", req.Auth.Password)
+}
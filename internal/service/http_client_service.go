@@ -2,25 +2,50 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/http/httptrace"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/ManoloEsS/burrow/internal/config"
 	"github.com/ManoloEsS/burrow/internal/database"
 	"github.com/ManoloEsS/burrow/internal/domain"
 )
 
 type httpClientService struct {
 	requestRepo *database.Database
+	historyCfg  config.HistoryConfig
+	httpCfg     config.HTTPConfig
+	responseCfg config.ResponseConfig
+	secretsCfg  config.SecretsConfig
+	envMu       sync.RWMutex
+	activeEnv   *domain.Environment
+
+	transportMu sync.Mutex
+	transports  map[config.HTTPConfig]*http.Transport
+
+	tokenMu sync.Mutex
+	tokens  map[domain.Auth]oauth2Token
+
+	secretKeyMu sync.Mutex
+	secretKeys  map[secretKeyID][]byte
 }
 
-func NewHttpClientService(requestRepo *database.Database) HttpClientService {
+func NewHttpClientService(requestRepo *database.Database, cfg *config.Config) HttpClientService {
 	return &httpClientService{
 		requestRepo: requestRepo,
+		historyCfg:  cfg.History,
+		httpCfg:     cfg.HTTP,
+		responseCfg: cfg.Response,
+		secretsCfg:  cfg.Secrets,
 	}
 }
 
@@ -33,7 +58,22 @@ func (s *httpClientService) DeleteRequest(reqName string) error {
 	return nil
 }
 
-func (s *httpClientService) SendRequest(req *domain.Request) (*domain.Response, error) {
+// SendRequest sends req, cancelling ctx aborts it at any point, including
+// while the body is being read.
+func (s *httpClientService) SendRequest(ctx context.Context, req *domain.Request) (*domain.Response, error) {
+	return s.sendAndRecord(ctx, req, nil)
+}
+
+// StreamRequest sends req and hands the response body to onEvent as it
+// arrives, as parsed events for an event stream or as chunks otherwise. The
+// configured timeout only bounds the wait for the response headers. It
+// returns when the server ends the stream or ctx is cancelled, which stops
+// the stream without an error once it has started.
+func (s *httpClientService) StreamRequest(ctx context.Context, req *domain.Request, onEvent func(domain.Event)) (*domain.Response, error) {
+	return s.sendAndRecord(ctx, req, onEvent)
+}
+
+func (s *httpClientService) sendAndRecord(ctx context.Context, req *domain.Request, onEvent func(domain.Event)) (*domain.Response, error) {
 	if req.Headers == nil {
 		req.Headers = make(map[string]string)
 	}
@@ -44,46 +84,162 @@ func (s *httpClientService) SendRequest(req *domain.Request) (*domain.Response,
 		req.Params = make(map[string]string)
 	}
 
-	newHttpReq, err := reqStructToHttpReq(req)
-	if err != nil {
+	lookup := &domain.SecretLookup{Variables: s.GetActiveEnvironment().Lookup, Secrets: s.lookupSecret}
+	resolved := req.WithVariables(lookup.Lookup)
+
+	sentAt := time.Now()
+	resp, err := &domain.Response{}, lookup.Err
+	if err == nil {
+		resp, err = s.send(ctx, resolved, onEvent)
+	}
+
+	// history keeps the unresolved request so environment values and secrets
+	// are not stored
+	s.recordHistory(req, resp, err, sentAt)
+
+	return resp, err
+}
+
+func (s *httpClientService) send(ctx context.Context, req *domain.Request, onEvent func(domain.Event)) (*domain.Response, error) {
+	if err := req.Auth.Validate(); err != nil {
 		return &domain.Response{}, err
 	}
 
-	client := &http.Client{
-		Timeout: time.Second * 5,
+	graphQL := req.GraphQL != nil
+	req, err := req.WithGraphQLBody()
+	if err != nil {
+		return &domain.Response{}, err
 	}
 
-	start := time.Now()
+	cfg := req.Options.Apply(s.httpCfg)
+	headersReceived := func() {}
+	if onEvent != nil {
+		// a stream stays open as long as the server keeps sending, so the
+		// timeout only covers the wait for the headers
+		var cancel func()
+		ctx, headersReceived, cancel = withHeaderTimeout(ctx, cfg.Timeout)
+		defer cancel()
+		cfg.Timeout = 0
+	}
 
-	httpResp, err := client.Do(newHttpReq)
+	client, err := s.httpClient(cfg)
 	if err != nil {
 		return &domain.Response{}, err
 	}
-	defer func() { _ = httpResp.Body.Close() }()
 
-	responseTime := time.Since(start)
+	oauth2Auth := req.Auth
+	if req.Auth != nil && req.Auth.Type == domain.AuthOAuth2 {
+		token, err := s.oauth2Token(ctx, client, *req.Auth)
+		if err != nil {
+			return &domain.Response{}, fmt.Errorf("could not get oauth2 token: %w", err)
+		}
+		req = req.Clone()
+		req.Auth = &domain.Auth{Type: domain.AuthBearer, Token: token}
+	}
+
+	start := time.Now()
+	httpResp, tracer, err := doTraced(ctx, client, req, start, "")
+	if err != nil {
+		return &domain.Response{}, headerTimeoutCause(ctx, err)
+	}
+
+	if httpResp.StatusCode == http.StatusUnauthorized && req.Auth != nil {
+		switch {
+		case req.Auth.Type == domain.AuthDigest:
+			authorization, err := digestAuthorization(httpResp, req)
+			if err != nil {
+				_ = httpResp.Body.Close()
+				return &domain.Response{}, err
+			}
+			if authorization != "" {
+				_, _ = io.Copy(io.Discard, httpResp.Body)
+				_ = httpResp.Body.Close()
+
+				httpResp, tracer, err = doTraced(ctx, client, req, start, authorization)
+				if err != nil {
+					return &domain.Response{}, headerTimeoutCause(ctx, err)
+				}
+			}
+		case oauth2Auth.Type == domain.AuthOAuth2:
+			// the server rejected the cached token, fetch a new one next time
+			s.forgetOAuth2Token(*oauth2Auth)
+		}
+	}
+	defer func() { _ = httpResp.Body.Close() }()
+	headersReceived()
 
 	newResp := &domain.Response{}
 
-	err = newResp.BuildResponse(httpResp)
-	if err != nil {
-		return &domain.Response{}, err
+	if onEvent != nil {
+		err = newResp.BuildStream(httpResp, s.responseCfg.MaxBodyBytes(), onEvent)
+		// cancelling ctx is how a stream is stopped
+		if err != nil && ctx.Err() == nil {
+			return &domain.Response{}, err
+		}
+	} else {
+		err = newResp.BuildResponse(httpResp, domain.BodyOptions{
+			MaxMemory: s.responseCfg.MaxBodyBytes(),
+			Progress:  progressFromContext(ctx),
+		})
+		if err != nil {
+			return &domain.Response{}, err
+		}
 	}
 
-	newResp.ResponseTime = responseTime
+	// the body has been read, so the response time covers the transfer too
+	end := time.Now()
+	newResp.ResponseTime = end.Sub(start)
+	newResp.Timing = tracer.timing(end)
+	if graphQL {
+		newResp.GraphQL = domain.ParseGraphQLResult(newResp.Body)
+	}
 
 	return newResp, nil
 }
 
+// doTraced sends req with a trace measuring its phases from start. A non
+// empty authorization replaces the Authorization header.
+func doTraced(ctx context.Context, client *http.Client, req *domain.Request, start time.Time, authorization string) (*http.Response, *timingTracer, error) {
+	httpReq, err := reqStructToHttpReq(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if authorization != "" {
+		httpReq.Header.Set("Authorization", authorization)
+	}
+
+	tracer := newTimingTracer(start)
+	httpReq = httpReq.WithContext(httptrace.WithClientTrace(httpReq.Context(), tracer.clientTrace()))
+
+	httpResp, err := client.Do(httpReq)
+	return httpResp, tracer, err
+}
+
+// SaveRequest stores req. Credentials written out in it are moved to the
+// encrypted secret store first and req is left with references to them, so
+// the saved request never holds a credential.
 func (s *httpClientService) SaveRequest(req *domain.Request) error {
+	if plaintext := req.PlaintextCredentials(); len(plaintext) > 0 {
+		if err := req.CheckCredentialVariables(); err != nil {
+			return err
+		}
+		if _, err := s.passphrase(); err != nil {
+			return fmt.Errorf("plaintext %s not saved: %v, or use {{secret.NAME}}", strings.Join(plaintext, ", "), err)
+		}
+		if err := req.MoveCredentials(s.storeCredential); err != nil {
+			return err
+		}
+	}
+
 	jsonData, err := json.Marshal(req)
 	if err != nil {
 		return fmt.Errorf("could not marshal request: %v", err)
 	}
 
 	requestParams := database.CreateRequestParams{
-		Name:        req.Name,
-		RequestJson: jsonData,
+		Name:         req.Name,
+		RequestJson:  jsonData,
+		CollectionID: nullCollectionID(req.CollectionID),
 	}
 	_, err = s.requestRepo.Queries.CreateRequest(context.Background(), requestParams)
 	if err != nil {
@@ -102,7 +258,7 @@ func (s *httpClientService) GetSavedRequests() ([]*domain.Request, error) {
 	var reqs []*domain.Request
 
 	for _, r := range reqsJSON {
-		request, err := requestJSONToStruct(r.RequestJson)
+		request, err := requestRowToStruct(r)
 		if err != nil {
 			log.Printf("could not parse request %s: %v", r.Name, err)
 			continue
@@ -112,6 +268,27 @@ func (s *httpClientService) GetSavedRequests() ([]*domain.Request, error) {
 	return reqs, nil
 }
 
+func (s *httpClientService) GetRequest(reqName string) (*domain.Request, error) {
+	reqJSON, err := s.requestRepo.Queries.GetRequest(context.Background(), reqName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("request %q not found", reqName)
+		}
+		return nil, fmt.Errorf("could not retrieve request from database: %w", err)
+	}
+
+	return requestRowToStruct(reqJSON)
+}
+
+func requestRowToStruct(row database.RequestBlob) (*domain.Request, error) {
+	req, err := requestJSONToStruct(row.RequestJson)
+	if err != nil {
+		return nil, err
+	}
+	req.CollectionID = row.CollectionID.Int64
+	return req, nil
+}
+
 func requestJSONToStruct(jsonData interface{}) (*domain.Request, error) {
 	jsonByte, ok := jsonData.([]byte)
 	if !ok {
@@ -127,31 +304,51 @@ func requestJSONToStruct(jsonData interface{}) (*domain.Request, error) {
 	return &req, nil
 }
 
-func addParams(params map[string]string, url string) string {
+func addParams(params map[string]string, rawURL string) string {
 	if len(params) == 0 {
-		return url
-	}
-	var paramsSlice []string
-	if len(params) != 0 {
-		for k, v := range params {
-			paramsSlice = append(paramsSlice, k+"="+v)
-		}
+		return rawURL
 	}
 
-	formattedParams := strings.Join(paramsSlice, "&")
-	return url + "?" + formattedParams
+	query := url.Values{}
+	for k, v := range params {
+		query.Set(k, v)
+	}
 
+	separator := "?"
+	if strings.Contains(rawURL, "?") {
+		separator = "&"
+	}
+	// Encode sorts the keys so the same request always produces the same url
+	return rawURL + separator + query.Encode()
 }
 
-func reqStructToHttpReq(req *domain.Request) (*http.Request, error) {
+func reqStructToHttpReq(ctx context.Context, req *domain.Request) (*http.Request, error) {
 	var bodyReader io.Reader
-	if req.Body != "" {
+	var streamed *requestBody
+	var err error
+	switch {
+	case req.BodyFile != "":
+		streamed, err = newFileBody(req.BodyFile, req.ContentType["Content-Type"])
+	case len(req.Form) > 0:
+		streamed, err = newFormBody(req)
+	case req.Body != "":
 		bodyReader = strings.NewReader(req.Body)
 	}
+	if err != nil {
+		return nil, err
+	}
+	if streamed != nil {
+		if bodyReader, err = streamed.open(); err != nil {
+			return nil, err
+		}
+	}
 	urlWithParams := addParams(req.Params, req.URL)
 
-	httpRequest, err := http.NewRequestWithContext(context.Background(), req.Method, urlWithParams, bodyReader)
+	httpRequest, err := http.NewRequestWithContext(ctx, req.Method, urlWithParams, bodyReader)
 	if err != nil {
+		if closer, ok := bodyReader.(io.Closer); ok {
+			_ = closer.Close()
+		}
 		return nil, err
 	}
 
@@ -159,11 +356,18 @@ func reqStructToHttpReq(req *domain.Request) (*http.Request, error) {
 		httpRequest.Header.Add(key, val)
 	}
 
-	if req.Body == "" {
+	switch {
+	case streamed != nil:
+		httpRequest.ContentLength = streamed.length
+		httpRequest.GetBody = streamed.open
+		httpRequest.Header.Add("Content-Type", streamed.contentType)
+	case req.Body == "":
 		httpRequest.Header.Add("Content-Type", "none/none")
-	} else {
+	default:
 		httpRequest.Header.Add("Content-Type", req.ContentType["Content-Type"])
 	}
 
+	req.Auth.Apply(httpRequest)
+
 	return httpRequest, nil
 }
@@ -1,12 +1,37 @@
 package service
 
 import (
+	"context"
+	"net/url"
+	"path/filepath"
 	"testing"
 
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/ManoloEsS/burrow/internal/database"
 	"github.com/ManoloEsS/burrow/internal/domain"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// newTestService is a service on a fresh database that enforces foreign keys
+// like the one Burrow opens.
+func newTestService(t *testing.T) *httpClientService {
+	t.Helper()
+	return &httpClientService{requestRepo: openTestDatabase(t, "?_foreign_keys=on")}
+}
+
+// openTestDatabase opens a fresh database with the connection options of
+// query.
+func openTestDatabase(t *testing.T, query string) *database.Database {
+	t.Helper()
+
+	dbPath := filepath.Join(t.TempDir(), "burrow.db")
+	db, err := database.NewDatabase(dbPath, "file:"+dbPath+query)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
 func TestRequestJSONToStruct(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -63,6 +88,18 @@ func TestAddParams(t *testing.T) {
 			url:            "http://example.com",
 			expectedResult: "http://example.com?param1=value1&param2=value2",
 		},
+		{
+			name:           "Reserved characters are escaped",
+			params:         map[string]string{"q": "a&b=c d", "path": "x/y?z"},
+			url:            "http://example.com",
+			expectedResult: "http://example.com?path=x%2Fy%3Fz&q=a%26b%3Dc+d",
+		},
+		{
+			name:           "URL with a query",
+			params:         map[string]string{"page": "2"},
+			url:            "http://example.com?tag=a&tag=b",
+			expectedResult: "http://example.com?tag=a&tag=b&page=2",
+		},
 		{
 			name:           "Empty parameters",
 			params:         map[string]string{},
@@ -87,6 +124,44 @@ func TestAddParams(t *testing.T) {
 	}
 }
 
+func TestCurlQueryRoundTrip(t *testing.T) {
+	cfg := &config.Config{App: config.AppConfig{DefaultPort: "8080"}}
+	tests := []struct {
+		name    string
+		command string
+		query   url.Values
+	}{
+		{
+			name:    "encoded query and url encoded -G data",
+			command: "curl -G 'https://example.com/search?path=a%2Fb%26c&lang=en' --data-urlencode 'q=go & rust=1'",
+			query:   url.Values{"path": {"a/b&c"}, "lang": {"en"}, "q": {"go & rust=1"}},
+		},
+		{
+			name:    "repeated keys",
+			command: "curl -G 'https://example.com/search?tag=a&tag=b%2Fc' -d page=2 -d page=3",
+			query:   url.Values{"tag": {"a", "b/c"}, "page": {"2", "3"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := domain.ParseCurl(tt.command, cfg)
+			require.NoError(t, err)
+
+			httpReq, err := reqStructToHttpReq(context.Background(), req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.query, httpReq.URL.Query())
+
+			// copied back as curl it still sends the same query
+			copied, err := domain.ParseCurl(req.ToCurl(), cfg)
+			require.NoError(t, err)
+			httpReq, err = reqStructToHttpReq(context.Background(), copied)
+			require.NoError(t, err)
+			assert.Equal(t, tt.query, httpReq.URL.Query())
+		})
+	}
+}
+
 func TestReqStructToHttpReq(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -125,7 +200,7 @@ func TestReqStructToHttpReq(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			httpReq, err := reqStructToHttpReq(tt.request)
+			httpReq, err := reqStructToHttpReq(context.Background(), tt.request)
 
 			if tt.expectError {
 				assert.Error(t, err)
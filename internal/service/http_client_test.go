@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func defaultHTTPConfig() config.HTTPConfig {
+	return config.HTTPConfig{Timeout: 5 * time.Second, FollowRedirects: true, MaxRedirects: 10}
+}
+
+func writeCertPEM(t *testing.T, cert *x509.Certificate) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path
+}
+
+func TestSendRequestRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusFound)
+		case "/b":
+			http.Redirect(w, r, "/c", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	off, two := false, 2
+	one := 1
+
+	tests := []struct {
+		name       string
+		cfg        func(*config.HTTPConfig)
+		options    *domain.ClientOptions
+		wantStatus int
+		wantErr    string
+	}{
+		{name: "follows by default", wantStatus: http.StatusOK},
+		{name: "disabled in config", cfg: func(c *config.HTTPConfig) { c.FollowRedirects = false }, wantStatus: http.StatusFound},
+		{name: "disabled per request", options: &domain.ClientOptions{FollowRedirects: &off}, wantStatus: http.StatusFound},
+		{name: "within max", options: &domain.ClientOptions{MaxRedirects: &two}, wantStatus: http.StatusOK},
+		{name: "over max", options: &domain.ClientOptions{MaxRedirects: &one}, wantErr: "stopped after 1 redirects"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultHTTPConfig()
+			if tt.cfg != nil {
+				tt.cfg(&cfg)
+			}
+			s := &httpClientService{httpCfg: cfg}
+
+			resp, err := s.SendRequest(context.Background(), &domain.Request{Method: "GET", URL: server.URL + "/a", Options: tt.options})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func TestSendRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	s := &httpClientService{httpCfg: defaultHTTPConfig()}
+	req := &domain.Request{Method: "GET", URL: server.URL, Options: &domain.ClientOptions{Timeout: 20 * time.Millisecond}}
+
+	_, err := s.SendRequest(context.Background(), req)
+	assert.ErrorContains(t, err, "Client.Timeout")
+}
+
+func TestSendRequestCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/body" {
+			w.(http.Flusher).Flush()
+		}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	for _, path := range []string{"/headers", "/body"} {
+		t.Run(path, func(t *testing.T) {
+			s := &httpClientService{httpCfg: defaultHTTPConfig()}
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+
+			start := time.Now()
+			_, err := s.SendRequest(ctx, &domain.Request{Method: "GET", URL: server.URL + path})
+			assert.ErrorIs(t, err, context.Canceled)
+			assert.Less(t, time.Since(start), time.Second)
+		})
+	}
+}
+
+func TestSendRequestTLSOptions(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	insecure := true
+
+	tests := []struct {
+		name      string
+		cfg       func(*config.HTTPConfig)
+		options   *domain.ClientOptions
+		wantProto string
+		wantErr   string
+	}{
+		{name: "untrusted certificate", wantErr: "certificate"},
+		{name: "insecure", options: &domain.ClientOptions{Insecure: &insecure}, wantProto: "HTTP/2.0"},
+		{name: "custom CA", cfg: func(c *config.HTTPConfig) { c.CACert = writeCertPEM(t, server.Certificate()) }, wantProto: "HTTP/2.0"},
+		{name: "forced http 1.1", cfg: func(c *config.HTTPConfig) { c.Insecure, c.HTTPVersion = true, "1.1" }, wantProto: "HTTP/1.1"},
+		{name: "missing CA bundle", cfg: func(c *config.HTTPConfig) { c.CACert = "/does/not/exist.pem" }, wantErr: "could not read CA bundle"},
+		{name: "missing client certificate", options: &domain.ClientOptions{ClientCert: "c.pem", ClientKey: "k.pem"}, wantErr: "could not load client certificate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultHTTPConfig()
+			if tt.cfg != nil {
+				tt.cfg(&cfg)
+			}
+			s := &httpClientService{httpCfg: cfg}
+
+			resp, err := s.SendRequest(context.Background(), &domain.Request{Method: "GET", URL: server.URL, Options: tt.options})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantProto, resp.Proto)
+		})
+	}
+}
+
+func TestSendRequestClientCertificate(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	// the test server certificate doubles as the client certificate
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+	serverCert := server.TLS.Certificates[0]
+	keyDER, err := x509.MarshalPKCS8PrivateKey(serverCert.PrivateKey)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: serverCert.Certificate[0]}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	cfg := defaultHTTPConfig()
+	cfg.Insecure = true
+	s := &httpClientService{httpCfg: cfg}
+
+	resp, err := s.SendRequest(context.Background(), &domain.Request{Method: "GET", URL: server.URL})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req := &domain.Request{Method: "GET", URL: server.URL, Options: &domain.ClientOptions{ClientCert: certFile, ClientKey: keyFile}}
+	resp, err = s.SendRequest(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestSendRequestProxy(t *testing.T) {
+	var proxiedHost string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedHost = r.URL.Host
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer proxy.Close()
+
+	cfg := defaultHTTPConfig()
+	cfg.Proxy = proxy.URL
+	s := &httpClientService{httpCfg: cfg}
+
+	resp, err := s.SendRequest(context.Background(), &domain.Request{Method: "GET", URL: "http://example.invalid/users"})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+	assert.Equal(t, "example.invalid", proxiedHost)
+
+	_, err = s.SendRequest(context.Background(), &domain.Request{Method: "GET", URL: "http://example.invalid", Options: &domain.ClientOptions{Proxy: "::bad"}})
+	assert.ErrorContains(t, err, "invalid proxy")
+}
+
+func TestHTTPClientReusesTransport(t *testing.T) {
+	s := &httpClientService{}
+	cfg := defaultHTTPConfig()
+
+	first, err := s.httpClient(cfg)
+	require.NoError(t, err)
+
+	// a different timeout shares the connection pool
+	cfg.Timeout = time.Second
+	second, err := s.httpClient(cfg)
+	require.NoError(t, err)
+	assert.Same(t, first.Transport, second.Transport)
+
+	cfg.Insecure = true
+	third, err := s.httpClient(cfg)
+	require.NoError(t, err)
+	assert.NotSame(t, first.Transport, third.Transport)
+}
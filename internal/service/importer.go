@@ -0,0 +1,41 @@
+package service
+
+import (
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/ManoloEsS/burrow/internal/importer"
+)
+
+// ImportRequests saves every converted request through the http service.
+// Requests whose name is already saved, or that fail to save, are moved to
+// the skipped list so the result only holds what was actually imported.
+func ImportRequests(httpService HttpClientService, result *importer.Result) error {
+	existing, err := httpService.GetSavedRequests()
+	if err != nil {
+		return err
+	}
+
+	taken := make(map[string]bool, len(existing))
+	for _, req := range existing {
+		taken[req.Name] = true
+	}
+
+	saved := make([]*domain.Request, 0, len(result.Requests))
+	for _, req := range result.Requests {
+		if taken[req.Name] {
+			result.Skipped = append(result.Skipped, importer.Skipped{Name: req.Name, Reason: "a saved request with this name already exists"})
+			continue
+		}
+
+		// collections name their credentials by variable, the values are
+		// expected in the secret store
+		req.ReferenceCredentialsAsSecrets()
+		if err := httpService.SaveRequest(req); err != nil {
+			result.Skipped = append(result.Skipped, importer.Skipped{Name: req.Name, Reason: err.Error()})
+			continue
+		}
+		saved = append(saved, req)
+	}
+
+	result.Requests = saved
+	return nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/ManoloEsS/burrow/internal/importer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type saveRecorder struct {
+	HttpClientService
+	existing []*domain.Request
+	saved    []string
+}
+
+func (s *saveRecorder) GetSavedRequests() ([]*domain.Request, error) {
+	return s.existing, nil
+}
+
+func (s *saveRecorder) SaveRequest(req *domain.Request) error {
+	if req.Name == "broken" {
+		return errors.New("could not save request: disk full")
+	}
+	s.saved = append(s.saved, req.Name)
+	return nil
+}
+
+func TestImportRequests(t *testing.T) {
+	recorder := &saveRecorder{existing: []*domain.Request{{Name: "health"}}}
+	result := &importer.Result{
+		Format:   importer.FormatPostman,
+		Requests: []*domain.Request{{Name: "health"}, {Name: "users"}, {Name: "broken"}},
+		Skipped:  []importer.Skipped{{Name: "upload", Reason: "unsupported body mode \"formdata\""}},
+	}
+
+	require.NoError(t, ImportRequests(recorder, result))
+
+	assert.Equal(t, []string{"users"}, recorder.saved)
+	require.Len(t, result.Requests, 1)
+	assert.Equal(t, "users", result.Requests[0].Name)
+
+	require.Len(t, result.Skipped, 3)
+	assert.Equal(t, "health", result.Skipped[1].Name)
+	assert.Contains(t, result.Skipped[1].Reason, "already exists")
+	assert.Equal(t, "broken", result.Skipped[2].Name)
+	assert.Contains(t, result.Skipped[2].Reason, "disk full")
+}
+
+func TestImportRequestsReferencesCredentialsAsSecrets(t *testing.T) {
+	s := newSecretsTestService(t)
+	result := &importer.Result{
+		Format: importer.FormatPostman,
+		Requests: []*domain.Request{{
+			Name:    "users",
+			Method:  "GET",
+			URL:     "{{base}}/users",
+			Headers: map[string]string{"Authorization": "Bearer {{token}}"},
+		}},
+	}
+
+	require.NoError(t, ImportRequests(s, result))
+	require.Empty(t, result.Skipped)
+
+	saved, err := s.GetRequest("users")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer {{secret.token}}", saved.Headers["Authorization"])
+	assert.Equal(t, "{{base}}/users", saved.URL)
+}
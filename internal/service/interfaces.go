@@ -1,17 +1,52 @@
 package service
 
 import (
+	"context"
+
+	"github.com/ManoloEsS/burrow/internal/config"
 	"github.com/ManoloEsS/burrow/internal/domain"
 )
 
 type HttpClientService interface {
-	SendRequest(*domain.Request) (*domain.Response, error)
+	SendRequest(context.Context, *domain.Request) (*domain.Response, error)
+	StreamRequest(ctx context.Context, req *domain.Request, onEvent func(domain.Event)) (*domain.Response, error)
+	ConnectWebSocket(ctx context.Context, req *domain.Request, outgoing <-chan domain.WSMessage, onFrame func(domain.WSFrame)) (*domain.Response, error)
+	ListGRPCMethods(context.Context, *domain.Request) ([]domain.GRPCMethod, error)
+	CallGRPC(ctx context.Context, req *domain.Request, onMessage func(domain.Event)) (*domain.Response, error)
+	IntrospectGraphQL(context.Context, *domain.Request) (*domain.GraphQLSchema, error)
 	SaveRequest(*domain.Request) error
 	DeleteRequest(string) error
 	GetSavedRequests() ([]*domain.Request, error)
+	GetRequest(string) (*domain.Request, error)
+	RenameRequest(oldName, newName string) error
+	DuplicateRequest(string) (*domain.Request, error)
+	MoveRequest(name string, collectionID int64) error
+	CreateCollection(name string, parentID int64) (*domain.Collection, error)
+	GetCollections() ([]*domain.Collection, error)
+	RenameCollection(id int64, name string) error
+	MoveCollection(id, parentID int64) error
+	DeleteCollection(int64) error
+	SaveEnvironment(*domain.Environment) error
+	DeleteEnvironment(string) error
+	GetEnvironments() ([]*domain.Environment, error)
+	SetActiveEnvironment(string) error
+	GetActiveEnvironment() *domain.Environment
+	SaveSecret(name, value string) error
+	DeleteSecret(string) error
+	GetSecrets() ([]*domain.Secret, error)
+	GetHistory(query string, limit int) ([]*domain.HistoryEntry, error)
+	GetHistoryEntry(int64) (*domain.HistoryEntry, error)
+	DeleteHistoryEntry(int64) error
+	ClearHistory() error
 }
 
 type ServerService interface {
-	StartServer(path string, port string, updateChan chan UIEvent) error
-	StopServer() error
+	StartServer(spec config.ServerConfig, updateChan chan UIEvent) error
+	StopServer(name string) error
+	StopAllServers() error
+	RemoveServer(name string) error
+	Servers() []ServerInfo
+	Logs(name string) []LogLine
+	LogsChanged() <-chan struct{}
+	RecentTargets() ([]config.ServerConfig, error)
 }
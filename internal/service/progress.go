@@ -0,0 +1,16 @@
+package service
+
+import "context"
+
+type progressKey struct{}
+
+// WithProgress returns a context reporting the download of the response body
+// to progress, with the bytes read so far and the expected size or -1.
+func WithProgress(ctx context.Context, progress func(read, total int64)) context.Context {
+	return context.WithValue(ctx, progressKey{}, progress)
+}
+
+func progressFromContext(ctx context.Context) func(read, total int64) {
+	progress, _ := ctx.Value(progressKey{}).(func(read, total int64))
+	return progress
+}
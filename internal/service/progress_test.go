@@ -0,0 +1,76 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendRequestLargeBody(t *testing.T) {
+	body := bytes.Repeat([]byte("burrow\n"), 3<<20/7)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	var read, total atomic.Int64
+	ctx := WithProgress(context.Background(), func(r, t int64) {
+		read.Store(r)
+		total.Store(t)
+	})
+
+	s := &httpClientService{httpCfg: defaultHTTPConfig(), responseCfg: config.ResponseConfig{MaxBodyMB: 1}}
+	resp, err := s.SendRequest(ctx, &domain.Request{Method: "GET", URL: server.URL})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.RemoveBodyFile() })
+
+	assert.True(t, resp.Truncated)
+	assert.Len(t, resp.Body, 1<<20)
+	assert.Equal(t, int64(len(body)), resp.BodySize)
+	assert.Equal(t, int64(len(body)), read.Load())
+	assert.Equal(t, int64(len(body)), total.Load())
+
+	saved := filepath.Join(t.TempDir(), "body.txt")
+	require.NoError(t, resp.SaveBody(saved))
+	data, err := os.ReadFile(saved)
+	require.NoError(t, err)
+	assert.Equal(t, body, data)
+}
+
+func TestSendRequestCancelEndlessStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chunk := bytes.Repeat([]byte("x"), 32<<10)
+		for r.Context().Err() == nil {
+			if _, err := w.Write(chunk); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = WithProgress(ctx, func(read, total int64) {
+		assert.Equal(t, int64(-1), total)
+		if read > 2<<20 {
+			cancel()
+		}
+	})
+
+	s := &httpClientService{httpCfg: defaultHTTPConfig(), responseCfg: config.ResponseConfig{MaxBodyMB: 1}}
+	_, err := s.SendRequest(ctx, &domain.Request{Method: "GET", URL: server.URL})
+	assert.ErrorIs(t, err, context.Canceled)
+}
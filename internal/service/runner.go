@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+)
+
+// RunSuite sends every request in order and evaluates its assertions,
+// collecting one test case per request. Cancelling ctx stops the run, the
+// result then only holds the cases that were started.
+func RunSuite(ctx context.Context, httpService HttpClientService, name string, reqs []*domain.Request) *domain.SuiteResult {
+	suite := &domain.SuiteResult{Name: name}
+	start := time.Now()
+
+	for _, req := range reqs {
+		if ctx.Err() != nil {
+			break
+		}
+		suite.Cases = append(suite.Cases, runCase(ctx, httpService, req))
+	}
+
+	suite.Duration = time.Since(start)
+	return suite
+}
+
+func runCase(ctx context.Context, httpService HttpClientService, req *domain.Request) *domain.TestCase {
+	tc := &domain.TestCase{Name: req.Name, Request: req}
+	start := time.Now()
+
+	resp, err := httpService.SendRequest(ctx, req)
+	tc.Duration = time.Since(start)
+	if err != nil {
+		tc.Error = err.Error()
+		return tc
+	}
+
+	tc.Response = resp
+	tc.Results = domain.EvaluateAssertions(req.Assertions, resp)
+	// the report only shows the part of a large body kept in memory
+	_ = resp.RemoveBodyFile()
+	return tc
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSuite(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	reqs := []*domain.Request{
+		{
+			Name:       "health",
+			Method:     "GET",
+			URL:        server.URL + "/health",
+			Assertions: []domain.Assertion{{Kind: domain.AssertJSON, Target: "$.status", Operator: domain.OpEquals, Expected: "ok"}},
+		},
+		{Name: "missing", Method: "GET", URL: server.URL + "/missing"},
+		{Name: "unreachable", Method: "GET", URL: "http://127.0.0.1:1"},
+	}
+
+	suite := RunSuite(context.Background(), &httpClientService{}, "smoke", reqs)
+
+	require.Len(t, suite.Cases, 3)
+	assert.Equal(t, "smoke", suite.Name)
+	assert.True(t, suite.Cases[0].Passed())
+	assert.Len(t, suite.Cases[0].Results, 1)
+	assert.False(t, suite.Cases[1].Passed())
+	assert.False(t, suite.Cases[2].Passed())
+	assert.NotEmpty(t, suite.Cases[2].Error)
+	assert.Equal(t, 1, suite.PassedCount())
+}
+
+func TestRunSuiteCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// cancel the run while the first request is in flight
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	reqs := []*domain.Request{
+		{Name: "first", Method: "GET", URL: server.URL},
+		{Name: "second", Method: "GET", URL: server.URL},
+	}
+
+	suite := RunSuite(ctx, &httpClientService{}, "smoke", reqs)
+
+	require.Len(t, suite.Cases, 1)
+	assert.Contains(t, suite.Cases[0].Error, "context canceled")
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxLogLineBytes splits lines longer than this, so a server writing
+// without newlines cannot grow a line forever.
+const maxLogLineBytes = 64 << 10
+
+// LogLevel is the severity a log line was written with, guessed from its
+// text.
+type LogLevel string
+
+const (
+	LogDebug LogLevel = "debug"
+	LogInfo  LogLevel = "info"
+	LogWarn  LogLevel = "warn"
+	LogError LogLevel = "error"
+)
+
+var logLevelPatterns = []struct {
+	level   LogLevel
+	pattern *regexp.Regexp
+}{
+	{LogError, regexp.MustCompile(`(?i)\b(panic|fatal|error|err|crit|critical)\b`)},
+	{LogWarn, regexp.MustCompile(`(?i)\b(warn|warning)\b`)},
+	{LogInfo, regexp.MustCompile(`(?i)\binfo\b`)},
+	{LogDebug, regexp.MustCompile(`(?i)\b(debug|trace)\b`)},
+}
+
+// ParseLogLevel guesses the level of a line from the level names it
+// contains, as written by log/slog, zap or logrus. It is empty for a line
+// naming none.
+func ParseLogLevel(text string) LogLevel {
+	for _, p := range logLevelPatterns {
+		if p.pattern.MatchString(text) {
+			return p.level
+		}
+	}
+	return ""
+}
+
+// LogLine is a line a server wrote.
+type LogLine struct {
+	Time time.Time
+	// Stream is "stdout" or "stderr"
+	Stream string
+	Text   string
+	Level  LogLevel
+}
+
+func (l LogLine) String() string {
+	return fmt.Sprintf("%s %s %s", l.Time.Format(time.RFC3339), l.Stream, l.Text)
+}
+
+// logBuffer keeps the last lines written by a server in a ring, and appends
+// them to a file while it is persisted.
+type logBuffer struct {
+	mu    sync.Mutex
+	lines []LogLine
+	// next is where the following line goes once the ring is full
+	next    int
+	partial map[string][]byte
+	file    *os.File
+	// onLine is told about every line added
+	onLine func(LogLine)
+}
+
+func newLogBuffer(size int, onLine func(LogLine)) *logBuffer {
+	return &logBuffer{
+		lines:   make([]LogLine, 0, max(size, 1)),
+		partial: make(map[string][]byte),
+		onLine:  onLine,
+	}
+}
+
+// stream returns a writer adding what is written to it as lines of stream.
+func (b *logBuffer) stream(name string) *logStream {
+	return &logStream{buffer: b, name: name}
+}
+
+// persist appends the lines added from now on to the file at path.
+func (b *logBuffer) persist(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.file != nil {
+		_ = b.file.Close()
+	}
+	b.file = file
+	return nil
+}
+
+// end adds the lines left without a newline and closes the file, once the
+// process has exited.
+func (b *logBuffer) end() {
+	b.mu.Lock()
+	var added []LogLine
+	for name, partial := range b.partial {
+		if len(partial) > 0 {
+			added = append(added, b.add(name, string(partial)))
+		}
+		delete(b.partial, name)
+	}
+	if b.file != nil {
+		_ = b.file.Close()
+		b.file = nil
+	}
+	b.mu.Unlock()
+	b.notify(added)
+}
+
+// Lines returns the lines kept, oldest first.
+func (b *logBuffer) Lines() []LogLine {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	lines := make([]LogLine, 0, len(b.lines))
+	if len(b.lines) == cap(b.lines) {
+		lines = append(lines, b.lines[b.next:]...)
+		return append(lines, b.lines[:b.next]...)
+	}
+	return append(lines, b.lines...)
+}
+
+func (b *logBuffer) write(stream string, p []byte) {
+	b.mu.Lock()
+	var added []LogLine
+	data := append(b.partial[stream], p...)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 && len(data) <= maxLogLineBytes {
+			break
+		}
+		if i < 0 || i > maxLogLineBytes {
+			i = maxLogLineBytes
+			added = append(added, b.add(stream, string(data[:i])))
+			data = data[i:]
+			continue
+		}
+		added = append(added, b.add(stream, strings.TrimSuffix(string(data[:i]), "\r")))
+		data = data[i+1:]
+	}
+	b.partial[stream] = data
+	b.mu.Unlock()
+	b.notify(added)
+}
+
+// add keeps a line, b.mu is held.
+func (b *logBuffer) add(stream, text string) LogLine {
+	line := LogLine{Time: time.Now(), Stream: stream, Text: text, Level: ParseLogLevel(text)}
+	if len(b.lines) < cap(b.lines) {
+		b.lines = append(b.lines, line)
+	} else {
+		b.lines[b.next] = line
+		b.next = (b.next + 1) % len(b.lines)
+	}
+	if b.file != nil {
+		_, _ = fmt.Fprintln(b.file, line.String())
+	}
+	return line
+}
+
+func (b *logBuffer) notify(lines []LogLine) {
+	if b.onLine == nil {
+		return
+	}
+	for _, line := range lines {
+		b.onLine(line)
+	}
+}
+
+// logStream is the stdout or stderr of a server process.
+type logStream struct {
+	buffer *logBuffer
+	name   string
+}
+
+func (s *logStream) Write(p []byte) (int, error) {
+	s.buffer.write(s.name, p)
+	return len(p), nil
+}
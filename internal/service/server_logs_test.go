@@ -0,0 +1,71 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func logTexts(lines []LogLine) []string {
+	var texts []string
+	for _, line := range lines {
+		texts = append(texts, line.Stream+": "+line.Text)
+	}
+	return texts
+}
+
+func TestLogBufferLines(t *testing.T) {
+	var notified []string
+	buffer := newLogBuffer(3, func(line LogLine) { notified = append(notified, line.Text) })
+	stdout, stderr := buffer.stream("stdout"), buffer.stream("stderr")
+
+	_, _ = stdout.Write([]byte("starting\r\nlisten"))
+	_, _ = stderr.Write([]byte("panic: boom\n"))
+	assert.Equal(t, []string{"stdout: starting", "stderr: panic: boom"}, logTexts(buffer.Lines()))
+
+	_, _ = stdout.Write([]byte("ing on :8080\nready\n"))
+	assert.Equal(t, []string{"stderr: panic: boom", "stdout: listening on :8080", "stdout: ready"}, logTexts(buffer.Lines()))
+
+	_, _ = stdout.Write([]byte("bye"))
+	buffer.end()
+	assert.Equal(t, []string{"stdout: listening on :8080", "stdout: ready", "stdout: bye"}, logTexts(buffer.Lines()))
+	assert.Equal(t, []string{"starting", "panic: boom", "listening on :8080", "ready", "bye"}, notified)
+
+	_, _ = stdout.Write([]byte(strings.Repeat("x", maxLogLineBytes+10)))
+	lines := buffer.Lines()
+	assert.Len(t, lines[len(lines)-1].Text, maxLogLineBytes)
+}
+
+func TestLogBufferPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "servers", "api.log")
+	buffer := newLogBuffer(10, nil)
+	_, _ = buffer.stream("stdout").Write([]byte("before\n"))
+
+	require.NoError(t, buffer.persist(path))
+	_, _ = buffer.stream("stderr").Write([]byte("level=WARN msg=slow\n"))
+	buffer.end()
+	_, _ = buffer.stream("stdout").Write([]byte("after\n"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Regexp(t, `^\S+ stderr level=WARN msg=slow\n$`, string(data))
+}
+
+func TestParseLogLevel(t *testing.T) {
+	tests := map[string]LogLevel{
+		`time=2024-01-01T00:00:00Z level=ERROR msg="db down"`: LogError,
+		`{"level":"warn","msg":"slow query"}`:                 LogWarn,
+		"2024/01/01 00:00:00 [INFO] listening on :8080":       LogInfo,
+		"DEBUG cache miss":                         LogDebug,
+		"panic: runtime error: index out of range": LogError,
+		"goroutine 1 [running]:":                   "",
+		"handled 3 errors":                         "",
+	}
+	for text, level := range tests {
+		assert.Equal(t, level, ParseLogLevel(text), text)
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"crypto/md5"
 	"errors"
@@ -9,181 +10,484 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/ManoloEsS/burrow/internal/database"
 )
 
+// ServerState is where a server is in its lifecycle.
+type ServerState string
+
+const (
+	ServerBuilding  ServerState = "building"
+	ServerStarting  ServerState = "starting"
+	ServerRunning   ServerState = "running"
+	ServerUnhealthy ServerState = "unhealthy"
+	ServerStopping  ServerState = "stopping"
+	ServerStopped   ServerState = "stopped"
+	ServerFailed    ServerState = "failed"
+	// ServerWaiting is a watched server that failed and is started again
+	// on the next change
+	ServerWaiting ServerState = "waiting"
+)
+
+// Active reports whether the server has been started and not stopped yet.
+func (s ServerState) Active() bool {
+	switch s {
+	case ServerStopped, ServerFailed, "":
+		return false
+	default:
+		return true
+	}
+}
+
+// ServerInfo is a snapshot of a managed server.
+type ServerInfo struct {
+	config.ServerConfig
+	State ServerState
+	// Message is the last event reported for the server
+	Message   string
+	PID       int
+	StartedAt time.Time
+	// Diagnostics are the compiler errors of the last build
+	Diagnostics []Diagnostic
+}
+
 type serverService struct {
-	isRunning      bool
-	updateChan     chan UIEvent
-	serverMu       sync.Mutex
-	cancelFunc     context.CancelFunc
-	serverProcess  *exec.Cmd
-	pathToServer   string
-	healthCheckURL string
-	httpClient     *http.Client
-	binaryPath     string
+	serverMu   sync.Mutex
+	servers    map[string]*managedServer
+	order      []string
+	updateChan chan UIEvent
+	// events waits to be handed to updateChan, in order, so a slow reader
+	// only loses old updates once maxQueuedEvents are waiting, and
+	// eventsReady tells the dispatcher about them
+	events      []UIEvent
+	eventsReady chan struct{}
+	// logsChanged is signalled when a server writes output, one pending
+	// signal standing for any number of lines
+	logsChanged chan struct{}
+	httpClient  *http.Client
+	logCfg      config.ServerLogsConfig
+	watchCfg    config.WatchConfig
+	targetRepo  *database.Database
+}
+
+// managedServer is a server with the state of its last run, guarded by the
+// serverMu of its service.
+type managedServer struct {
+	spec          config.ServerConfig
+	target        buildTarget
+	state         ServerState
+	message       string
+	cancelFunc    context.CancelFunc
+	serverProcess *exec.Cmd
+	binaryPath    string
+	// builds counts the binaries built, each one getting a path of its own
+	builds    int
+	startedAt time.Time
+	// logs outlives the runs of the server, so the output of a crash can
+	// be read after it
+	logs        *logBuffer
+	diagnostics []Diagnostic
 }
 
 type UIEvent struct {
 	Type    string
 	Message string
+	// Server is the name of the server the event is about
+	Server string
 }
 
-func NewServerService() ServerService {
+func NewServerService(targetRepo *database.Database, cfg *config.Config) ServerService {
 	client := &http.Client{
 		Timeout: 5 * time.Second,
 	}
-	return &serverService{
-		httpClient: client,
+	s := &serverService{
+		httpClient:  client,
+		servers:     make(map[string]*managedServer),
+		eventsReady: make(chan struct{}, 1),
+		logsChanged: make(chan struct{}, 1),
+		logCfg:      cfg.ServerLogs,
+		watchCfg:    cfg.Watch,
+		targetRepo:  targetRepo,
+	}
+	go s.dispatchEvents()
+	return s
+}
+
+// ServerName names a server after its file, or after its directory for a
+// main.go.
+func ServerName(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), ".go")
+	if name == "main" {
+		if dir := filepath.Base(filepath.Dir(path)); dir != "." && dir != string(filepath.Separator) {
+			name = dir
+		}
 	}
+	return name
 }
 
-func (s *serverService) StartServer(path string, port string, updateChan chan UIEvent) error {
+// StartServer builds and runs the server described by spec, replacing the
+// spec of a stopped server with the same name. Servers run side by side as
+// long as their names and ports differ.
+func (s *serverService) StartServer(spec config.ServerConfig, updateChan chan UIEvent) error {
 	s.serverMu.Lock()
 	s.updateChan = updateChan
 	s.serverMu.Unlock()
 
-	s.sendEvent("update", "starting server...")
-
-	validPath, err := s.validatePath(path)
+	target, err := s.resolveTarget(spec)
 	if err != nil {
 		return fmt.Errorf("invalid path: %v", err)
 	}
-	s.sendEvent("update", "valid path")
-
-	if s.isRunning {
-		return fmt.Errorf("server already running")
+	if spec.Name == "" {
+		spec.Name = ServerName(target.Source)
+	}
+	// paths on disk are absolute now, only an import path needs the directory
+	spec.Path, spec.Dir = target.Source, ""
+	if isImportPath(spec.Path) {
+		spec.Dir = target.Dir
 	}
+	s.sendEvent(spec.Name, "update", "valid path")
 
 	s.serverMu.Lock()
-	s.pathToServer = validPath
-	s.healthCheckURL = "http://localhost:" + port + "/health"
-	s.serverMu.Unlock()
+	for _, other := range s.servers {
+		if !other.state.Active() {
+			continue
+		}
+		if other.spec.Name == spec.Name {
+			s.serverMu.Unlock()
+			return fmt.Errorf("server %s already running", spec.Name)
+		}
+		if other.spec.Port == spec.Port {
+			s.serverMu.Unlock()
+			return fmt.Errorf("port %s is used by server %s", spec.Port, other.spec.Name)
+		}
+	}
 
+	server, ok := s.servers[spec.Name]
+	if !ok {
+		server = &managedServer{}
+		s.servers[spec.Name] = server
+		s.order = append(s.order, spec.Name)
+	}
+	logs := server.logs
+	if logs == nil {
+		logs = newLogBuffer(s.logCfg.Lines, func(LogLine) { s.notifyLogs() })
+	}
 	orchestratorCtx, cancel := context.WithCancel(context.Background())
-	s.cancelFunc = cancel
+	*server = managedServer{spec: spec, target: target, state: ServerBuilding, cancelFunc: cancel, logs: logs}
+	s.serverMu.Unlock()
+
+	s.rememberTarget(spec)
 
-	go s.orchestrator(orchestratorCtx)
-	s.sendEvent("update", "orchestrator starting...")
+	go s.orchestrator(orchestratorCtx, server)
+	s.sendEvent(spec.Name, "update", "orchestrator starting...")
 	return nil
 }
 
-func (s *serverService) orchestrator(ctx context.Context) {
-	healthCheckerCtx, healthCheckerCancel := context.WithCancel(ctx)
+// StopServer stops the named server, its orchestrator shuts the process
+// down and reports when it is gone.
+func (s *serverService) StopServer(name string) error {
+	s.serverMu.Lock()
+	defer s.serverMu.Unlock()
 
-	var wg sync.WaitGroup
+	server, ok := s.servers[name]
+	if !ok || server.cancelFunc == nil || !server.state.Active() {
+		return fmt.Errorf("server %s not running", name)
+	}
 
-	cmd, err := s.runCmdFromPath(healthCheckerCtx)
-	if err != nil {
-		s.sendEvent("error", fmt.Sprintf("couldn't run file: %v", err))
-		defer healthCheckerCancel()
-		return
+	cancel := server.cancelFunc
+	server.cancelFunc = nil
+	server.state = ServerStopping
+	cancel()
+
+	return nil
+}
+
+// StopAllServers stops every running server.
+func (s *serverService) StopAllServers() error {
+	var stopped int
+	for _, info := range s.Servers() {
+		if info.State.Active() && s.StopServer(info.Name) == nil {
+			stopped++
+		}
+	}
+	if stopped == 0 {
+		return errors.New("no servers running")
+	}
+	return nil
+}
+
+// Servers lists the servers started so far in the order they were first
+// started, stopped ones included so they can be started again.
+func (s *serverService) Servers() []ServerInfo {
+	s.serverMu.Lock()
+	defer s.serverMu.Unlock()
+
+	infos := make([]ServerInfo, 0, len(s.order))
+	for _, name := range s.order {
+		server := s.servers[name]
+		info := ServerInfo{
+			ServerConfig: server.spec,
+			State:        server.state,
+			Message:      server.message,
+			StartedAt:    server.startedAt,
+			Diagnostics:  server.diagnostics,
+		}
+		if server.serverProcess != nil && server.serverProcess.Process != nil {
+			info.PID = server.serverProcess.Process.Pid
+		}
+		infos = append(infos, info)
 	}
+	return infos
+}
+
+// Logs returns the last lines the named server wrote, oldest first.
+func (s *serverService) Logs(name string) []LogLine {
 	s.serverMu.Lock()
-	s.serverProcess = cmd
-	s.isRunning = true
+	server, ok := s.servers[name]
 	s.serverMu.Unlock()
+	if !ok || server.logs == nil {
+		return nil
+	}
+	return server.logs.Lines()
+}
 
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		s.healthChecker(healthCheckerCtx)
-	}()
+// RemoveServer forgets a stopped server.
+func (s *serverService) RemoveServer(name string) error {
+	s.serverMu.Lock()
+	defer s.serverMu.Unlock()
 
-	timeoutTicker := time.NewTicker(time.Minute * 15)
+	server, ok := s.servers[name]
+	if !ok {
+		return fmt.Errorf("server %s not found", name)
+	}
+	if server.state.Active() {
+		return fmt.Errorf("server %s is running, stop it first", name)
+	}
+	delete(s.servers, name)
+	s.order = slices.DeleteFunc(s.order, func(n string) bool { return n == name })
+	return nil
+}
+
+// orchestrator builds and runs server until it is stopped. A watched server
+// is swapped for each new build of its files, and waits for the next change
+// instead of failing when it does not build or exits.
+func (s *serverService) orchestrator(ctx context.Context, server *managedServer) {
+	var reloads chan string
+	if server.spec.Watch {
+		reloads = make(chan string)
+		go s.watchServer(ctx, server, reloads)
+	}
 
+	binaryPath, err := s.buildBinary(server)
+	if err != nil {
+		err = fmt.Errorf("couldn't run file: %v", err)
+	}
 	for {
+		for err == nil {
+			binaryPath, err = s.runServer(ctx, server, binaryPath, reloads)
+			if binaryPath == "" && err == nil {
+				s.finish(server, ServerStopped, "update", "server not running...ready")
+				return
+			}
+		}
+		if ctx.Err() != nil {
+			s.finish(server, ServerStopped, "update", "server not running...ready")
+			return
+		}
+		if reloads == nil {
+			s.finish(server, ServerFailed, "error", err.Error())
+			return
+		}
+
+		s.setState(server, ServerWaiting)
+		s.sendEvent(server.spec.Name, "error", err.Error()+", waiting for changes")
 		select {
 		case <-ctx.Done():
-			healthCheckerCancel()
-			wg.Wait()
-			s.gracefulShutdown()
+			s.finish(server, ServerStopped, "update", "server not running...ready")
+			return
+		case binaryPath = <-reloads:
+			err = nil
+		}
+	}
+}
 
-			s.serverMu.Lock()
-			defer s.serverMu.Unlock()
+// runServer runs the binary at binaryPath until ctx is done, the process
+// exits or a new build arrives on reloads. It returns the new build to run
+// next, why the server exited, or neither once it has been stopped.
+func (s *serverService) runServer(ctx context.Context, server *managedServer, binaryPath string, reloads <-chan string) (string, error) {
+	healthCheckerCtx, healthCheckerCancel := context.WithCancel(ctx)
+	defer healthCheckerCancel()
 
-			s.isRunning = false
-			s.serverProcess = nil
-			return
+	var wg sync.WaitGroup
 
-		case <-timeoutTicker.C:
-			healthCheckerCancel()
-			wg.Wait()
-			s.gracefulShutdown()
+	cmd, err := s.startProcess(healthCheckerCtx, server, binaryPath)
+	if err != nil {
+		s.cleanupBinary(server)
+		return "", fmt.Errorf("couldn't run file: %v", err)
+	}
+	exited := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		server.logs.end()
+		exited <- err
+	}()
 
-			s.serverMu.Lock()
-			defer s.serverMu.Unlock()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.healthChecker(healthCheckerCtx, server)
+	}()
 
-			s.isRunning = false
-			s.serverProcess = nil
-			return
+	// a watched server is kept running while it is being worked on, however
+	// long it goes without a change
+	var timeout <-chan time.Time
+	if !server.spec.Watch {
+		timeoutTicker := time.NewTicker(time.Minute * 15)
+		defer timeoutTicker.Stop()
+		timeout = timeoutTicker.C
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-timeout:
+	case err := <-exited:
+		// the server stopped on its own, such as after a panic
+		healthCheckerCancel()
+		wg.Wait()
+		s.cleanupBinary(server)
+		if err == nil {
+			err = errors.New("exit status 0")
 		}
+		return "", fmt.Errorf("server exited: %v", err)
+	case next := <-reloads:
+		healthCheckerCancel()
+		wg.Wait()
+		s.sendEvent(server.spec.Name, "update", "restarting server with the new build")
+		s.gracefulShutdown(server, exited)
+		return next, nil
 	}
 
+	healthCheckerCancel()
+	wg.Wait()
+	s.setState(server, ServerStopping)
+	s.gracefulShutdown(server, exited)
+	return "", nil
+}
+
+// finish ends the run of a server in state, reporting why.
+func (s *serverService) finish(server *managedServer, state ServerState, eventType, message string) {
+	s.serverMu.Lock()
+	if server.cancelFunc != nil {
+		server.cancelFunc()
+	}
+	server.state = state
+	server.serverProcess = nil
+	server.cancelFunc = nil
+	s.serverMu.Unlock()
+	s.sendEvent(server.spec.Name, eventType, message)
 }
 
-func (s *serverService) buildBinary(path string) error {
-	s.sendEvent("update", "building binary...")
+// buildBinary builds server into a new binary each time, so a build never
+// overwrites the binary of the process still running.
+func (s *serverService) buildBinary(server *managedServer) (string, error) {
+	s.sendEvent(server.spec.Name, "update", "building binary...")
 
 	cacheDir := config.GetServerCachePath()
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
-		return fmt.Errorf("failed to create cache directory: %v", err)
+		return "", fmt.Errorf("failed to create cache directory: %v", err)
 	}
 
-	binaryName := fmt.Sprintf("burrow-server-%x", md5.Sum([]byte(path)))[:8]
-	binaryPath := filepath.Join(cacheDir, binaryName)
+	// servers built from the same file still get a binary each
+	sum := md5.Sum([]byte(server.spec.Name + "\x00" + server.spec.Path))
+	s.serverMu.Lock()
+	server.builds++
+	binaryPath := filepath.Join(cacheDir, fmt.Sprintf("burrow-server-%x-%d", sum[:4], server.builds))
+	s.serverMu.Unlock()
 
-	cmd := exec.Command("go", "build", "-o", binaryPath, "-trimpath", path)
+	cmd := exec.Command("go", buildArgs(server.spec, server.target, binaryPath)...)
+	cmd.Dir = server.target.Dir
+	var stderr bytes.Buffer
 	cmd.Stdout = nil
-	cmd.Stderr = nil
+	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("build failed: %v", err)
+		if stderr.Len() == 0 {
+			return "", fmt.Errorf("build failed: %v", err)
+		}
+		buildErr := &BuildError{Output: stderr.String(), Diagnostics: ParseBuildOutput(stderr.String(), server.target.Dir)}
+		s.serverMu.Lock()
+		server.diagnostics = buildErr.Diagnostics
+		s.serverMu.Unlock()
+		return "", buildErr
 	}
 
-	s.binaryPath = binaryPath
-	s.sendEvent("update", "server running...")
-	return nil
+	s.serverMu.Lock()
+	server.diagnostics = nil
+	s.serverMu.Unlock()
+	return binaryPath, nil
 }
 
-func (s *serverService) runCmdFromPath(ctx context.Context) (*exec.Cmd, error) {
-	if err := s.buildBinary(s.pathToServer); err != nil {
+// startProcess runs the binary at binaryPath as the process of server.
+func (s *serverService) startProcess(ctx context.Context, server *managedServer, binaryPath string) (*exec.Cmd, error) {
+	s.serverMu.Lock()
+	server.binaryPath = binaryPath
+	s.serverMu.Unlock()
+
+	// the process is stopped by gracefulShutdown rather than killed with ctx
+	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
-
-	cmd := exec.CommandContext(ctx, s.binaryPath)
-	cmd.Stdout = nil
-	cmd.Stderr = nil
+	cmd := exec.Command(binaryPath)
+	cmd.Env = append(os.Environ(), "PORT="+server.spec.Port)
+	cmd.Stdout = server.logs.stream("stdout")
+	cmd.Stderr = server.logs.stream("stderr")
+
+	if s.logCfg.Persist {
+		if err := server.logs.persist(config.GetServerLogPath(server.spec.Name)); err != nil {
+			s.sendEvent(server.spec.Name, "error", fmt.Sprintf("failed to open log file: %v", err))
+		}
+	}
 
 	if err := cmd.Start(); err != nil {
+		server.logs.end()
 		return nil, err
 	}
 
+	s.serverMu.Lock()
+	server.serverProcess = cmd
+	server.startedAt = time.Now()
+	if server.state != ServerStopping {
+		server.state = ServerStarting
+	}
+	s.serverMu.Unlock()
+	s.sendEvent(server.spec.Name, "update", "server running...")
 	return cmd, nil
 }
 
-func (s *serverService) healthChecker(ctx context.Context) {
-	time.Sleep(time.Second * 1)
-
-	s.sendEvent("update", "trying to reach server")
+func (s *serverService) healthChecker(ctx context.Context, server *managedServer) {
+	name, healthCheckURL := server.spec.Name, server.spec.HealthCheckURL()
 
-	resp, err := s.httpClient.Get(s.healthCheckURL)
-	if resp != nil && resp.Body != nil {
-		_ = resp.Body.Close()
+	select {
+	case <-ctx.Done():
+		return
+	case <-time.After(time.Second):
 	}
-	if err != nil || resp.StatusCode != 200 {
-		if err != nil {
-			s.sendEvent("error", fmt.Sprintf("cant reach server: %v", err))
-		} else {
-			s.sendEvent("error", fmt.Sprintf("server returned status %d (expected 200)", resp.StatusCode))
-		}
+
+	s.sendEvent(name, "update", "trying to reach server")
+
+	if err := s.checkHealth(ctx, healthCheckURL); err != nil {
+		s.setState(server, ServerUnhealthy)
+		s.sendEvent(name, "error", err.Error())
 	} else {
-		s.sendEvent("update", "server reached, starting health checker")
+		s.setState(server, ServerRunning)
+		s.sendEvent(name, "update", "server reached, starting health checker")
 	}
 	ticker := time.NewTicker(time.Second * 5)
 	defer ticker.Stop()
@@ -193,82 +497,97 @@ func (s *serverService) healthChecker(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			resp, err := s.httpClient.Get(s.healthCheckURL)
-			if resp != nil && resp.Body != nil {
-				_ = resp.Body.Close()
-			}
-			if err != nil || resp.StatusCode != 200 {
-				if err != nil {
-					s.sendEvent("error", fmt.Sprintf("cant reach server: %v", err))
-				} else {
-					s.sendEvent("error", fmt.Sprintf("server returned status %d (expected 200)", resp.StatusCode))
-				}
+			if err := s.checkHealth(ctx, healthCheckURL); err != nil {
+				s.setState(server, ServerUnhealthy)
+				s.sendEvent(name, "error", err.Error())
+				continue
 			}
 
-			s.sendEvent("update", "server healthy")
+			s.setState(server, ServerRunning)
+			s.sendEvent(name, "update", "server healthy")
 		}
 	}
-
 }
 
-func (s *serverService) StopServer() error {
-	if s.cancelFunc == nil || !s.isRunning {
-		return errors.New("server not running")
+func (s *serverService) checkHealth(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("invalid health url: %v", err)
 	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("cant reach server: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("server returned status %d (expected 200)", resp.StatusCode)
+	}
+	return nil
+}
 
-	cancel := s.cancelFunc
-
+// setState moves a server to state unless it is already being stopped.
+func (s *serverService) setState(server *managedServer, state ServerState) {
 	s.serverMu.Lock()
 	defer s.serverMu.Unlock()
+	if server.state == ServerStopping && state != ServerStopping {
+		return
+	}
+	server.state = state
+}
 
-	s.cancelFunc = nil
-	cancel()
+// gracefulShutdown terminates the process of server, killing it when it does
+// not exit in time. exited receives the result of waiting for the process.
+func (s *serverService) gracefulShutdown(server *managedServer, exited <-chan error) {
+	name := server.spec.Name
 
-	return nil
-}
+	s.serverMu.Lock()
+	process := server.serverProcess
+	s.serverMu.Unlock()
 
-func (s *serverService) gracefulShutdown() {
-	if s.serverProcess == nil || s.serverProcess.Process == nil {
-		s.sendEvent("update", "no server process to stop")
-		s.cleanupBinary()
+	if process == nil || process.Process == nil {
+		s.sendEvent(name, "update", "no server process to stop")
+		s.cleanupBinary(server)
 		return
 	}
 
-	s.sendEvent("update", "stopping server")
-
-	process := s.serverProcess
+	s.sendEvent(name, "update", "stopping server")
 
 	if err := process.Process.Signal(syscall.SIGTERM); err != nil {
-		s.sendEvent("error", fmt.Sprintf("failed to terminate process: %v", err))
+		s.sendEvent(name, "error", fmt.Sprintf("failed to terminate process: %v", err))
 	}
 
-	done := make(chan error, 1)
-	go func() {
-		done <- process.Wait()
-	}()
-
 	select {
-	case err := <-done:
-		if err != nil {
-			s.sendEvent("error", fmt.Sprintf("server process exited with error: %v", err))
+	case err := <-exited:
+		if terminatedBy(err, syscall.SIGTERM) {
+			// servers without a signal handler die of the SIGTERM sent above
+			s.sendEvent(name, "update", "server process terminated")
+		} else if err != nil {
+			s.sendEvent(name, "error", fmt.Sprintf("server process exited with error: %v", err))
 		} else {
-			s.sendEvent("update", "server process shut down gracefully")
+			s.sendEvent(name, "update", "server process shut down gracefully")
 		}
 	case <-time.After(5 * time.Second):
-		s.sendEvent("error", "server didn't shutdown gracefully, force killing")
-		if process.Process != nil {
-			if err := process.Process.Kill(); err != nil {
-				s.sendEvent("error", fmt.Sprintf("failed to kill process %d: %v", s.serverProcess.Process.Pid, err))
-			} else {
-				s.sendEvent("update", "server process force killed")
-			}
-			_ = process.Wait()
+		s.sendEvent(name, "error", "server didn't shutdown gracefully, force killing")
+		if err := process.Process.Kill(); err != nil {
+			s.sendEvent(name, "error", fmt.Sprintf("failed to kill process %d: %v", process.Process.Pid, err))
+		} else {
+			s.sendEvent(name, "update", "server process force killed")
 		}
+		<-exited
 	}
 
-	s.cleanupBinary()
+	s.cleanupBinary(server)
+}
 
-	s.sendEvent("update", "server not running...ready")
+// terminatedBy tells whether the process waited for with err was killed by
+// signal.
+func terminatedBy(err error, signal syscall.Signal) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	status, ok := exitErr.Sys().(syscall.WaitStatus)
+	return ok && status.Signaled() && status.Signal() == signal
 }
 
 func (s *serverService) validatePath(path string) (string, error) {
@@ -278,10 +597,19 @@ func (s *serverService) validatePath(path string) (string, error) {
 		return "", fmt.Errorf("could not resolve path: %v", err)
 	}
 
-	if _, err := os.Stat(absPath); err != nil {
+	info, err := os.Stat(absPath)
+	if err != nil {
 		return "", fmt.Errorf("file does not exist: %v", err)
 	}
 
+	if info.IsDir() {
+		goFiles, _ := filepath.Glob(filepath.Join(absPath, "*.go"))
+		if len(goFiles) == 0 {
+			return "", fmt.Errorf("no .go files in %s", absPath)
+		}
+		return absPath, nil
+	}
+
 	if !strings.HasSuffix(absPath, ".go") {
 		return "", errors.New("file is not .go type")
 	}
@@ -290,26 +618,91 @@ func (s *serverService) validatePath(path string) (string, error) {
 
 }
 
-func (s *serverService) cleanupBinary() {
-	if s.binaryPath != "" {
-		if err := os.Remove(s.binaryPath); err != nil && !os.IsNotExist(err) {
-			s.sendEvent("error", fmt.Sprintf("failed to cleanup binary: %v", err))
+func (s *serverService) cleanupBinary(server *managedServer) {
+	s.serverMu.Lock()
+	binaryPath := server.binaryPath
+	server.binaryPath = ""
+	s.serverMu.Unlock()
+
+	if binaryPath != "" {
+		if err := os.Remove(binaryPath); err != nil && !os.IsNotExist(err) {
+			s.sendEvent(server.spec.Name, "error", fmt.Sprintf("failed to cleanup binary: %v", err))
 		} else {
-			s.sendEvent("update", "cleanup successful")
+			s.sendEvent(server.spec.Name, "update", "cleanup successful")
 		}
-		s.binaryPath = ""
 	}
 }
 
-func (s *serverService) sendEvent(eventType, message string) {
+// sendEvent reports an event about the named server and keeps its message
+// for the server table. The event is queued rather than dropped when the
+// reader of updateChan falls behind.
+func (s *serverService) sendEvent(name, eventType, message string) {
 	s.serverMu.Lock()
-	updateChan := s.updateChan
+	if server, ok := s.servers[name]; ok {
+		server.message = message
+	}
+	if s.updateChan != nil {
+		if len(s.events) >= maxQueuedEvents {
+			s.events = dropOldEvent(s.events)
+		}
+		s.events = append(s.events, UIEvent{Type: eventType, Message: message, Server: name})
+	}
 	s.serverMu.Unlock()
 
-	if updateChan != nil {
-		select {
-		case updateChan <- UIEvent{Type: eventType, Message: message}:
-		default:
+	select {
+	case s.eventsReady <- struct{}{}:
+	default:
+	}
+}
+
+// maxQueuedEvents bounds the events waiting for a reader that stopped
+// reading, such as a hung UI.
+const maxQueuedEvents = 1000
+
+// dropOldEvent makes room in a full queue by dropping its oldest update,
+// errors are only dropped when nothing else is queued. A later update
+// replaces the status line of its server anyway.
+func dropOldEvent(events []UIEvent) []UIEvent {
+	drop := 0
+	for i, event := range events {
+		if event.Type != "error" {
+			drop = i
+			break
 		}
 	}
+	return slices.Delete(events, drop, drop+1)
+}
+
+// dispatchEvents hands the queued events to updateChan, waiting for the
+// reader rather than dropping them.
+func (s *serverService) dispatchEvents() {
+	for range s.eventsReady {
+		for {
+			s.serverMu.Lock()
+			if len(s.events) == 0 {
+				s.serverMu.Unlock()
+				break
+			}
+			event, updateChan := s.events[0], s.updateChan
+			s.events = s.events[1:]
+			s.serverMu.Unlock()
+
+			updateChan <- event
+		}
+	}
+}
+
+// notifyLogs notes that a server wrote a line. Lines only set the
+// logsChanged signal, so a chatty server cannot crowd out the events.
+func (s *serverService) notifyLogs() {
+	select {
+	case s.logsChanged <- struct{}{}:
+	default:
+	}
+}
+
+// LogsChanged is signalled after servers write output, once for any number
+// of lines written since it was last received.
+func (s *serverService) LogsChanged() <-chan struct{} {
+	return s.logsChanged
 }
@@ -1,31 +1,33 @@
 package service
 
 import (
+	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/ManoloEsS/burrow/internal/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func TestStartServerInvalidPath(t *testing.T) {
-	service := NewServerService()
-	serverService := service.(*serverService)
+	service := NewServerService(nil, &config.Config{})
 
 	updateChan := make(chan UIEvent, 10)
 	defer close(updateChan)
 
-	err := service.StartServer("/nonexistent/file.go", "8080", updateChan)
+	err := service.StartServer(config.ServerConfig{Path: "/nonexistent/file.go", Port: "8080"}, updateChan)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "file does not exist")
-	assert.Empty(t, serverService.pathToServer)
-	assert.False(t, serverService.isRunning)
+	assert.Empty(t, service.Servers())
 }
 
 func TestStartServerNonGoFile(t *testing.T) {
-	service := NewServerService()
+	service := NewServerService(nil, &config.Config{})
 
 	tempDir := t.TempDir()
 	serverFile := filepath.Join(tempDir, "test_server.txt")
@@ -35,45 +37,85 @@ func TestStartServerNonGoFile(t *testing.T) {
 	updateChan := make(chan UIEvent, 10)
 	defer close(updateChan)
 
-	err = service.StartServer(serverFile, "8080", updateChan)
+	err = service.StartServer(config.ServerConfig{Path: serverFile, Port: "8080"}, updateChan)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "file is not .go type")
 }
 
+func TestStartServerConflicts(t *testing.T) {
+	service := NewServerService(nil, &config.Config{})
+	serverService := service.(*serverService)
+	serverService.servers["api"] = &managedServer{spec: config.ServerConfig{Name: "api", Port: "8080"}, state: ServerRunning}
+	serverService.order = []string{"api"}
+
+	serverFile := filepath.Join(t.TempDir(), "auth.go")
+	require.NoError(t, os.WriteFile(serverFile, []byte("package main\n\nfunc main() {}"), 0644))
+
+	err := service.StartServer(config.ServerConfig{Name: "api", Path: serverFile, Port: "9090"}, nil)
+	assert.ErrorContains(t, err, "server api already running")
+
+	err = service.StartServer(config.ServerConfig{Path: serverFile, Port: "8080"}, nil)
+	assert.ErrorContains(t, err, "port 8080 is used by server api")
+}
+
 func TestStopServer(t *testing.T) {
-	service := NewServerService()
+	service := NewServerService(nil, &config.Config{})
 	serverService := service.(*serverService)
 
-	serverService.isRunning = true
-	serverService.cancelFunc = func() {}
-	serverService.serverProcess = nil
+	cancelled := false
+	server := &managedServer{spec: config.ServerConfig{Name: "api"}, state: ServerRunning, cancelFunc: func() { cancelled = true }}
+	serverService.servers["api"] = server
 
 	updateChan := make(chan UIEvent, 10)
 	defer close(updateChan)
 	serverService.updateChan = updateChan
 
-	err := service.StopServer()
+	err := service.StopServer("api")
 
 	assert.NoError(t, err)
-	assert.Nil(t, serverService.cancelFunc)
+	assert.True(t, cancelled)
+	assert.Nil(t, server.cancelFunc)
+	assert.Equal(t, ServerStopping, server.state)
 }
 
 func TestStopServerNotRunning(t *testing.T) {
-	service := NewServerService()
+	service := NewServerService(nil, &config.Config{})
 	serverService := service.(*serverService)
+	serverService.servers["api"] = &managedServer{spec: config.ServerConfig{Name: "api"}, state: ServerStopped}
 
-	assert.False(t, serverService.isRunning)
-	assert.Nil(t, serverService.cancelFunc)
+	err := service.StopServer("api")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "server api not running")
 
-	err := service.StopServer()
+	err = service.StopServer("missing")
+	assert.Contains(t, err.Error(), "server missing not running")
 
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "server not running")
+	assert.ErrorContains(t, service.StopAllServers(), "no servers running")
+}
+
+func TestRemoveServer(t *testing.T) {
+	service := NewServerService(nil, &config.Config{})
+	serverService := service.(*serverService)
+	serverService.servers["api"] = &managedServer{spec: config.ServerConfig{Name: "api"}, state: ServerRunning}
+	serverService.servers["auth"] = &managedServer{spec: config.ServerConfig{Name: "auth"}, state: ServerFailed}
+	serverService.order = []string{"api", "auth"}
+
+	assert.ErrorContains(t, service.RemoveServer("api"), "stop it first")
+	require.NoError(t, service.RemoveServer("auth"))
+	servers := service.Servers()
+	require.Len(t, servers, 1)
+	assert.Equal(t, "api", servers[0].Name)
+}
+
+func TestServerName(t *testing.T) {
+	assert.Equal(t, "auth_stub", ServerName("stubs/auth_stub.go"))
+	assert.Equal(t, "api", ServerName("/src/shop/cmd/api/main.go"))
+	assert.Equal(t, "main", ServerName("main.go"))
 }
 
 func TestValidatePath(t *testing.T) {
-	service := NewServerService()
+	service := NewServerService(nil, &config.Config{})
 	serverService := service.(*serverService)
 
 	tempDir := t.TempDir()
@@ -104,30 +146,221 @@ func TestValidatePath(t *testing.T) {
 }
 
 func TestCleanupBinaryNoBinary(t *testing.T) {
-	service := NewServerService()
+	service := NewServerService(nil, &config.Config{})
 	serverService := service.(*serverService)
 
-	assert.Empty(t, serverService.binaryPath)
+	server := &managedServer{}
+	serverService.cleanupBinary(server)
 
-	serverService.cleanupBinary()
-
-	assert.Empty(t, serverService.binaryPath)
+	assert.Empty(t, server.binaryPath)
 }
 
 func TestSendEvent(t *testing.T) {
-	service := NewServerService()
+	service := NewServerService(nil, &config.Config{})
 	serverService := service.(*serverService)
+	serverService.servers["api"] = &managedServer{}
+	serverService.order = []string{"api"}
 
 	eventChan := make(chan UIEvent, 10)
-	defer close(eventChan)
 	serverService.updateChan = eventChan
 
-	serverService.sendEvent("test", "test message")
+	serverService.sendEvent("api", "test", "test message")
 
 	select {
 	case event := <-eventChan:
 		assert.Equal(t, "test", event.Type)
 		assert.Equal(t, "test message", event.Message)
-	default:
+		assert.Equal(t, "api", event.Server)
+	case <-time.After(time.Second):
+		t.Fatal("event not delivered")
+	}
+	assert.Equal(t, "test message", service.Servers()[0].Message)
+}
+
+func TestEventsSurviveLogFlood(t *testing.T) {
+	service := NewServerService(nil, &config.Config{})
+	serverService := service.(*serverService)
+	serverService.servers["api"] = &managedServer{logs: newLogBuffer(100, func(LogLine) { serverService.notifyLogs() })}
+	serverService.order = []string{"api"}
+
+	// nothing reads while the server floods its output and the channel fills
+	eventChan := make(chan UIEvent, 2)
+	serverService.updateChan = eventChan
+	serverService.sendEvent("api", "update", "building binary...")
+	stdout := serverService.servers["api"].logs.stream("stdout")
+	for i := range 10000 {
+		_, err := stdout.Write([]byte("request " + strconv.Itoa(i) + "\n"))
+		require.NoError(t, err)
+	}
+	for i := range 5 {
+		serverService.sendEvent("api", "update", "step "+strconv.Itoa(i))
+	}
+	serverService.sendEvent("api", "error", "build failed: 1 error")
+
+	var messages []string
+	for len(messages) < 7 {
+		select {
+		case event := <-eventChan:
+			assert.NotEqual(t, "log", event.Type)
+			messages = append(messages, event.Message)
+		case <-time.After(time.Second):
+			t.Fatalf("events lost, got %v", messages)
+		}
+	}
+	assert.Equal(t, "building binary...", messages[0])
+	assert.Equal(t, "build failed: 1 error", messages[6])
+
+	// the lines leave a single pending signal
+	assert.Len(t, service.LogsChanged(), 1)
+	<-service.LogsChanged()
+	assert.Len(t, service.LogsChanged(), 0)
+	assert.Len(t, service.Logs("api"), 100)
+}
+
+func TestEventQueueIsBounded(t *testing.T) {
+	service := NewServerService(nil, &config.Config{})
+	serverService := service.(*serverService)
+	serverService.servers["api"] = &managedServer{}
+	serverService.order = []string{"api"}
+
+	// nothing ever reads, the dispatcher blocks on the first event
+	serverService.updateChan = make(chan UIEvent)
+	serverService.sendEvent("api", "update", "building binary...")
+	require.Eventually(t, func() bool {
+		serverService.serverMu.Lock()
+		defer serverService.serverMu.Unlock()
+		return len(serverService.events) == 0
+	}, time.Second, time.Millisecond)
+	serverService.sendEvent("api", "error", "build failed: 1 error")
+	for i := range 3 * maxQueuedEvents {
+		serverService.sendEvent("api", "update", "step "+strconv.Itoa(i))
 	}
+
+	serverService.serverMu.Lock()
+	defer serverService.serverMu.Unlock()
+	events := serverService.events
+	require.Len(t, events, maxQueuedEvents)
+	assert.Contains(t, events, UIEvent{Type: "error", Message: "build failed: 1 error", Server: "api"})
+	assert.Equal(t, "step "+strconv.Itoa(3*maxQueuedEvents-1), events[len(events)-1].Message)
+}
+
+const healthServer = `package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+)
+
+func main() {
+	fmt.Println("listening on", os.Getenv("PORT"))
+	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {})
+	_ = http.ListenAndServe("127.0.0.1:"+os.Getenv("PORT"), nil)
+}
+`
+
+func freePort(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+	return strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+}
+
+func waitForState(t *testing.T, service ServerService, name string, state ServerState) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		for _, info := range service.Servers() {
+			if info.Name == name {
+				return info.State == state
+			}
+		}
+		return false
+	}, 60*time.Second, 100*time.Millisecond, "server %s never got %s", name, state)
+}
+
+func TestRunSeveralServers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs servers")
+	}
+	serverFile := filepath.Join(t.TempDir(), "main.go")
+	require.NoError(t, os.WriteFile(serverFile, []byte(healthServer), 0644))
+
+	service := NewServerService(nil, &config.Config{ServerLogs: config.ServerLogsConfig{Lines: 100}})
+	// left open, the orchestrators may still report after the servers stop
+	updateChan := make(chan UIEvent, 100)
+	go func() {
+		for range updateChan {
+		}
+	}()
+
+	require.NoError(t, service.StartServer(config.ServerConfig{Name: "api", Path: serverFile, Port: freePort(t)}, updateChan))
+	require.NoError(t, service.StartServer(config.ServerConfig{Name: "auth", Path: serverFile, Port: freePort(t)}, updateChan))
+	waitForState(t, service, "api", ServerRunning)
+	waitForState(t, service, "auth", ServerRunning)
+
+	require.NoError(t, service.StopServer("auth"))
+	waitForState(t, service, "auth", ServerStopped)
+	servers := service.Servers()
+	assert.Equal(t, ServerRunning, servers[0].State)
+	assert.NotZero(t, servers[0].PID)
+	assert.Zero(t, servers[1].PID)
+
+	require.NoError(t, service.StopAllServers())
+	waitForState(t, service, "api", ServerStopped)
+
+	logs := service.Logs("api")
+	require.NotEmpty(t, logs)
+	assert.Equal(t, "listening on "+servers[0].Port, logs[0].Text)
+	assert.Equal(t, "stdout", logs[0].Stream)
+	assert.Nil(t, service.Logs("missing"))
+}
+
+func TestStartServerBuildErrors(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds servers")
+	}
+	serverFile := filepath.Join(t.TempDir(), "broken.go")
+	require.NoError(t, os.WriteFile(serverFile, []byte("package main\n\nfunc main() {\n\tserve()\n}\n"), 0644))
+
+	service := NewServerService(nil, &config.Config{})
+	require.NoError(t, service.StartServer(config.ServerConfig{Path: serverFile, Port: freePort(t)}, nil))
+	waitForState(t, service, "broken", ServerFailed)
+
+	info := service.Servers()[0]
+	assert.Equal(t, "couldn't run file: build failed: 1 error", info.Message)
+	require.Len(t, info.Diagnostics, 1)
+	assert.Equal(t, Diagnostic{File: serverFile, Line: 4, Column: 2, Message: "undefined: serve"}, info.Diagnostics[0])
+}
+
+func TestRunModulePackage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs servers")
+	}
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod":             "module example.com/shop\n\ngo 1.21\n",
+		"cmd/api/main.go":    healthServer,
+		"cmd/api/dev.go":     "//go:build dev\n\npackage main\n\nimport \"fmt\"\n\nfunc init() { fmt.Println(\"dev build\", version) }\n",
+		"cmd/api/version.go": "package main\n\nvar version = \"unset\"\n",
+		"internal/db/db.go":  "package db\n",
+	})
+
+	service := NewServerService(nil, &config.Config{ServerLogs: config.ServerLogsConfig{Lines: 100}})
+	spec := config.ServerConfig{
+		Path:    "./cmd/api",
+		Dir:     filepath.Join(root, "internal", "db"),
+		Port:    freePort(t),
+		Tags:    []string{"dev"},
+		LDFlags: "-X main.version=1.2.0",
+	}
+	require.NoError(t, service.StartServer(spec, nil))
+	waitForState(t, service, "api", ServerRunning)
+
+	info := service.Servers()[0]
+	assert.Equal(t, filepath.Join(root, "cmd", "api"), info.Path)
+	assert.Equal(t, "dev build 1.2.0", service.Logs("api")[0].Text)
+
+	require.NoError(t, service.StopServer("api"))
+	waitForState(t, service, "api", ServerStopped)
 }
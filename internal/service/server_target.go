@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/ManoloEsS/burrow/internal/database"
+)
+
+// maxRecentTargets is how many of the servers started last are remembered.
+const maxRecentTargets = 10
+
+// buildTarget is what go build is run on and where.
+type buildTarget struct {
+	// Dir is the working directory of go build, the module root for a
+	// package in a module
+	Dir string
+	// Package is handed to go build, a .go file, a ./ directory relative
+	// to Dir or an import path
+	Package string
+	// Source is the absolute path of the file or directory, or the import
+	// path, the server is named after
+	Source string
+}
+
+// resolveTarget works out how to build the file, directory or package of
+// spec. A ./ path that is not found from spec.Dir is looked up from the root
+// of its module, so ./cmd/api works from anywhere in the module.
+func (s *serverService) resolveTarget(spec config.ServerConfig) (buildTarget, error) {
+	if strings.Contains(spec.Path, "...") {
+		return buildTarget{}, errors.New("name a single main package, not a pattern")
+	}
+
+	base := spec.Dir
+	if base == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return buildTarget{}, fmt.Errorf("could not resolve path: %v", err)
+		}
+		base = wd
+	}
+	base, err := filepath.Abs(base)
+	if err != nil {
+		return buildTarget{}, fmt.Errorf("could not resolve path: %v", err)
+	}
+
+	path := spec.Path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(base, path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		relative := strings.HasPrefix(spec.Path, "./") || strings.HasPrefix(spec.Path, "../")
+		if root := moduleRoot(base); relative && root != "" {
+			if _, err := os.Stat(filepath.Join(root, spec.Path)); err == nil {
+				path = filepath.Join(root, spec.Path)
+			}
+		} else if isImportPath(spec.Path) {
+			return buildTarget{Dir: base, Package: spec.Path, Source: spec.Path}, nil
+		}
+	}
+
+	validPath, err := s.validatePath(path)
+	if err != nil {
+		return buildTarget{}, err
+	}
+	if strings.HasSuffix(validPath, ".go") {
+		return buildTarget{Dir: filepath.Dir(validPath), Package: validPath, Source: validPath}, nil
+	}
+
+	root := moduleRoot(validPath)
+	if root == "" {
+		return buildTarget{Dir: validPath, Package: ".", Source: validPath}, nil
+	}
+	rel, err := filepath.Rel(root, validPath)
+	if err != nil {
+		return buildTarget{}, fmt.Errorf("could not resolve path: %v", err)
+	}
+	return buildTarget{Dir: root, Package: "./" + filepath.ToSlash(rel), Source: validPath}, nil
+}
+
+// moduleRoot returns the closest directory from dir up holding a go.mod, or
+// "" outside of a module.
+func moduleRoot(dir string) string {
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// isImportPath tells a package path such as example.com/shop/cmd/api from a
+// path on disk.
+func isImportPath(path string) bool {
+	return path != "" &&
+		!filepath.IsAbs(path) &&
+		!strings.HasPrefix(path, ".") &&
+		!strings.HasSuffix(path, ".go")
+}
+
+// buildArgs are the arguments of go build for the server of spec.
+func buildArgs(spec config.ServerConfig, target buildTarget, binaryPath string) []string {
+	args := []string{"build", "-o", binaryPath, "-trimpath"}
+	if spec.Race {
+		args = append(args, "-race")
+	}
+	if len(spec.Tags) > 0 {
+		args = append(args, "-tags", strings.Join(spec.Tags, ","))
+	}
+	if spec.LDFlags != "" {
+		args = append(args, "-ldflags", spec.LDFlags)
+	}
+	return append(args, target.Package)
+}
+
+// rememberTarget records spec as the latest way its path was started.
+func (s *serverService) rememberTarget(spec config.ServerConfig) {
+	if s.targetRepo == nil {
+		return
+	}
+
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		log.Printf("could not marshal server target: %v", err)
+		return
+	}
+
+	ctx := context.Background()
+	err = s.targetRepo.Queries.UpsertServerTarget(ctx, database.UpsertServerTargetParams{
+		Path:     spec.Path,
+		SpecJson: string(specJSON),
+		UsedAt:   time.Now().UTC(),
+	})
+	if err != nil {
+		log.Printf("could not save server target: %v", err)
+		return
+	}
+	if err := s.targetRepo.Queries.DeleteServerTargetsBeyondCount(ctx, maxRecentTargets); err != nil {
+		log.Printf("could not prune server targets: %v", err)
+	}
+}
+
+// RecentTargets lists the servers started last, the latest first.
+func (s *serverService) RecentTargets() ([]config.ServerConfig, error) {
+	if s.targetRepo == nil {
+		return nil, nil
+	}
+
+	rows, err := s.targetRepo.Queries.ListServerTargets(context.Background(), maxRecentTargets)
+	if err != nil {
+		return nil, fmt.Errorf("could not list recent servers: %v", err)
+	}
+
+	targets := make([]config.ServerConfig, 0, len(rows))
+	for _, row := range rows {
+		var spec config.ServerConfig
+		if err := json.Unmarshal([]byte(row.SpecJson), &spec); err != nil {
+			log.Printf("could not read server target %s: %v", row.Path, err)
+			continue
+		}
+		targets = append(targets, spec)
+	}
+	return targets, nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/ManoloEsS/burrow/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFiles writes files relative to dir, creating their directories.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func TestResolveTarget(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"shop/go.mod":               "module example.com/shop\n",
+		"shop/cmd/api/main.go":      "package main\n\nfunc main() {}\n",
+		"shop/internal/db/db.go":    "package db\n",
+		"shop/docs/README.md":       "docs\n",
+		"scratch/server.go":         "package main\n\nfunc main() {}\n",
+		"scratch/nested/handler.go": "package main\n",
+	})
+	shop := filepath.Join(root, "shop")
+	service := NewServerService(nil, &config.Config{}).(*serverService)
+
+	tests := []struct {
+		name     string
+		spec     config.ServerConfig
+		expected buildTarget
+		err      string
+	}{
+		{
+			name:     "file",
+			spec:     config.ServerConfig{Path: "server.go", Dir: filepath.Join(root, "scratch")},
+			expected: buildTarget{Dir: filepath.Join(root, "scratch"), Package: filepath.Join(root, "scratch", "server.go"), Source: filepath.Join(root, "scratch", "server.go")},
+		},
+		{
+			name:     "package in a module",
+			spec:     config.ServerConfig{Path: "cmd/api", Dir: shop},
+			expected: buildTarget{Dir: shop, Package: "./cmd/api", Source: filepath.Join(shop, "cmd", "api")},
+		},
+		{
+			name:     "relative to the module root",
+			spec:     config.ServerConfig{Path: "./cmd/api", Dir: filepath.Join(shop, "internal", "db")},
+			expected: buildTarget{Dir: shop, Package: "./cmd/api", Source: filepath.Join(shop, "cmd", "api")},
+		},
+		{
+			name:     "directory outside of a module",
+			spec:     config.ServerConfig{Path: filepath.Join(root, "scratch", "nested")},
+			expected: buildTarget{Dir: filepath.Join(root, "scratch", "nested"), Package: ".", Source: filepath.Join(root, "scratch", "nested")},
+		},
+		{
+			name:     "import path",
+			spec:     config.ServerConfig{Path: "example.com/shop/cmd/api", Dir: shop},
+			expected: buildTarget{Dir: shop, Package: "example.com/shop/cmd/api", Source: "example.com/shop/cmd/api"},
+		},
+		{name: "pattern", spec: config.ServerConfig{Path: "./cmd/...", Dir: shop}, err: "not a pattern"},
+		{name: "no go files", spec: config.ServerConfig{Path: "docs", Dir: shop}, err: "no .go files in"},
+		{name: "missing package", spec: config.ServerConfig{Path: "./cmd/web", Dir: shop}, err: "file does not exist"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, err := service.resolveTarget(tt.spec)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, target)
+		})
+	}
+}
+
+func TestBuildArgs(t *testing.T) {
+	target := buildTarget{Dir: "/src/shop", Package: "./cmd/api"}
+	assert.Equal(t, []string{"build", "-o", "/tmp/api", "-trimpath", "./cmd/api"}, buildArgs(config.ServerConfig{}, target, "/tmp/api"))
+
+	spec := config.ServerConfig{Race: true, Tags: []string{"dev", "sqlite"}, LDFlags: "-X main.version=1.2.0"}
+	assert.Equal(t, []string{"build", "-o", "/tmp/api", "-trimpath", "-race", "-tags", "dev,sqlite", "-ldflags", "-X main.version=1.2.0", "./cmd/api"}, buildArgs(spec, target, "/tmp/api"))
+}
+
+func TestRecentTargets(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "burrow.db")
+	db, err := database.NewDatabase(dbPath, "file:"+dbPath+"?_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	service := NewServerService(db, &config.Config{}).(*serverService)
+
+	for i := range maxRecentTargets + 2 {
+		service.rememberTarget(config.ServerConfig{Name: fmt.Sprint("server", i), Path: fmt.Sprintf("/src/server%d.go", i), Port: "8080"})
+	}
+	service.rememberTarget(config.ServerConfig{Name: "server5", Path: "/src/server5.go", Port: "9090", Tags: []string{"dev"}})
+
+	targets, err := service.RecentTargets()
+	require.NoError(t, err)
+	require.Len(t, targets, maxRecentTargets)
+	assert.Equal(t, config.ServerConfig{Name: "server5", Path: "/src/server5.go", Port: "9090", Tags: []string{"dev"}}, targets[0])
+	assert.Equal(t, "/src/server11.go", targets[1].Path)
+	assert.Equal(t, "/src/server2.go", targets[len(targets)-1].Path)
+
+	targets, err = NewServerService(nil, &config.Config{}).RecentTargets()
+	assert.NoError(t, err)
+	assert.Empty(t, targets)
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchServer rebuilds server after the files of its package change and
+// hands each binary that built to reloads, leaving the running process alone
+// when the build fails.
+func (s *serverService) watchServer(ctx context.Context, server *managedServer, reloads chan<- string) {
+	name := server.spec.Name
+	root, err := watchDir(server.target)
+	if err != nil {
+		s.sendEvent(name, "error", fmt.Sprintf("could not watch for changes: %v", err))
+		return
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		s.sendEvent(name, "error", fmt.Sprintf("could not watch for changes: %v", err))
+		return
+	}
+	defer func() { _ = watcher.Close() }()
+
+	// a lone file is built by itself, a package with its subpackages
+	recursive := !strings.HasSuffix(server.target.Source, ".go")
+	if err := s.addWatchDirs(watcher, root, root, recursive); err != nil {
+		s.sendEvent(name, "error", fmt.Sprintf("could not watch for changes: %v", err))
+		return
+	}
+
+	changed := make(map[string]bool)
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if recursive && event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := s.addWatchDirs(watcher, root, event.Name, true); err != nil {
+						s.sendEvent(name, "error", fmt.Sprintf("could not watch %s: %v", event.Name, err))
+					}
+					continue
+				}
+			}
+			if event.Op == fsnotify.Chmod || !watchMatch(s.watchCfg, root, event.Name) {
+				continue
+			}
+			changed[event.Name] = true
+			debounce = time.After(s.watchCfg.Debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			s.sendEvent(name, "error", fmt.Sprintf("watch error: %v", err))
+		case <-debounce:
+			s.sendEvent(name, "update", describeChanges(root, changed)+", rebuilding")
+			clear(changed)
+			debounce = nil
+
+			binaryPath, err := s.buildBinary(server)
+			if err != nil {
+				s.sendEvent(name, "error", fmt.Sprintf("reload failed: %v", err))
+				continue
+			}
+			select {
+			case reloads <- binaryPath:
+			case <-ctx.Done():
+				_ = os.Remove(binaryPath)
+				return
+			}
+		}
+	}
+}
+
+// watchDir is the directory of the package of target.
+func watchDir(target buildTarget) (string, error) {
+	if isImportPath(target.Source) {
+		cmd := exec.Command("go", "list", "-f", "{{.Dir}}", target.Package)
+		cmd.Dir = target.Dir
+		output, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("could not find package %s: %v", target.Package, err)
+		}
+		return strings.TrimSpace(string(output)), nil
+	}
+	if strings.HasSuffix(target.Source, ".go") {
+		return filepath.Dir(target.Source), nil
+	}
+	return target.Source, nil
+}
+
+// addWatchDirs watches dir, and its subdirectories that are not excluded
+// when recursive.
+func (s *serverService) addWatchDirs(watcher *fsnotify.Watcher, root, dir string, recursive bool) error {
+	if !recursive {
+		return watcher.Add(dir)
+	}
+	return filepath.WalkDir(dir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if file != root && matchGlobs(s.watchCfg.Exclude, root, file) {
+			return filepath.SkipDir
+		}
+		return watcher.Add(file)
+	})
+}
+
+// watchMatch tells whether a change to file reloads the server. Without
+// include globs every file that is not excluded does.
+func watchMatch(cfg config.WatchConfig, root, file string) bool {
+	if matchGlobs(cfg.Exclude, root, file) {
+		return false
+	}
+	return len(cfg.Include) == 0 || matchGlobs(cfg.Include, root, file)
+}
+
+// matchGlobs tells whether one of patterns matches the name of file or its
+// path relative to root.
+func matchGlobs(patterns []string, root, file string) bool {
+	rel, err := filepath.Rel(root, file)
+	if err != nil {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// describeChanges names the file that changed, or counts them.
+func describeChanges(root string, changed map[string]bool) string {
+	if len(changed) != 1 {
+		return fmt.Sprintf("%d files changed", len(changed))
+	}
+	for file := range changed {
+		if rel, err := filepath.Rel(root, file); err == nil {
+			file = rel
+		}
+		return file + " changed"
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchMatch(t *testing.T) {
+	root := filepath.Join("/src", "shop")
+	cfg := config.WatchConfig{
+		Include: []string{"*.go", "go.mod", "templates/*.html"},
+		Exclude: []string{"*_test.go", ".*", "vendor"},
+	}
+
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{"main.go", true},
+		{"handlers/users.go", true},
+		{"go.mod", true},
+		{"templates/index.html", true},
+		{"static/index.html", false},
+		{"main_test.go", false},
+		{".main.go.swp", false},
+		{"vendor", false},
+		{"README.md", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			assert.Equal(t, tt.want, watchMatch(cfg, root, filepath.Join(root, tt.file)))
+		})
+	}
+
+	assert.True(t, watchMatch(config.WatchConfig{Exclude: []string{"*.tmp"}}, root, filepath.Join(root, "notes.txt")))
+}
+
+func TestDescribeChanges(t *testing.T) {
+	root := filepath.Join("/src", "shop")
+	assert.Equal(t, "api/main.go changed", describeChanges(root, map[string]bool{filepath.Join(root, "api", "main.go"): true}))
+	assert.Equal(t, "2 files changed", describeChanges(root, map[string]bool{"a.go": true, "b.go": true}))
+}
+
+func TestWatchDir(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod":          "module example.com/shop\n\ngo 1.21\n",
+		"cmd/api/main.go": "package main\n\nfunc main() {}\n",
+	})
+
+	dir, err := watchDir(buildTarget{Dir: root, Package: "./cmd/api", Source: filepath.Join(root, "cmd", "api")})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "cmd", "api"), dir)
+
+	dir, err = watchDir(buildTarget{Dir: root, Package: filepath.Join(root, "cmd", "api", "main.go"), Source: filepath.Join(root, "cmd", "api", "main.go")})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "cmd", "api"), dir)
+
+	dir, err = watchDir(buildTarget{Dir: root, Package: "example.com/shop/cmd/api", Source: "example.com/shop/cmd/api"})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "cmd", "api"), dir)
+}
+
+func TestWatchReload(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs servers")
+	}
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.mod":             "module example.com/shop\n\ngo 1.21\n",
+		"cmd/api/main.go":    healthServer,
+		"cmd/api/version.go": "package main\n\nfunc init() { println(\"version 1\") }\n",
+	})
+
+	service := NewServerService(nil, &config.Config{
+		ServerLogs: config.ServerLogsConfig{Lines: 100},
+		Watch:      config.WatchConfig{Include: []string{"*.go"}, Debounce: 50 * time.Millisecond},
+	})
+	updateChan := make(chan UIEvent, 100)
+	go func() {
+		for range updateChan {
+		}
+	}()
+	spec := config.ServerConfig{Path: filepath.Join(root, "cmd", "api"), Port: freePort(t), Watch: true}
+	require.NoError(t, service.StartServer(spec, updateChan))
+	waitForState(t, service, "api", ServerRunning)
+	firstPID := service.Servers()[0].PID
+
+	// a change that does not build leaves the server running
+	writeFiles(t, root, map[string]string{"cmd/api/version.go": "package main\n\nfunc init() { undefined() }\n"})
+	require.Eventually(t, func() bool {
+		return len(service.Servers()[0].Diagnostics) == 1
+	}, 60*time.Second, 100*time.Millisecond)
+	info := service.Servers()[0]
+	assert.Equal(t, ServerRunning, info.State)
+	assert.Equal(t, firstPID, info.PID)
+
+	writeFiles(t, root, map[string]string{"cmd/api/version.go": "package main\n\nfunc init() { println(\"version 2\") }\n"})
+	require.Eventually(t, func() bool {
+		for _, line := range service.Logs("api") {
+			if strings.Contains(line.Text, "version 2") {
+				return true
+			}
+		}
+		return false
+	}, 60*time.Second, 100*time.Millisecond)
+	waitForState(t, service, "api", ServerRunning)
+	info = service.Servers()[0]
+	assert.NotEqual(t, firstPID, info.PID)
+	assert.Empty(t, info.Diagnostics)
+
+	require.NoError(t, service.StopServer("api"))
+	waitForState(t, service, "api", ServerStopped)
+}
+
+func TestWatchWaitsForFix(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs servers")
+	}
+	dir := t.TempDir()
+	serverFile := filepath.Join(dir, "main.go")
+	writeFiles(t, dir, map[string]string{"main.go": "package main\n\nfunc main() {\n\tserve()\n}\n"})
+
+	service := NewServerService(nil, &config.Config{
+		ServerLogs: config.ServerLogsConfig{Lines: 100},
+		Watch:      config.WatchConfig{Include: []string{"*.go"}, Debounce: 50 * time.Millisecond},
+	})
+	require.NoError(t, service.StartServer(config.ServerConfig{Name: "api", Path: serverFile, Port: freePort(t), Watch: true}, nil))
+	waitForState(t, service, "api", ServerWaiting)
+	assert.Equal(t, "couldn't run file: build failed: 1 error, waiting for changes", service.Servers()[0].Message)
+
+	writeFiles(t, dir, map[string]string{"main.go": healthServer})
+	waitForState(t, service, "api", ServerRunning)
+
+	require.NoError(t, service.StopServer("api"))
+	waitForState(t, service, "api", ServerStopped)
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var errHeaderTimeout = errors.New("timed out waiting for the stream to start")
+
+// withHeaderTimeout returns a context cancelled once timeout passes unless
+// received is called first, so a stream is only timed out before it starts.
+func withHeaderTimeout(ctx context.Context, timeout time.Duration) (_ context.Context, received func(), cancel func()) {
+	ctx, cancelCause := context.WithCancelCause(ctx)
+	received = func() {}
+	if timeout > 0 {
+		timer := time.AfterFunc(timeout, func() { cancelCause(errHeaderTimeout) })
+		received = func() { timer.Stop() }
+	}
+	return ctx, received, func() { cancelCause(nil) }
+}
+
+// headerTimeoutCause reports a request cancelled by withHeaderTimeout as a
+// timeout, not as cancelled by the user.
+func headerTimeoutCause(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); errors.Is(cause, errHeaderTimeout) {
+		return cause
+	}
+	return err
+}
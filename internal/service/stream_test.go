@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamRequestEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 1; i <= 3; i++ {
+			_, _ = fmt.Fprintf(w, "id: %d\nevent: tick\ndata: %d\n\n", i, i)
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	var events []domain.Event
+	s := &httpClientService{httpCfg: defaultHTTPConfig()}
+	resp, err := s.StreamRequest(context.Background(), &domain.Request{Method: "GET", URL: server.URL}, func(e domain.Event) {
+		events = append(events, e)
+	})
+	require.NoError(t, err)
+
+	require.Len(t, events, 3)
+	assert.Equal(t, "3", events[2].ID)
+	assert.Equal(t, "tick", events[2].Type)
+	assert.Equal(t, 3, resp.Stream.Events)
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func TestStreamRequestStopOutlivesTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.(http.Flusher).Flush()
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-ticker.C:
+				_, _ = fmt.Fprint(w, "data: ping\n\n")
+				w.(http.Flusher).Flush()
+			}
+		}
+	}))
+	defer server.Close()
+
+	cfg := defaultHTTPConfig()
+	cfg.Timeout = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	received := 0
+	s := &httpClientService{httpCfg: cfg}
+	resp, err := s.StreamRequest(ctx, &domain.Request{Method: "GET", URL: server.URL}, func(domain.Event) {
+		// the stream keeps going well past the timeout until it is stopped
+		received++
+		if received == 20 {
+			cancel()
+		}
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 20, resp.Stream.Events)
+}
+
+func TestStreamRequestHeaderTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	cfg := defaultHTTPConfig()
+	cfg.Timeout = 20 * time.Millisecond
+
+	s := &httpClientService{httpCfg: cfg}
+	_, err := s.StreamRequest(context.Background(), &domain.Request{Method: "GET", URL: server.URL}, func(domain.Event) {})
+	assert.ErrorIs(t, err, errHeaderTimeout)
+	assert.NotErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+)
+
+// timingTracer records the httptrace events of the last hop of a request,
+// earlier hops of a redirect chain are reported as a single phase.
+type timingTracer struct {
+	mu    sync.Mutex
+	start time.Time
+	hops  int
+
+	hopStart     time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	gotConn      time.Time
+	firstByte    time.Time
+	reused       bool
+}
+
+func newTimingTracer(start time.Time) *timingTracer {
+	return &timingTracer{start: start}
+}
+
+func (t *timingTracer) record(field *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// dialing several addresses fires the connect hooks more than once,
+	// keep the first start and the first successful finish
+	if field.IsZero() {
+		*field = time.Now()
+	}
+}
+
+func (t *timingTracer) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GetConn: func(string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.hops++
+			t.hopStart = time.Now()
+			t.dnsStart, t.dnsDone = time.Time{}, time.Time{}
+			t.connectStart, t.connectDone = time.Time{}, time.Time{}
+			t.tlsStart, t.tlsDone = time.Time{}, time.Time{}
+			t.gotConn, t.firstByte = time.Time{}, time.Time{}
+			t.reused = false
+		},
+		DNSStart: func(httptrace.DNSStartInfo) { t.record(&t.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.record(&t.dnsDone) },
+		ConnectStart: func(string, string) {
+			t.record(&t.connectStart)
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				t.record(&t.connectDone)
+			}
+		},
+		TLSHandshakeStart: func() { t.record(&t.tlsStart) },
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil {
+				t.record(&t.tlsDone)
+			}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.record(&t.gotConn)
+			t.mu.Lock()
+			t.reused = info.Reused
+			t.mu.Unlock()
+		},
+		GotFirstResponseByte: func() { t.record(&t.firstByte) },
+	}
+}
+
+// timing builds the phases once the body has been read at end.
+func (t *timingTracer) timing(end time.Time) *domain.Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	timing := &domain.Timing{Total: end.Sub(t.start), ConnReused: t.reused}
+	add := func(name string, from, to time.Time) {
+		if from.IsZero() || to.IsZero() || to.Before(from) {
+			return
+		}
+		timing.Phases = append(timing.Phases, domain.TimingPhase{
+			Name:     name,
+			Start:    from.Sub(t.start),
+			Duration: to.Sub(from),
+		})
+	}
+
+	if t.hops > 1 {
+		add(domain.PhaseRedirects, t.start, t.hopStart)
+	}
+	add(domain.PhaseDNS, t.dnsStart, t.dnsDone)
+	add(domain.PhaseConnect, t.connectStart, t.connectDone)
+	add(domain.PhaseTLS, t.tlsStart, t.tlsDone)
+	add(domain.PhaseTTFB, t.gotConn, t.firstByte)
+	add(domain.PhaseTransfer, t.firstByte, end)
+
+	return timing
+}
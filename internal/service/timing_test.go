@@ -0,0 +1,81 @@
+package service
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"testing"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tracedGet(t *testing.T, client *http.Client, url string) *domain.Timing {
+	t.Helper()
+
+	start := time.Now()
+	tracer := newTimingTracer(start)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), tracer.clientTrace()))
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	_, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	return tracer.timing(time.Now())
+}
+
+func phaseNames(timing *domain.Timing) []string {
+	var names []string
+	for _, p := range timing.Phases {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func TestTimingTracer(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	t.Run("plain http", func(t *testing.T) {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
+
+		timing := tracedGet(t, &http.Client{Transport: &http.Transport{}}, srv.URL)
+		assert.Equal(t, []string{domain.PhaseConnect, domain.PhaseTTFB, domain.PhaseTransfer}, phaseNames(timing))
+		assert.False(t, timing.ConnReused)
+
+		last := timing.Phases[len(timing.Phases)-1]
+		assert.LessOrEqual(t, last.End(), timing.Total)
+		for i := 1; i < len(timing.Phases); i++ {
+			assert.GreaterOrEqual(t, timing.Phases[i].Start, timing.Phases[i-1].Start)
+		}
+	})
+
+	t.Run("tls", func(t *testing.T) {
+		srv := httptest.NewTLSServer(handler)
+		defer srv.Close()
+
+		timing := tracedGet(t, srv.Client(), srv.URL)
+		assert.Equal(t, []string{domain.PhaseConnect, domain.PhaseTLS, domain.PhaseTTFB, domain.PhaseTransfer}, phaseNames(timing))
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		srv := httptest.NewServer(handler)
+		defer srv.Close()
+
+		timing := tracedGet(t, &http.Client{Transport: &http.Transport{}}, srv.URL+"/old")
+		assert.Equal(t, []string{domain.PhaseRedirects, domain.PhaseTTFB, domain.PhaseTransfer}, phaseNames(timing))
+		assert.True(t, timing.ConnReused)
+	})
+}
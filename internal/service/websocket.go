@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/gorilla/websocket"
+)
+
+// how long a close handshake may take before the connection is dropped
+const wsCloseTimeout = time.Second
+
+// ConnectWebSocket opens a WebSocket for req, sends its scripted messages and
+// then those arriving on outgoing, reporting every frame sent and received to
+// onFrame. Pings from the server are answered and a close from the server is
+// echoed back. It blocks until the connection ends, cancelling ctx closes it
+// normally, and returns the handshake response.
+func (s *httpClientService) ConnectWebSocket(ctx context.Context, req *domain.Request, outgoing <-chan domain.WSMessage, onFrame func(domain.WSFrame)) (*domain.Response, error) {
+	lookup := &domain.SecretLookup{Variables: s.GetActiveEnvironment().Lookup, Secrets: s.lookupSecret}
+	resolved := req.WithVariables(lookup.Lookup)
+
+	sentAt := time.Now()
+	resp, conn, err := &domain.Response{}, (*websocket.Conn)(nil), lookup.Err
+	if err == nil {
+		resp, conn, err = s.dialWebSocket(ctx, resolved)
+	}
+	s.recordHistory(req, resp, err, sentAt)
+	if err != nil {
+		return resp, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	ws := &wsConn{conn: conn, onFrame: onFrame}
+	return resp, ws.run(ctx, resolved, outgoing, func(msg domain.WSMessage) (domain.WSMessage, error) {
+		msg.Text = domain.ExpandVariables(msg.Text, lookup.Lookup)
+		return msg, lookup.Err
+	})
+}
+
+func (s *httpClientService) dialWebSocket(ctx context.Context, req *domain.Request) (*domain.Response, *websocket.Conn, error) {
+	if err := req.Auth.Validate(); err != nil {
+		return &domain.Response{}, nil, err
+	}
+	cfg := req.Options.Apply(s.httpCfg)
+	if err := cfg.Validate(); err != nil {
+		return &domain.Response{}, nil, err
+	}
+	transport, err := s.transport(cfg)
+	if err != nil {
+		return &domain.Response{}, nil, err
+	}
+
+	// the handshake is built like any request, without the body that holds
+	// the message to send
+	handshake := req.Clone()
+	if req.Auth != nil && req.Auth.Type == domain.AuthOAuth2 {
+		client, err := s.httpClient(cfg)
+		if err != nil {
+			return &domain.Response{}, nil, err
+		}
+		token, err := s.oauth2Token(ctx, client, *req.Auth)
+		if err != nil {
+			return &domain.Response{}, nil, fmt.Errorf("could not get oauth2 token: %w", err)
+		}
+		handshake.Auth = &domain.Auth{Type: domain.AuthBearer, Token: token}
+	}
+	handshake.Method, handshake.Body, handshake.BodyFile, handshake.Form = http.MethodGet, "", "", nil
+	httpReq, err := reqStructToHttpReq(ctx, handshake)
+	if err != nil {
+		return &domain.Response{}, nil, err
+	}
+	header := httpReq.Header.Clone()
+	for _, key := range []string{"Content-Type", "Upgrade", "Connection", "Sec-Websocket-Key", "Sec-Websocket-Version", "Sec-Websocket-Extensions"} {
+		header.Del(key)
+	}
+
+	wsURL := *httpReq.URL
+	switch wsURL.Scheme {
+	case "http":
+		wsURL.Scheme = "ws"
+	case "https":
+		wsURL.Scheme = "wss"
+	}
+
+	dialer := &websocket.Dialer{
+		Proxy:            transport.Proxy,
+		TLSClientConfig:  transport.TLSClientConfig,
+		HandshakeTimeout: cfg.Timeout,
+	}
+
+	start := time.Now()
+	conn, httpResp, err := dialer.DialContext(ctx, wsURL.String(), header)
+	resp := &domain.Response{}
+	if httpResp != nil {
+		// the body of a successful handshake is empty, a refused one is read
+		// so its reason can be shown
+		_ = resp.BuildResponse(httpResp, domain.BodyOptions{MaxMemory: s.responseCfg.MaxBodyBytes()})
+		_ = httpResp.Body.Close()
+		resp.ResponseTime = time.Since(start)
+	}
+	if errors.Is(err, websocket.ErrBadHandshake) && httpResp != nil {
+		return resp, nil, fmt.Errorf("websocket handshake refused: %s", httpResp.Status)
+	}
+	if err != nil {
+		return &domain.Response{}, nil, err
+	}
+	return resp, conn, nil
+}
+
+// wsConn reports the frames of an open connection. Only run writes data
+// messages, control frames may be written from the read loop as gorilla
+// allows, so frames are reported one at a time.
+type wsConn struct {
+	conn    *websocket.Conn
+	mu      sync.Mutex
+	onFrame func(domain.WSFrame)
+}
+
+func (ws *wsConn) report(frame domain.WSFrame) {
+	frame.Time = time.Now()
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.onFrame(frame)
+}
+
+func (ws *wsConn) frame(sent bool, frameType string, data []byte) {
+	ws.report(domain.WSFrame{Sent: sent, Type: frameType, Data: data})
+}
+
+func (ws *wsConn) run(ctx context.Context, req *domain.Request, outgoing <-chan domain.WSMessage, resolve func(domain.WSMessage) (domain.WSMessage, error)) error {
+	ws.conn.SetPingHandler(func(data string) error {
+		ws.frame(false, domain.FramePing, []byte(data))
+		err := ws.conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(wsCloseTimeout))
+		if err != nil {
+			return nil
+		}
+		ws.frame(true, domain.FramePong, []byte(data))
+		return nil
+	})
+	ws.conn.SetPongHandler(func(data string) error {
+		ws.frame(false, domain.FramePong, []byte(data))
+		return nil
+	})
+	ws.conn.SetCloseHandler(func(code int, text string) error {
+		ws.report(domain.WSFrame{Type: domain.FrameClose, Code: code, Data: []byte(text)})
+		if code == websocket.CloseNoStatusReceived {
+			code = websocket.CloseNormalClosure
+		}
+		message := websocket.FormatCloseMessage(code, "")
+		if err := ws.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(wsCloseTimeout)); err == nil {
+			ws.report(domain.WSFrame{Sent: true, Type: domain.FrameClose, Code: code})
+		}
+		return nil
+	})
+
+	readErr := make(chan error, 1)
+	go func() {
+		for {
+			messageType, data, err := ws.conn.ReadMessage()
+			if err != nil {
+				readErr <- err
+				return
+			}
+			frameType := domain.FrameText
+			if messageType == websocket.BinaryMessage {
+				frameType = domain.FrameBinary
+			}
+			ws.frame(false, frameType, data)
+		}
+	}()
+
+	for _, msg := range req.Messages {
+		if err := ws.send(msg); err != nil {
+			return err
+		}
+	}
+
+	var ping <-chan time.Time
+	if req.Options != nil && req.Options.PingInterval > 0 {
+		ticker := time.NewTicker(req.Options.PingInterval)
+		defer ticker.Stop()
+		ping = ticker.C
+	}
+
+	for {
+		select {
+		case msg := <-outgoing:
+			msg, err := resolve(msg)
+			if err != nil {
+				return err
+			}
+			if err := ws.send(msg); err != nil {
+				return err
+			}
+		case <-ping:
+			if err := ws.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsCloseTimeout)); err != nil {
+				return err
+			}
+			ws.frame(true, domain.FramePing, nil)
+		case err := <-readErr:
+			// the server closing the connection is how it normally ends, a
+			// dropped connection is reported as an abnormal closure
+			var closeErr *websocket.CloseError
+			if !errors.As(err, &closeErr) {
+				return err
+			}
+			if closeErr.Code == websocket.CloseAbnormalClosure {
+				ws.report(domain.WSFrame{Type: domain.FrameClose, Code: closeErr.Code})
+			}
+			return nil
+		case <-ctx.Done():
+			return ws.close(readErr)
+		}
+	}
+}
+
+func (ws *wsConn) send(msg domain.WSMessage) error {
+	if msg.IsBinary() {
+		if err := ws.conn.WriteMessage(websocket.BinaryMessage, msg.Binary); err != nil {
+			return err
+		}
+		ws.frame(true, domain.FrameBinary, msg.Binary)
+		return nil
+	}
+	if err := ws.conn.WriteMessage(websocket.TextMessage, []byte(msg.Text)); err != nil {
+		return err
+	}
+	ws.frame(true, domain.FrameText, []byte(msg.Text))
+	return nil
+}
+
+// close sends a normal closure and waits for the server to answer it.
+func (ws *wsConn) close(readErr <-chan error) error {
+	message := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	if err := ws.conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(wsCloseTimeout)); err != nil {
+		return nil
+	}
+	ws.report(domain.WSFrame{Sent: true, Type: domain.FrameClose, Code: websocket.CloseNormalClosure})
+
+	select {
+	case <-readErr:
+	case <-time.After(wsCloseTimeout):
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// frameLog collects frames reported by ConnectWebSocket for the test to
+// wait on.
+type frameLog struct {
+	mu     sync.Mutex
+	frames []domain.WSFrame
+}
+
+func (l *frameLog) add(frame domain.WSFrame) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.frames = append(l.frames, frame)
+}
+
+func (l *frameLog) labels() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var labels []string
+	for _, f := range l.frames {
+		direction := "<"
+		if f.Sent {
+			direction = ">"
+		}
+		labels = append(labels, direction+" "+f.Type+" "+string(f.Data))
+	}
+	return labels
+}
+
+func newWebSocketServer(t *testing.T, handle func(*websocket.Conn)) *httptest.Server {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") != "secret" {
+			http.Error(w, "missing token", http.StatusUnauthorized)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		handle(conn)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestConnectWebSocketScriptAndServerClose(t *testing.T) {
+	server := newWebSocketServer(t, func(conn *websocket.Conn) {
+		_ = conn.WriteControl(websocket.PingMessage, []byte("hi"), time.Now().Add(time.Second))
+		for range 2 {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			_ = conn.WriteMessage(messageType, append([]byte("echo "), data...))
+		}
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(4001, "done"))
+		_, _, _ = conn.ReadMessage()
+	})
+
+	req := &domain.Request{
+		Method:   domain.MethodWebSocket,
+		URL:      server.URL,
+		Headers:  map[string]string{"X-Token": "secret"},
+		Messages: []domain.WSMessage{{Text: "one"}, {Binary: []byte("two")}},
+	}
+
+	log := &frameLog{}
+	s := &httpClientService{httpCfg: defaultHTTPConfig()}
+	resp, err := s.ConnectWebSocket(context.Background(), req, nil, log.add)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+
+	labels := log.labels()
+	assert.Contains(t, labels, "< ping hi")
+	assert.Contains(t, labels, "> pong hi")
+	assert.Contains(t, labels, "> text one")
+	assert.Contains(t, labels, "< text echo one")
+	assert.Contains(t, labels, "< binary echo two")
+	assert.Contains(t, labels, "< close done")
+	assert.Contains(t, labels, "> close ")
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	for _, f := range log.frames {
+		if f.Type == domain.FrameClose && !f.Sent {
+			assert.Equal(t, 4001, f.Code)
+		}
+	}
+}
+
+func TestConnectWebSocketOutgoingAndStop(t *testing.T) {
+	server := newWebSocketServer(t, func(conn *websocket.Conn) {
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			_ = conn.WriteMessage(messageType, data)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	outgoing := make(chan domain.WSMessage)
+	echoed := make(chan struct{})
+	log := &frameLog{}
+
+	req := &domain.Request{Method: domain.MethodWebSocket, URL: strings.Replace(server.URL, "http", "ws", 1), Headers: map[string]string{"X-Token": "secret"}}
+	s := &httpClientService{httpCfg: defaultHTTPConfig()}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.ConnectWebSocket(ctx, req, outgoing, func(f domain.WSFrame) {
+			log.add(f)
+			if !f.Sent && f.Type == domain.FrameText {
+				close(echoed)
+			}
+		})
+		done <- err
+	}()
+
+	outgoing <- domain.WSMessage{Text: `{"n":1}`}
+	<-echoed
+	cancel()
+	require.NoError(t, <-done)
+
+	assert.Equal(t, []string{`> text {"n":1}`, `< text {"n":1}`, "> close ", "< close "}, log.labels())
+}
+
+func TestConnectWebSocketRefused(t *testing.T) {
+	server := newWebSocketServer(t, func(*websocket.Conn) {})
+
+	s := &httpClientService{httpCfg: defaultHTTPConfig()}
+	resp, err := s.ConnectWebSocket(context.Background(), &domain.Request{Method: domain.MethodWebSocket, URL: server.URL}, nil, func(domain.WSFrame) {})
+	assert.ErrorContains(t, err, "websocket handshake refused: 401 Unauthorized")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Contains(t, resp.Body, "missing token")
+}
@@ -4,30 +4,84 @@ import (
 	"fmt"
 
 	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/ManoloEsS/burrow/internal/domain"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
+const (
+	mainPage         = "main"
+	environmentsPage = "environments"
+	curlImportPage   = "curl"
+	historyPage      = "history"
+	promptPage       = "prompt"
+	confirmPage      = "confirm"
+	authPage         = "auth"
+	grpcMethodsPage  = "grpc"
+	graphQLFieldPage = "graphql"
+	serverLogPage    = "logs"
+	buildErrorPage   = "build"
+)
+
 type UIComponents struct {
+	Pages        *tview.Pages
 	MainLayout   *tview.Flex
 	Form         *tview.Form
 	LogoText     *tview.TextView
 	BindingsText *tview.TextView
-	ServerStatus *tview.TextView
+	ServerTable  *tview.Table
 	ServerPath   *tview.InputField
 
 	MethodDropdown *tview.DropDown
 	URLInput       *tview.InputField
 	HeadersText    *tview.TextArea
 	ParamsText     *tview.TextArea
+	AuthDropdown   *tview.DropDown
 	BodyText       *tview.TextArea
 	BodyType       *tview.DropDown
+	VariablesText  *tview.TextArea
+	AssertionsText *tview.TextArea
+	OptionsInput   *tview.InputField
 
 	ResponseView *tview.TextView
 
-	RequestList *tview.List
+	RequestTree *tview.TreeView
 	NameInput   *tview.InputField
 	StatusText  *tview.TextView
+	EnvStatus   *tview.TextView
+
+	EnvironmentModal *tview.Flex
+	EnvironmentList  *tview.List
+	EnvironmentForm  *tview.Form
+	EnvNameInput     *tview.InputField
+	EnvVariablesText *tview.TextArea
+
+	CurlText *tview.TextArea
+
+	HistoryModal     *tview.Flex
+	HistorySearch    *tview.InputField
+	HistoryList      *tview.List
+	HistoryPreview   *tview.TextView
+	HistoryNameInput *tview.InputField
+
+	PromptInput  *tview.InputField
+	ConfirmModal *tview.Modal
+
+	AuthForm *tview.Form
+
+	ServerLogModal  *tview.Flex
+	ServerLogSearch *tview.InputField
+	ServerLogView   *tview.TextView
+
+	BuildErrorModal  *tview.Flex
+	BuildErrorList   *tview.List
+	BuildErrorSource *tview.TextView
+
+	GRPCMethodModal   *tview.Flex
+	GRPCMethodList    *tview.List
+	GRPCMethodPreview *tview.TextView
+
+	GraphQLFieldList *tview.List
 }
 
 func createTuiLayout(cfg *config.Config) *UIComponents {
@@ -39,7 +93,7 @@ func createTuiLayout(cfg *config.Config) *UIComponents {
 
 	components.createServerPathComponent()
 
-	components.createServerStatusComponent()
+	components.createServerTableComponent()
 
 	components.createUrlInputComponent(cfg)
 
@@ -49,22 +103,51 @@ func createTuiLayout(cfg *config.Config) *UIComponents {
 
 	components.createBodyTextComponent()
 
+	components.createVariablesTextComponent()
+
+	components.createAssertionsTextComponent()
+
+	components.createOptionsInputComponent()
+
 	components.createResponseViewComponent()
 
 	components.createNameInputComponent()
 
-	components.createRequestListComponent()
+	components.createRequestTreeComponent()
 
 	components.createFormAndSetup()
 
 	components.createStatusComponent()
 
+	components.createEnvStatusComponent()
+
+	components.createEnvironmentModalComponent()
+
+	components.createCurlImportComponent()
+
+	components.createHistoryModalComponent()
+
+	components.createPromptComponent()
+
+	components.createConfirmComponent()
+
+	components.createAuthFormComponent()
+
+	components.createGRPCMethodModalComponent()
+
+	components.createGraphQLFieldListComponent()
+
+	components.createServerLogModalComponent()
+
+	components.createBuildErrorModalComponent()
+
 	topFlex := tview.NewFlex()
 
 	serverFlex := tview.NewFlex().SetDirection(tview.FlexRow)
 
-	serverFlex.AddItem(components.ServerStatus, 0, 2, false).
+	serverFlex.AddItem(components.ServerTable, 0, 2, false).
 		AddItem(components.ServerPath, 0, 1, false).
+		AddItem(components.EnvStatus, 0, 1, false).
 		AddItem(components.StatusText, 0, 2, false)
 
 	topFlex.AddItem(components.LogoText, 0, 3, false).
@@ -84,7 +167,7 @@ func createTuiLayout(cfg *config.Config) *UIComponents {
 
 	bottomRightFlex := tview.NewFlex().SetDirection(tview.FlexColumn)
 
-	bottomRightFlex.AddItem(components.RequestList, 0, 9, false)
+	bottomRightFlex.AddItem(components.RequestTree, 0, 9, false)
 	bottomRightFlex.AddItem(serverFlex, 0, 5, false)
 
 	rightFlex.AddItem(responseFlex, 0, 8, false).
@@ -94,12 +177,35 @@ func createTuiLayout(cfg *config.Config) *UIComponents {
 		AddItem(rightFlex, 0, 9, false)
 
 	components.MainLayout = tview.NewFlex().SetDirection(tview.FlexRow)
-	components.MainLayout.AddItem(topFlex, 5, 2, false).
+	components.MainLayout.AddItem(topFlex, 6, 2, false).
 		AddItem(bottomFlex, 0, 10, false)
 
+	components.Pages = tview.NewPages().
+		AddPage(mainPage, components.MainLayout, true, true).
+		AddPage(environmentsPage, centeredModal(components.EnvironmentModal, 70, 20), true, false).
+		AddPage(curlImportPage, centeredModal(components.CurlText, 90, 14), true, false).
+		AddPage(historyPage, centeredModal(components.HistoryModal, 130, 32), true, false).
+		AddPage(promptPage, centeredModal(components.PromptInput, 70, 3), true, false).
+		AddPage(confirmPage, components.ConfirmModal, true, false).
+		AddPage(authPage, centeredModal(components.AuthForm, 70, 19), true, false).
+		AddPage(grpcMethodsPage, centeredModal(components.GRPCMethodModal, 110, 24), true, false).
+		AddPage(graphQLFieldPage, centeredModal(components.GraphQLFieldList, 80, 16), true, false).
+		AddPage(serverLogPage, centeredModal(components.ServerLogModal, 130, 32), true, false).
+		AddPage(buildErrorPage, centeredModal(components.BuildErrorModal, 130, 24), true, false)
+
 	return components
 }
 
+func centeredModal(p tview.Primitive, width, height int) tview.Primitive {
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, height, 1, true).
+			AddItem(nil, 0, 1, false), width, 1, true).
+		AddItem(nil, 0, 1, false)
+}
+
 func (components *UIComponents) createUrlInputComponent(cfg *config.Config) {
 	components.URLInput = tview.NewInputField()
 	components.URLInput.SetPlaceholder(fmt.Sprintf("default localhost:%s", cfg.App.DefaultPort)).
@@ -118,15 +224,45 @@ func (components *UIComponents) createNameInputComponent() {
 		SetFieldBackgroundColor(tcell.ColorLightCoral)
 }
 
+// bodyTypes are the options of the Body dropdown, Form and Multipart bodies
+// are edited as one key:value field per line and a File body is the path of
+// the file to send. A GraphQL body is the query, its variables have an
+// editor of their own.
+var bodyTypes = []string{"Text", "JSON", "Form", "Multipart", "File", "GraphQL"}
+
+// graphQLBodyType is the index of GraphQL in bodyTypes.
+const graphQLBodyType = 5
+
+func bodyPlaceholder(index int) string {
+	switch bodyTypes[max(index, 0)] {
+	case "Form":
+		return "key:value, one per line, key:@path sends a file's contents"
+	case "Multipart":
+		return "key:value, one per line, key:@path uploads a file"
+	case "File":
+		return "path of the file to send, typed from its extension"
+	case "GraphQL":
+		return "query { ... }, C-space completes the field being typed"
+	default:
+		return "Your body content here"
+	}
+}
+
 func (components *UIComponents) createFormAndSetup() {
 	form := tview.NewForm().
-		AddDropDown("Method", []string{"GET", "POST", "PUT", "DELETE", "HEAD"}, 0, nil).
+		AddDropDown("Method", []string{"GET", "POST", "PUT", "DELETE", "HEAD", "PATCH", domain.MethodWebSocket, domain.MethodGRPC}, 0, nil).
 		AddFormItem(components.URLInput).
 		AddFormItem(components.NameInput).
 		AddFormItem(components.HeadersText).
 		AddFormItem(components.ParamsText).
-		AddDropDown("Body", []string{"Text", "JSON"}, 0, nil).
-		AddFormItem(components.BodyText)
+		AddDropDown("Auth", authTypeLabels, 0, nil).
+		AddDropDown("Body", bodyTypes, 0, func(_ string, index int) {
+			components.BodyText.SetPlaceholder(bodyPlaceholder(index))
+			components.showVariablesText(index == graphQLBodyType)
+		}).
+		AddFormItem(components.BodyText).
+		AddFormItem(components.AssertionsText).
+		AddFormItem(components.OptionsInput)
 
 	form.SetFieldTextColor(tcell.ColorBlack)
 	form.ClearButtons().SetButtonTextColor(tcell.ColorBlack).
@@ -140,7 +276,12 @@ func (components *UIComponents) createFormAndSetup() {
 		components.MethodDropdown.SetCurrentOption(0)
 	}
 
-	bodyFormItem := form.GetFormItem(5)
+	authFormItem := form.GetFormItem(5)
+	if authDropDown, ok := authFormItem.(*tview.DropDown); ok {
+		components.AuthDropdown = authDropDown
+	}
+
+	bodyFormItem := form.GetFormItem(6)
 	if bodyDropDown, ok := bodyFormItem.(*tview.DropDown); ok {
 		components.BodyType = bodyDropDown
 		components.BodyType.SetCurrentOption(0)
@@ -148,6 +289,27 @@ func (components *UIComponents) createFormAndSetup() {
 
 }
 
+// bodyTextItem is the index of the Body editor in the request form, the
+// Variables editor of a GraphQL body goes right after it.
+const bodyTextItem = 7
+
+// showVariablesText adds or removes the Variables editor of the request
+// form, moving the items after it.
+func (components *UIComponents) showVariablesText(show bool) {
+	form := components.Form
+	if form == nil || (form.GetFormItemIndex("Vars") >= 0) == show {
+		return
+	}
+	for form.GetFormItemCount() > bodyTextItem+1 {
+		form.RemoveFormItem(bodyTextItem + 1)
+	}
+	if show {
+		form.AddFormItem(components.VariablesText)
+	}
+	form.AddFormItem(components.AssertionsText).
+		AddFormItem(components.OptionsInput)
+}
+
 func (components *UIComponents) createLogoComponent() {
 	components.LogoText = tview.NewTextView().SetText(
 		" _ __                      \n( /  )                     \n /--< , , _   _   __ , , , \n/___/(_/_/ (_/ (_(_)(_(_/_ ",
@@ -156,6 +318,7 @@ func (components *UIComponents) createLogoComponent() {
 
 func (components *UIComponents) createStatusComponent() {
 	components.StatusText = tview.NewTextView().SetText("Ready!").
+		SetDynamicColors(true).
 		SetWrap(true).
 		SetTextColor(tcell.ColorBlue)
 }
@@ -163,27 +326,28 @@ func (components *UIComponents) createStatusComponent() {
 func (components *UIComponents) createKeybindingsComponent() {
 	components.BindingsText = tview.NewTextView().
 		SetDynamicColors(true).
-		SetText(`[white]Request form[-]     [blue]|[-][-][white]Response view[-]        [blue]|[-][white]Saved requests list[-][blue]|[-][white]Server[-]
+		SetText(`[white]Request form[-]     [blue]|[-][-][white]Response view[-]        [blue]|[-][white]Saved requests[-]     [blue]|[-][white]Server[-]
 C-f: focus form  [blue]|[-] C-t: focus resp     [blue]|[-] C-l: focus list   [blue]|[-] C-g: focus input
-C-s: send request[blue]|[-] j/k:scroll    ↑↓    [blue]|[-] j/k:navigate  ↑↓  [blue]|[-] C-x: kill server
-C-a: save request[blue]|[-][blue]_____________________|[-] C-o: load request [blue]|[-] C-r: start server
-C-n/p: navigate↑↓  C-u: clear form     [blue]|[-] C-d: del request  [blue]|[-]`).
+C-s: send request[blue]|[-] j/k:scroll C-k:stop [blue]|[-] j/k:nav  C-o:load [blue]|[-] C-x: stop  F2/F3: logs/errs
+C-a: save request[blue]|[-] Tab/1-6 C-w:save    [blue]|[-] n/e: folder/rename[blue]|[-] C-r: start server
+C-n/p: navigate↑↓  C-u: clear form     [blue]|[-] m/c: move/copy    [blue]|[-] C-e: environments
+C-v: import curl   C-y: copy as curl   [blue]|[-] C-d: del  r: tests[blue]|[-] C-b: history`).
 		SetTextColor(tcell.ColorGray)
 }
 
 func (components *UIComponents) createServerPathComponent() {
 	components.ServerPath = tview.NewInputField()
-	components.ServerPath.SetPlaceholder("path/to/server").
+	components.ServerPath.SetPlaceholder("path/to/server.go [port]").
 		SetPlaceholderStyle(tcell.StyleDefault.Background(tcell.ColorGrey)).
 		SetPlaceholderTextColor(tcell.ColorBlue).
 		SetFieldTextColor(tcell.ColorBlack)
 }
 
-func (components *UIComponents) createServerStatusComponent() {
-	components.ServerStatus = tview.NewTextView()
-	components.ServerStatus.SetDynamicColors(true).
-		SetWrap(true).
-		SetText("Server not running")
+func (components *UIComponents) createServerTableComponent() {
+	components.ServerTable = tview.NewTable()
+	components.ServerTable.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorGrey).Foreground(tcell.ColorBlack))
 }
 
 func (components *UIComponents) createHeadersTextComponent() {
@@ -213,6 +377,33 @@ func (components *UIComponents) createBodyTextComponent() {
 		SetFormAttributes(8, tcell.ColorYellow, tcell.ColorBlue, tcell.ColorBlack, tcell.ColorLightCoral)
 }
 
+func (components *UIComponents) createVariablesTextComponent() {
+	components.VariablesText = tview.NewTextArea()
+	components.VariablesText.SetPlaceholder(`{"id": 1}`).
+		SetLabel("Vars").
+		SetPlaceholderStyle(tcell.StyleDefault.Background(tcell.ColorGrey).Foreground(tcell.ColorBlue)).
+		SetSize(3, 0).
+		SetFormAttributes(8, tcell.ColorYellow, tcell.ColorBlue, tcell.ColorBlack, tcell.ColorLightCoral)
+}
+
+func (components *UIComponents) createAssertionsTextComponent() {
+	components.AssertionsText = tview.NewTextArea()
+	components.AssertionsText.SetPlaceholder("status == 200\nheader Content-Type contains json\njson $.id == 1").
+		SetLabel("Asserts").
+		SetPlaceholderStyle(tcell.StyleDefault.Background(tcell.ColorGrey).Foreground(tcell.ColorBlue)).
+		SetSize(3, 0).
+		SetFormAttributes(8, tcell.ColorYellow, tcell.ColorBlue, tcell.ColorBlack, tcell.ColorLightCoral)
+}
+
+func (components *UIComponents) createOptionsInputComponent() {
+	components.OptionsInput = tview.NewInputField()
+	components.OptionsInput.SetPlaceholder("timeout:10s, redirects:off, insecure:true, http:1.1").
+		SetPlaceholderStyle(tcell.StyleDefault.Background(tcell.ColorGrey)).
+		SetPlaceholderTextColor(tcell.ColorBlue).
+		SetLabel("Options ").
+		SetFieldBackgroundColor(tcell.ColorLightCoral)
+}
+
 func (components *UIComponents) createResponseViewComponent() {
 	components.ResponseView = tview.NewTextView()
 	components.ResponseView.SetDynamicColors(true).
@@ -223,12 +414,238 @@ func (components *UIComponents) createResponseViewComponent() {
 		SetTitleColor(tcell.ColorYellow)
 }
 
-func (components *UIComponents) createRequestListComponent() {
-	components.RequestList = tview.NewList()
-	components.RequestList.ShowSecondaryText(false).
+func (components *UIComponents) createRequestTreeComponent() {
+	components.RequestTree = tview.NewTreeView()
+	components.RequestTree.SetGraphicsColor(tcell.ColorBlue).
 		SetBorder(true).
 		SetTitle("Saved Requests").
 		SetTitleAlign(tview.AlignLeft).
 		SetBorderColor(tcell.ColorBlue).
 		SetTitleColor(tcell.ColorYellow)
 }
+
+func (components *UIComponents) createEnvStatusComponent() {
+	components.EnvStatus = tview.NewTextView()
+	components.EnvStatus.SetDynamicColors(true).
+		SetText("[yellow]Env:[-] none")
+}
+
+func (components *UIComponents) createEnvironmentModalComponent() {
+	components.EnvironmentList = tview.NewList()
+	components.EnvironmentList.ShowSecondaryText(false).
+		SetBorder(true).
+		SetTitle("Environments").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.ColorBlue).
+		SetTitleColor(tcell.ColorYellow)
+
+	components.EnvNameInput = tview.NewInputField()
+	components.EnvNameInput.SetPlaceholder("dev, test, ci...").
+		SetPlaceholderStyle(tcell.StyleDefault.Background(tcell.ColorGrey)).
+		SetPlaceholderTextColor(tcell.ColorBlue).
+		SetLabel("Name ").
+		SetFieldBackgroundColor(tcell.ColorLightCoral)
+
+	components.EnvVariablesText = tview.NewTextArea()
+	components.EnvVariablesText.SetPlaceholder("key:value, key:value").
+		SetPlaceholderStyle(tcell.StyleDefault.Background(tcell.ColorGrey).Foreground(tcell.ColorBlue)).
+		SetLabel("Variables").
+		SetSize(10, 0).
+		SetFormAttributes(10, tcell.ColorYellow, tcell.ColorBlue, tcell.ColorBlack, tcell.ColorLightCoral)
+
+	components.EnvironmentForm = tview.NewForm().
+		AddFormItem(components.EnvNameInput).
+		AddFormItem(components.EnvVariablesText)
+	components.EnvironmentForm.SetFieldTextColor(tcell.ColorBlack).
+		SetItemPadding(1).
+		SetBorder(true).
+		SetTitle("Enter: activate | C-a: save | C-d: delete | Esc: close").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.ColorBlue).
+		SetTitleColor(tcell.ColorYellow)
+
+	components.EnvironmentModal = tview.NewFlex().
+		AddItem(components.EnvironmentList, 0, 1, true).
+		AddItem(components.EnvironmentForm, 0, 2, false)
+}
+
+func (components *UIComponents) createCurlImportComponent() {
+	components.CurlText = tview.NewTextArea()
+	components.CurlText.SetPlaceholder("curl -X POST https://example.com/api -H 'Content-Type: application/json' -d '{}'").
+		SetPlaceholderStyle(tcell.StyleDefault.Foreground(tcell.ColorBlue)).
+		SetBorder(true).
+		SetTitle("Paste curl command | C-s: import | Esc: close").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.ColorBlue).
+		SetTitleColor(tcell.ColorYellow)
+}
+
+func (components *UIComponents) createHistoryModalComponent() {
+	components.HistorySearch = tview.NewInputField()
+	components.HistorySearch.SetPlaceholder("filter by method, url or status").
+		SetPlaceholderStyle(tcell.StyleDefault.Background(tcell.ColorGrey)).
+		SetPlaceholderTextColor(tcell.ColorBlue).
+		SetLabel("Search ").
+		SetLabelColor(tcell.ColorYellow).
+		SetFieldTextColor(tcell.ColorBlack).
+		SetFieldBackgroundColor(tcell.ColorLightCoral)
+
+	components.HistoryList = tview.NewList()
+	components.HistoryList.ShowSecondaryText(false).
+		SetBorder(true).
+		SetTitle("History").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.ColorBlue).
+		SetTitleColor(tcell.ColorYellow)
+
+	components.HistoryPreview = tview.NewTextView()
+	components.HistoryPreview.SetDynamicColors(true).
+		SetBorder(true).
+		SetTitle("Entry").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.ColorBlue).
+		SetTitleColor(tcell.ColorYellow)
+
+	components.HistoryNameInput = tview.NewInputField()
+	components.HistoryNameInput.SetPlaceholder("name to save the selected entry as").
+		SetPlaceholderStyle(tcell.StyleDefault.Background(tcell.ColorGrey)).
+		SetPlaceholderTextColor(tcell.ColorBlue).
+		SetLabel("Save as ").
+		SetLabelColor(tcell.ColorYellow).
+		SetFieldTextColor(tcell.ColorBlack).
+		SetFieldBackgroundColor(tcell.ColorLightCoral)
+
+	components.HistoryModal = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(components.HistorySearch, 1, 0, false).
+		AddItem(tview.NewFlex().
+			AddItem(components.HistoryList, 0, 1, true).
+			AddItem(components.HistoryPreview, 0, 1, false), 0, 1, true).
+		AddItem(components.HistoryNameInput, 1, 0, false)
+	components.HistoryModal.SetBorder(true).
+		SetTitle("Enter: re-send | C-o: load | C-a: save as | C-d: delete | C-f/C-l/C-n: search/list/name | Esc: close").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.ColorBlue).
+		SetTitleColor(tcell.ColorYellow)
+}
+
+func (components *UIComponents) createPromptComponent() {
+	components.PromptInput = tview.NewInputField()
+	components.PromptInput.SetFieldTextColor(tcell.ColorBlack).
+		SetFieldBackgroundColor(tcell.ColorLightCoral).
+		SetBorder(true).
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.ColorBlue).
+		SetTitleColor(tcell.ColorYellow)
+}
+
+func (components *UIComponents) createConfirmComponent() {
+	components.ConfirmModal = tview.NewModal().
+		AddButtons([]string{"Delete", "Cancel"}).
+		SetTextColor(tcell.ColorYellow).
+		SetBackgroundColor(tcell.ColorBlack)
+	components.ConfirmModal.SetBorderColor(tcell.ColorBlue)
+}
+
+func (components *UIComponents) createAuthFormComponent() {
+	components.AuthForm = tview.NewForm()
+	components.AuthForm.SetFieldTextColor(tcell.ColorBlack).
+		SetFieldBackgroundColor(tcell.ColorLightCoral).
+		SetLabelColor(tcell.ColorYellow).
+		SetItemPadding(1).
+		SetBorder(true).
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.ColorBlue).
+		SetTitleColor(tcell.ColorYellow)
+}
+
+func (components *UIComponents) createGRPCMethodModalComponent() {
+	components.GRPCMethodList = tview.NewList()
+	components.GRPCMethodList.SetSecondaryTextColor(tcell.ColorGray).
+		SetBorder(true).
+		SetTitle("Methods").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.ColorBlue).
+		SetTitleColor(tcell.ColorYellow)
+
+	components.GRPCMethodPreview = tview.NewTextView()
+	components.GRPCMethodPreview.SetDynamicColors(true).
+		SetBorder(true).
+		SetTitle("Request message").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.ColorBlue).
+		SetTitleColor(tcell.ColorYellow)
+
+	components.GRPCMethodModal = tview.NewFlex().
+		AddItem(components.GRPCMethodList, 0, 1, true).
+		AddItem(components.GRPCMethodPreview, 0, 1, false)
+	components.GRPCMethodModal.SetBorder(true).
+		SetTitle("Enter: use method | Esc: close").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.ColorBlue).
+		SetTitleColor(tcell.ColorYellow)
+}
+
+func (components *UIComponents) createGraphQLFieldListComponent() {
+	components.GraphQLFieldList = tview.NewList()
+	components.GraphQLFieldList.SetSecondaryTextColor(tcell.ColorGray).
+		SetBorder(true).
+		SetTitle("Fields | Enter: insert | Esc: close").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.ColorBlue).
+		SetTitleColor(tcell.ColorYellow)
+}
+
+func (components *UIComponents) createServerLogModalComponent() {
+	components.ServerLogSearch = tview.NewInputField()
+	components.ServerLogSearch.SetPlaceholder("filter lines").
+		SetPlaceholderStyle(tcell.StyleDefault.Background(tcell.ColorGrey)).
+		SetPlaceholderTextColor(tcell.ColorBlue).
+		SetLabel("Search ").
+		SetLabelColor(tcell.ColorYellow).
+		SetFieldTextColor(tcell.ColorBlack).
+		SetFieldBackgroundColor(tcell.ColorLightCoral)
+
+	components.ServerLogView = tview.NewTextView()
+	components.ServerLogView.SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(true).
+		SetBorder(true).
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.ColorBlue).
+		SetTitleColor(tcell.ColorYellow)
+
+	components.ServerLogModal = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(components.ServerLogSearch, 1, 0, false).
+		AddItem(components.ServerLogView, 0, 1, true)
+	components.ServerLogModal.SetBorder(true).
+		SetTitle("j/k: scroll | Tab: next server | C-f/C-l: search/log | Esc: close").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.ColorBlue).
+		SetTitleColor(tcell.ColorYellow)
+}
+
+func (components *UIComponents) createBuildErrorModalComponent() {
+	components.BuildErrorList = tview.NewList()
+	components.BuildErrorList.SetSecondaryTextColor(tcell.ColorGray).
+		SetBorder(true).
+		SetTitle("Errors").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.ColorBlue).
+		SetTitleColor(tcell.ColorYellow)
+
+	components.BuildErrorSource = tview.NewTextView()
+	components.BuildErrorSource.SetDynamicColors(true).
+		SetBorder(true).
+		SetTitle("Source").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.ColorBlue).
+		SetTitleColor(tcell.ColorYellow)
+
+	components.BuildErrorModal = tview.NewFlex().
+		AddItem(components.BuildErrorList, 0, 1, true).
+		AddItem(components.BuildErrorSource, 0, 1, false)
+	components.BuildErrorModal.SetBorder(true).
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.ColorBlue).
+		SetTitleColor(tcell.ColorYellow)
+}
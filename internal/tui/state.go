@@ -2,13 +2,31 @@ package tui
 
 import (
 	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/ManoloEsS/burrow/internal/service"
 	"github.com/rivo/tview"
 )
 
 type UIState struct {
-	CurrentRequest        *domain.Request
-	SavedRequests         []*domain.Request
-	CurrentResponse       *domain.Response
+	CurrentRequest       *domain.Request
+	Auth                 *domain.Auth
+	SavedRequests        []*domain.Request
+	Collections          []*domain.Collection
+	CollectionTree       *domain.CollectionNode
+	CollapsedCollections map[int64]bool
+	CurrentResponse      *domain.Response
+	CurrentResults       []domain.AssertionResult
+	WSScript             []domain.WSMessage
+	GRPCMethods          []domain.GRPCMethod
+	GraphQLSchema        *domain.GraphQLSchema
+	GraphQLSchemaURL     string
+	ResponseTab          int
+	Environments         []*domain.Environment
+	HistoryEntries       []*domain.HistoryEntry
+	// ServerLogName is the server shown in the log pane
+	ServerLogName string
+	// RecentServers are the inputs of the servers started last
+	RecentServers         []string
+	BuildDiagnostics      []service.Diagnostic
 	CurrentFormFocusIndex int
 	CurrentFocused        tview.Primitive
 }
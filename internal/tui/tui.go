@@ -1,11 +1,14 @@
 package tui
 
 import (
+	"context"
 	"log"
 	"os"
+	"sync"
 
 	"github.com/ManoloEsS/burrow/internal/config"
 	"github.com/ManoloEsS/burrow/internal/service"
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
@@ -18,6 +21,16 @@ type Tui struct {
 	Config              *config.Config
 	logger              *log.Logger
 	ServerUpdateChannel chan service.UIEvent
+	screen              tcell.Screen
+
+	// cancel handle of the request or test run in flight
+	requestMu     sync.Mutex
+	cancelRequest context.CancelFunc
+	requestSeq    int
+	ws            *wsSession
+
+	// set while the auth dropdown is changed from code rather than by the user
+	settingAuthType bool
 }
 
 func NewTui(cfg *config.Config) *Tui {
@@ -44,13 +57,32 @@ func NewTui(cfg *config.Config) *Tui {
 func (tui *Tui) Initialize() error {
 	tui.Components = createTuiLayout(tui.Config)
 	tui.setupKeybindings()
+	tui.setupEnvironmentKeybindings()
+	tui.setupCurlKeybindings()
+	tui.setupHistoryKeybindings()
+	tui.setupCollectionKeybindings()
+	tui.setupAuthKeybindings()
+	tui.setupGRPCKeybindings()
+	tui.setupGraphQLKeybindings()
+	tui.setupServerKeybindings()
+	tui.setupServerLogKeybindings()
+	tui.setupBuildErrorKeybindings()
 	tui.loadSavedRequests()
+	tui.updateEnvironmentStatus()
 	tui.focusForm()
 	go tui.serverUpdateListener()
+	go tui.serverLogListener()
+
+	tui.Ui.SetBeforeDrawFunc(func(screen tcell.Screen) bool {
+		tui.screen = screen
+		return false
+	})
 
 	return nil
 }
 
 func (tui *Tui) Start() error {
-	return tui.Ui.SetRoot(tui.Components.MainLayout, true).EnableMouse(true).Run()
+	// a large body spilled to disk goes away with the app
+	defer func() { _ = tui.State.CurrentResponse.RemoveBodyFile() }()
+	return tui.Ui.SetRoot(tui.Components.Pages, true).EnableMouse(true).EnablePaste(true).Run()
 }
@@ -0,0 +1,161 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// authTypes lines up with the options of the Auth dropdown; "" is no auth.
+var (
+	authTypes      = []string{"", domain.AuthBasic, domain.AuthBearer, domain.AuthAPIKey, domain.AuthOAuth2, domain.AuthDigest}
+	authTypeLabels = []string{"None", "Basic", "Bearer", "API Key", "OAuth2", "Digest"}
+)
+
+var (
+	apiKeyLocations = []string{domain.APIKeyInHeader, domain.APIKeyInQuery}
+	oauth2Grants    = []string{domain.GrantClientCredentials, domain.GrantPassword}
+)
+
+const (
+	authUsernameLabel     = "Username "
+	authPasswordLabel     = "Password "
+	authTokenLabel        = "Token "
+	authKeyLabel          = "Key "
+	authValueLabel        = "Value "
+	authInLabel           = "In "
+	authGrantLabel        = "Grant "
+	authTokenURLLabel     = "Token URL "
+	authClientIDLabel     = "Client ID "
+	authClientSecretLabel = "Client secret "
+	authScopesLabel       = "Scopes "
+)
+
+func (tui *Tui) setupAuthKeybindings() {
+	tui.Components.AuthDropdown.SetSelectedFunc(func(_ string, index int) {
+		if tui.settingAuthType || index < 0 {
+			return
+		}
+		if index == 0 {
+			tui.State.Auth = nil
+			tui.Components.StatusText.SetText("Auth removed")
+			return
+		}
+		tui.showAuth(authTypes[index])
+	})
+
+	tui.Components.AuthForm.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			tui.hideAuth()
+			return nil
+		case tcell.KeyCtrlS:
+			tui.saveAuth()
+			return nil
+		default:
+			return event
+		}
+	})
+}
+
+// showAuth opens the auth form for authType, filled in with the current auth
+// when it is of the same type.
+func (tui *Tui) showAuth(authType string) {
+	auth := tui.State.Auth
+	if auth == nil || auth.Type != authType {
+		auth = &domain.Auth{Type: authType}
+	}
+
+	tui.buildAuthForm(auth)
+	tui.Components.Pages.ShowPage(authPage)
+	tui.Ui.SetFocus(tui.Components.AuthForm)
+}
+
+// hideAuth closes the auth form, putting the dropdown back to the auth in use
+// when the form was left without saving.
+func (tui *Tui) hideAuth() {
+	tui.Components.Pages.HidePage(authPage)
+	tui.setAuth(tui.State.Auth)
+	tui.restoreFocus()
+}
+
+func (tui *Tui) setAuth(auth *domain.Auth) {
+	tui.State.Auth = auth
+
+	index := 0
+	if auth != nil {
+		index = max(slices.Index(authTypes, auth.Type), 0)
+	}
+	tui.settingAuthType = true
+	tui.Components.AuthDropdown.SetCurrentOption(index)
+	tui.settingAuthType = false
+}
+
+func (tui *Tui) buildAuthForm(auth *domain.Auth) {
+	form := tui.Components.AuthForm
+	form.Clear(true)
+	form.SetTitle(fmt.Sprintf("%s auth | C-s: save | Esc: close", authTypeLabels[slices.Index(authTypes, auth.Type)]))
+
+	switch auth.Type {
+	case domain.AuthBasic, domain.AuthDigest:
+		form.AddInputField(authUsernameLabel, auth.Username, 0, nil, nil).
+			AddPasswordField(authPasswordLabel, auth.Password, 0, '*', nil)
+	case domain.AuthBearer:
+		form.AddPasswordField(authTokenLabel, auth.Token, 0, '*', nil)
+	case domain.AuthAPIKey:
+		form.AddInputField(authKeyLabel, auth.Key, 0, nil, nil).
+			AddPasswordField(authValueLabel, auth.Value, 0, '*', nil).
+			AddDropDown(authInLabel, apiKeyLocations, max(slices.Index(apiKeyLocations, auth.In), 0), nil)
+	case domain.AuthOAuth2:
+		form.AddDropDown(authGrantLabel, oauth2Grants, max(slices.Index(oauth2Grants, auth.Grant), 0), nil).
+			AddInputField(authTokenURLLabel, auth.TokenURL, 0, nil, nil).
+			AddInputField(authClientIDLabel, auth.ClientID, 0, nil, nil).
+			AddPasswordField(authClientSecretLabel, auth.ClientSecret, 0, '*', nil).
+			AddInputField(authScopesLabel, auth.Scopes, 0, nil, nil).
+			AddInputField(authUsernameLabel, auth.Username, 0, nil, nil).
+			AddPasswordField(authPasswordLabel, auth.Password, 0, '*', nil)
+	}
+}
+
+// saveAuth reads the auth form for the type picked in the dropdown. The form
+// stays open while the auth is invalid.
+func (tui *Tui) saveAuth() {
+	field := func(label string) string {
+		switch item := tui.Components.AuthForm.GetFormItemByLabel(label).(type) {
+		case *tview.InputField:
+			return item.GetText()
+		case *tview.DropDown:
+			_, option := item.GetCurrentOption()
+			return option
+		default:
+			return ""
+		}
+	}
+
+	index, _ := tui.Components.AuthDropdown.GetCurrentOption()
+	auth := &domain.Auth{
+		Type:         authTypes[index],
+		Username:     field(authUsernameLabel),
+		Password:     field(authPasswordLabel),
+		Token:        field(authTokenLabel),
+		Key:          field(authKeyLabel),
+		Value:        field(authValueLabel),
+		In:           field(authInLabel),
+		Grant:        field(authGrantLabel),
+		TokenURL:     field(authTokenURLLabel),
+		ClientID:     field(authClientIDLabel),
+		ClientSecret: field(authClientSecretLabel),
+		Scopes:       field(authScopesLabel),
+	}
+	if err := auth.Validate(); err != nil {
+		tui.Components.StatusText.SetText(fmt.Sprintf("[red]Error: %s[-]", err.Error()))
+		return
+	}
+
+	tui.State.Auth = auth
+	tui.hideAuth()
+	tui.Components.StatusText.SetText("Auth: " + auth.Summary())
+}
@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ManoloEsS/burrow/internal/service"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// buildErrorContext is how many lines are shown around an error.
+const buildErrorContext = 4
+
+func (tui *Tui) setupBuildErrorKeybindings() {
+	tui.Components.BuildErrorList.SetChangedFunc(func(index int, _ string, _ string, _ rune) {
+		tui.previewBuildError(index)
+	})
+
+	tui.Components.BuildErrorModal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			tui.hideBuildErrors()
+			return nil
+		}
+		return event
+	})
+}
+
+// showBuildErrors opens the compiler errors of the server selected in the
+// table, or of the first server whose build failed.
+func (tui *Tui) showBuildErrors() {
+	var failed *service.ServerInfo
+	row, _ := tui.Components.ServerTable.GetSelection()
+	selected := tui.serverAt(row)
+	for _, server := range tui.ServerService.Servers() {
+		if len(server.Diagnostics) == 0 {
+			continue
+		}
+		if failed == nil || server.Name == selected {
+			failed = &server
+		}
+	}
+	if failed == nil {
+		tui.Components.StatusText.SetText("No build errors")
+		return
+	}
+
+	tui.State.BuildDiagnostics = failed.Diagnostics
+	dir := filepath.Dir(failed.Path)
+	list := tui.Components.BuildErrorList
+	list.Clear()
+	for _, diagnostic := range failed.Diagnostics {
+		message, _, _ := strings.Cut(diagnostic.Message, "\n")
+		list.AddItem(tview.Escape(diagnosticPlace(diagnostic, dir)), tview.Escape(message), 0, nil)
+	}
+	tui.previewBuildError(0)
+
+	tui.Components.BuildErrorModal.SetTitle(fmt.Sprintf("Build errors: %s (%d) | Esc: close", failed.Name, len(failed.Diagnostics)))
+	tui.Components.Pages.ShowPage(buildErrorPage)
+	tui.Ui.SetFocus(list)
+}
+
+func (tui *Tui) hasBuildErrors(name string) bool {
+	for _, server := range tui.ServerService.Servers() {
+		if server.Name == name {
+			return len(server.Diagnostics) > 0
+		}
+	}
+	return false
+}
+
+func (tui *Tui) hideBuildErrors() {
+	tui.Components.Pages.HidePage(buildErrorPage)
+	tui.restoreFocus()
+}
+
+// previewBuildError shows the source around an error with the whole
+// message below it.
+func (tui *Tui) previewBuildError(index int) {
+	view := tui.Components.BuildErrorSource
+	if index < 0 || index >= len(tui.State.BuildDiagnostics) {
+		view.SetText("")
+		return
+	}
+	diagnostic := tui.State.BuildDiagnostics[index]
+
+	var builder strings.Builder
+	if source, err := os.ReadFile(diagnostic.File); err == nil {
+		builder.WriteString(sourceExcerpt(string(source), diagnostic.Line, diagnostic.Column))
+		builder.WriteString("\n")
+	}
+	fmt.Fprintf(&builder, "[red]%s[-]", tview.Escape(diagnostic.Message))
+	view.SetTitle(filepath.Base(diagnostic.File))
+	view.SetText(builder.String())
+	view.ScrollToBeginning()
+}
+
+// diagnosticPlace is where an error is, relative to the directory of the
+// server when it is in it.
+func diagnosticPlace(diagnostic service.Diagnostic, dir string) string {
+	file := diagnostic.File
+	if rel, err := filepath.Rel(dir, file); err == nil && !strings.HasPrefix(rel, "..") {
+		file = rel
+	}
+	if diagnostic.Column == 0 {
+		return fmt.Sprintf("%s:%d", file, diagnostic.Line)
+	}
+	return fmt.Sprintf("%s:%d:%d", file, diagnostic.Line, diagnostic.Column)
+}
+
+// sourceExcerpt numbers the lines around line, marking it and pointing at
+// column under it.
+func sourceExcerpt(source string, line, column int) string {
+	lines := strings.Split(source, "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	var builder strings.Builder
+	width := len(fmt.Sprint(min(line+buildErrorContext, len(lines))))
+	for n := max(line-buildErrorContext, 1); n <= min(line+buildErrorContext, len(lines)); n++ {
+		text := tview.Escape(strings.ReplaceAll(lines[n-1], "\t", "    "))
+		if n != line {
+			fmt.Fprintf(&builder, "[gray]%*d[-] %s\n", width, n, text)
+			continue
+		}
+		fmt.Fprintf(&builder, "[red]%*d[-] [yellow]%s[-]\n", width, n, text)
+		if column > 0 {
+			// tabs before the column are shown as four spaces
+			prefix := lines[n-1][:min(column-1, len(lines[n-1]))]
+			offset := len(prefix) + 3*strings.Count(prefix, "\t")
+			fmt.Fprintf(&builder, "%s [red]^[-]\n", strings.Repeat(" ", width+offset))
+		}
+	}
+	return builder.String()
+}
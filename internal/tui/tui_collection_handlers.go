@@ -0,0 +1,322 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func (tui *Tui) setupCollectionKeybindings() {
+	tui.Components.RequestTree.SetSelectedFunc(func(node *tview.TreeNode) {
+		switch ref := node.GetReference().(type) {
+		case *domain.CollectionNode:
+			if ref.Collection == nil {
+				return
+			}
+			node.SetExpanded(!node.IsExpanded())
+			tui.State.CollapsedCollections[ref.ID()] = !node.IsExpanded()
+		case *domain.Request:
+			go tui.handleLoadRequest()
+		}
+	})
+
+	tui.Components.PromptInput.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			tui.hidePrompt()
+			return nil
+		}
+		return event
+	})
+}
+
+func (tui *Tui) renderRequestTree() {
+	selected := treeNodeKey(tui.Components.RequestTree.GetCurrentNode())
+
+	root := tview.NewTreeNode("/").
+		SetReference(tui.State.CollectionTree).
+		SetColor(tcell.ColorYellow)
+	tui.addTreeChildren(root, tui.State.CollectionTree)
+
+	tui.Components.RequestTree.SetRoot(root).SetCurrentNode(root)
+	tui.selectTreeNode(selected)
+}
+
+func (tui *Tui) selectTreeNode(key string) {
+	if key == "" {
+		return
+	}
+	tui.Components.RequestTree.GetRoot().Walk(func(node, _ *tview.TreeNode) bool {
+		if treeNodeKey(node) == key {
+			tui.Components.RequestTree.SetCurrentNode(node)
+		}
+		return true
+	})
+}
+
+func (tui *Tui) addTreeChildren(parent *tview.TreeNode, collection *domain.CollectionNode) {
+	for _, child := range collection.Children {
+		item := tview.NewTreeNode(tview.Escape(child.Collection.Name + "/")).
+			SetReference(child).
+			SetColor(tcell.ColorYellow).
+			SetExpanded(!tui.State.CollapsedCollections[child.ID()])
+		tui.addTreeChildren(item, child)
+		parent.AddChild(item)
+	}
+
+	for _, req := range collection.Requests {
+		name := req.Name
+		if name == "" {
+			name = "unnamed"
+		}
+		text := fmt.Sprintf("%-6s %s  [gray]%s[-]", req.Method, tview.Escape(name), tview.Escape(req.URL))
+		parent.AddChild(tview.NewTreeNode(text).SetReference(req))
+	}
+}
+
+// treeNodeKey identifies a node across reloads of the tree.
+func treeNodeKey(node *tview.TreeNode) string {
+	if node == nil {
+		return ""
+	}
+	switch ref := node.GetReference().(type) {
+	case *domain.CollectionNode:
+		return collectionKey(ref.ID())
+	case *domain.Request:
+		return requestKey(ref.Name)
+	default:
+		return ""
+	}
+}
+
+func collectionKey(id int64) string {
+	return fmt.Sprintf("collection:%d", id)
+}
+
+func requestKey(name string) string {
+	return "request:" + name
+}
+
+// selectedTreeItem returns either the collection or the request under the
+// cursor of the saved requests tree.
+func (tui *Tui) selectedTreeItem() (*domain.CollectionNode, *domain.Request) {
+	node := tui.Components.RequestTree.GetCurrentNode()
+	if node == nil {
+		return nil, nil
+	}
+	switch ref := node.GetReference().(type) {
+	case *domain.CollectionNode:
+		return ref, nil
+	case *domain.Request:
+		return nil, ref
+	default:
+		return nil, nil
+	}
+}
+
+// selectedCollection is the collection under the cursor, or the one holding
+// the request under the cursor.
+func (tui *Tui) selectedCollection() *domain.CollectionNode {
+	if tui.State.CollectionTree == nil {
+		return nil
+	}
+
+	collection, req := tui.selectedTreeItem()
+	if collection != nil {
+		return collection
+	}
+	if req != nil {
+		if node := tui.State.CollectionTree.Node(req.CollectionID); node != nil {
+			return node
+		}
+	}
+	return tui.State.CollectionTree
+}
+
+func (tui *Tui) showPrompt(title, text string, completions []string, onDone func(string)) {
+	input := tui.Components.PromptInput
+	input.SetText(text).SetTitle(title)
+	input.SetAutocompleteFunc(func(current string) []string {
+		var entries []string
+		for _, c := range completions {
+			if strings.HasPrefix(c, strings.ToLower(current)) {
+				entries = append(entries, c)
+			}
+		}
+		return entries
+	})
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			return
+		}
+		value := input.GetText()
+		tui.hidePrompt()
+		go onDone(value)
+	})
+
+	tui.Components.Pages.ShowPage(promptPage)
+	tui.Ui.SetFocus(input)
+}
+
+func (tui *Tui) hidePrompt() {
+	tui.Components.Pages.HidePage(promptPage)
+	tui.restoreFocus()
+}
+
+func (tui *Tui) showConfirm(text string, onConfirm func()) {
+	tui.Components.ConfirmModal.SetText(text).
+		SetFocus(1).
+		SetDoneFunc(func(_ int, label string) {
+			tui.Components.Pages.HidePage(confirmPage)
+			tui.restoreFocus()
+			if label == "Delete" {
+				go onConfirm()
+			}
+		})
+
+	tui.Components.Pages.ShowPage(confirmPage)
+	tui.Ui.SetFocus(tui.Components.ConfirmModal)
+}
+
+func (tui *Tui) handleNewCollection() {
+	parent := tui.selectedCollection()
+	if parent == nil {
+		return
+	}
+
+	title := "New collection"
+	if parent.Collection != nil {
+		title = fmt.Sprintf("New collection in %s", parent.Path())
+	}
+
+	tui.Ui.QueueUpdateDraw(func() {
+		tui.showPrompt(title, "", nil, func(name string) {
+			collection, err := tui.HttpService.CreateCollection(name, parent.ID())
+			if err != nil {
+				tui.showTreeError(err)
+				return
+			}
+			tui.reloadTreeWithStatus(fmt.Sprintf("Collection %s created", collection.Name), collectionKey(collection.ID))
+		})
+	})
+}
+
+func (tui *Tui) handleRenameTreeItem() {
+	collection, req := tui.selectedTreeItem()
+
+	switch {
+	case req != nil:
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.showPrompt(fmt.Sprintf("Rename %s", req.Name), req.Name, nil, func(name string) {
+				if err := tui.HttpService.RenameRequest(req.Name, name); err != nil {
+					tui.showTreeError(err)
+					return
+				}
+				tui.reloadTreeWithStatus("Request renamed", requestKey(strings.ToLower(name)))
+			})
+		})
+	case collection != nil && collection.Collection != nil:
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.showPrompt(fmt.Sprintf("Rename %s", collection.Path()), collection.Collection.Name, nil, func(name string) {
+				if err := tui.HttpService.RenameCollection(collection.ID(), name); err != nil {
+					tui.showTreeError(err)
+					return
+				}
+				tui.reloadTreeWithStatus("Collection renamed", "")
+			})
+		})
+	default:
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.StatusText.SetText("Select a collection or request to rename")
+		})
+	}
+}
+
+func (tui *Tui) handleMoveTreeItem() {
+	collection, req := tui.selectedTreeItem()
+	if req == nil && (collection == nil || collection.Collection == nil) {
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.StatusText.SetText("Select a collection or request to move")
+		})
+		return
+	}
+
+	tree := tui.State.CollectionTree
+	name := ""
+	if req != nil {
+		name = req.Name
+	} else {
+		name = collection.Path()
+	}
+
+	tui.Ui.QueueUpdateDraw(func() {
+		tui.showPrompt(fmt.Sprintf("Move %s to (empty for top level)", name), "", tree.Paths(), func(path string) {
+			target := tree.Find(path)
+			if target == nil {
+				tui.showTreeError(fmt.Errorf("collection %q not found", path))
+				return
+			}
+
+			var err error
+			if req != nil {
+				err = tui.HttpService.MoveRequest(req.Name, target.ID())
+			} else {
+				err = tui.HttpService.MoveCollection(collection.ID(), target.ID())
+			}
+			if err != nil {
+				tui.showTreeError(err)
+				return
+			}
+			tui.reloadTreeWithStatus(fmt.Sprintf("Moved %s", name), "")
+		})
+	})
+}
+
+func (tui *Tui) handleDuplicateRequest() {
+	_, req := tui.selectedTreeItem()
+	if req == nil {
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.StatusText.SetText("Select a request to duplicate")
+		})
+		return
+	}
+
+	dup, err := tui.HttpService.DuplicateRequest(req.Name)
+	if err != nil {
+		tui.showTreeError(err)
+		return
+	}
+	tui.reloadTreeWithStatus(fmt.Sprintf("Duplicated as %s", dup.Name), requestKey(dup.Name))
+}
+
+func (tui *Tui) handleDeleteCollection(collection *domain.CollectionNode) {
+	text := fmt.Sprintf("Delete collection %s and the %d requests in it?", collection.Path(), len(collection.AllRequests()))
+
+	tui.Ui.QueueUpdateDraw(func() {
+		tui.showConfirm(text, func() {
+			if err := tui.HttpService.DeleteCollection(collection.ID()); err != nil {
+				tui.showTreeError(err)
+				return
+			}
+			tui.reloadTreeWithStatus("Collection deleted", "")
+		})
+	})
+}
+
+// reloadTreeWithStatus refreshes the saved requests tree, moving the cursor
+// to the node identified by selectKey when it is given.
+func (tui *Tui) reloadTreeWithStatus(status, selectKey string) {
+	tui.Ui.QueueUpdateDraw(func() {
+		tui.loadSavedRequests()
+		tui.selectTreeNode(selectKey)
+		tui.Components.StatusText.SetText(status)
+	})
+}
+
+func (tui *Tui) showTreeError(err error) {
+	tui.Ui.QueueUpdateDraw(func() {
+		tui.Components.StatusText.SetText(fmt.Sprintf("[red]Error: %s[-]", err.Error()))
+	})
+}
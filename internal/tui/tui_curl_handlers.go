@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func (tui *Tui) setupCurlKeybindings() {
+	tui.Components.CurlText.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			tui.hideCurlImport()
+			return nil
+		case tcell.KeyCtrlS:
+			go tui.handleImportCurl()
+			return nil
+		default:
+			return event
+		}
+	})
+}
+
+func (tui *Tui) showCurlImport() {
+	tui.Components.CurlText.SetText("", false)
+	tui.Components.Pages.ShowPage(curlImportPage)
+	tui.Ui.SetFocus(tui.Components.CurlText)
+}
+
+func (tui *Tui) hideCurlImport() {
+	tui.Components.Pages.HidePage(curlImportPage)
+	tui.restoreFocus()
+}
+
+func (tui *Tui) handleImportCurl() {
+	req, err := domain.ParseCurl(tui.Components.CurlText.GetText(), tui.Config)
+	if err != nil {
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.StatusText.SetText(fmt.Sprintf("[red]Error: %s[-]", err.Error()))
+		})
+		return
+	}
+
+	tui.Ui.QueueUpdateDraw(func() {
+		tui.populateRequest(req)
+		tui.hideCurlImport()
+		tui.Components.StatusText.SetText("curl command imported")
+	})
+}
+
+func (tui *Tui) handleCopyCurl() {
+	err := tui.getCurrentRequest()
+	if err != nil {
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.StatusText.SetText(fmt.Sprintf("[red]Error: %s[-]", err.Error()))
+		})
+		return
+	}
+
+	command := tui.State.CurrentRequest.ToCurl()
+
+	// the clipboard gets the real credentials, the screen only masked ones
+	masked := tui.State.CurrentRequest.Clone()
+	masked.Auth = masked.Auth.Masked()
+	display := masked.ToCurl()
+
+	tui.Ui.QueueUpdateDraw(func() {
+		// the clipboard is set through OSC 52, so the command is also shown
+		// for terminals that do not support it
+		if tui.screen != nil {
+			tui.screen.SetClipboard([]byte(command))
+		}
+		tui.Components.ResponseView.SetText(tview.Escape(display)).ScrollToBeginning()
+		tui.Components.StatusText.SetText("Copied request as curl")
+	})
+}
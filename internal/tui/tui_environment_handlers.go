@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/gdamore/tcell/v2"
+)
+
+func (tui *Tui) setupEnvironmentKeybindings() {
+	tui.Components.EnvironmentList.SetChangedFunc(func(index int, _ string, _ string, _ rune) {
+		tui.populateEnvironment(tui.environmentAt(index))
+	})
+
+	tui.Components.EnvironmentList.SetSelectedFunc(func(index int, _ string, _ string, _ rune) {
+		go tui.handleActivateEnvironment(tui.environmentAt(index))
+	})
+
+	tui.Components.EnvironmentModal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			tui.hideEnvironments()
+			return nil
+		case tcell.KeyCtrlA:
+			go tui.handleSaveEnvironment()
+			return nil
+		case tcell.KeyCtrlD:
+			if tui.Ui.GetFocus() == tui.Components.EnvironmentList {
+				go tui.handleDeleteEnvironment()
+			}
+			return nil
+		case tcell.KeyCtrlL:
+			tui.Ui.SetFocus(tui.Components.EnvironmentList)
+			return nil
+		case tcell.KeyCtrlF:
+			tui.Ui.SetFocus(tui.Components.EnvNameInput)
+			return nil
+		default:
+			return event
+		}
+	})
+}
+
+func (tui *Tui) showEnvironments() {
+	tui.loadEnvironments()
+	tui.Components.Pages.ShowPage(environmentsPage)
+	tui.Ui.SetFocus(tui.Components.EnvironmentList)
+}
+
+func (tui *Tui) hideEnvironments() {
+	tui.Components.Pages.HidePage(environmentsPage)
+	tui.restoreFocus()
+}
+
+// environmentAt maps a list index to an environment; index 0 is "no environment".
+func (tui *Tui) environmentAt(index int) *domain.Environment {
+	if index < 1 || index > len(tui.State.Environments) {
+		return nil
+	}
+	return tui.State.Environments[index-1]
+}
+
+func (tui *Tui) loadEnvironments() {
+	if tui.HttpService == nil {
+		return
+	}
+	envs, err := tui.HttpService.GetEnvironments()
+	if err != nil {
+		log.Printf("Error loading environments: %v", err)
+		return
+	}
+
+	tui.State.Environments = envs
+
+	active := tui.HttpService.GetActiveEnvironment()
+
+	tui.Components.EnvironmentList.Clear()
+	tui.Components.EnvironmentList.AddItem("(none)", "", 0, nil)
+	for _, env := range envs {
+		itemText := env.Name
+		if active != nil && active.Name == env.Name {
+			itemText = fmt.Sprintf("%s [green](active)[-]", env.Name)
+		}
+		tui.Components.EnvironmentList.AddItem(itemText, "", 0, nil)
+	}
+}
+
+func (tui *Tui) populateEnvironment(env *domain.Environment) {
+	if env == nil {
+		tui.Components.EnvNameInput.SetText("")
+		tui.Components.EnvVariablesText.SetText("", true)
+		return
+	}
+
+	tui.Components.EnvNameInput.SetText(env.Name)
+	tui.Components.EnvVariablesText.SetText(variablesToString(env.Variables), true)
+}
+
+func (tui *Tui) handleSaveEnvironment() {
+	env := domain.NewEnvironment()
+	err := env.BuildEnvironment(tui.Components.EnvNameInput.GetText(), tui.Components.EnvVariablesText.GetText())
+	if err != nil {
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.StatusText.SetText(fmt.Sprintf("[red]Error: %s[-]", err.Error()))
+		})
+		return
+	}
+
+	err = tui.HttpService.SaveEnvironment(env)
+	if err != nil {
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.StatusText.SetText(fmt.Sprintf("Error: %s", err))
+		})
+		return
+	}
+
+	tui.Ui.QueueUpdateDraw(func() {
+		tui.loadEnvironments()
+		tui.updateEnvironmentStatus()
+		tui.Components.StatusText.SetText(fmt.Sprintf("Environment %s saved", env.Name))
+	})
+}
+
+func (tui *Tui) handleDeleteEnvironment() {
+	env := tui.environmentAt(tui.Components.EnvironmentList.GetCurrentItem())
+	if env == nil {
+		return
+	}
+
+	err := tui.HttpService.DeleteEnvironment(env.Name)
+	if err != nil {
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.StatusText.SetText(fmt.Sprintf("Error %v", err))
+		})
+		return
+	}
+
+	tui.Ui.QueueUpdateDraw(func() {
+		tui.loadEnvironments()
+		tui.updateEnvironmentStatus()
+		tui.Components.StatusText.SetText("Environment deleted")
+	})
+}
+
+func (tui *Tui) handleActivateEnvironment(env *domain.Environment) {
+	name := ""
+	if env != nil {
+		name = env.Name
+	}
+
+	err := tui.HttpService.SetActiveEnvironment(name)
+	if err != nil {
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.StatusText.SetText(fmt.Sprintf("[red]Error: %s[-]", err.Error()))
+		})
+		return
+	}
+
+	tui.Ui.QueueUpdateDraw(func() {
+		tui.updateEnvironmentStatus()
+		tui.hideEnvironments()
+	})
+}
+
+func (tui *Tui) updateEnvironmentStatus() {
+	if tui.HttpService == nil {
+		return
+	}
+
+	active := tui.HttpService.GetActiveEnvironment()
+	if active == nil {
+		tui.Components.EnvStatus.SetText("[yellow]Env:[-] none")
+		return
+	}
+	tui.Components.EnvStatus.SetText(fmt.Sprintf("[yellow]Env:[-] [green]%s[-]", active.Name))
+}
+
+func variablesToString(vars map[string]string) string {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&builder, "%s:%s\n", k, vars[k])
+	}
+	return builder.String()
+}
@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func (tui *Tui) setupGraphQLKeybindings() {
+	tui.Components.BodyText.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyCtrlSpace {
+			return event
+		}
+		if index, _ := tui.Components.BodyType.GetCurrentOption(); index != graphQLBodyType {
+			return event
+		}
+		query, offset := tui.Components.BodyText.GetText(), bodyCursor(tui.Components.BodyText)
+		go tui.handleCompleteGraphQL(query, offset)
+		return nil
+	})
+
+	tui.Components.GraphQLFieldList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			tui.hideGraphQLFields()
+			return nil
+		}
+		return event
+	})
+}
+
+// bodyCursor returns the offset of the cursor in the text of area.
+func bodyCursor(area *tview.TextArea) int {
+	_, start, end := area.GetSelection()
+	return max(start, end)
+}
+
+// handleCompleteGraphQL lists the fields that can be written at offset in
+// the query, asking the endpoint for its schema the first time.
+func (tui *Tui) handleCompleteGraphQL(query string, offset int) {
+	schema, err := tui.graphQLSchema()
+	if err != nil {
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.StatusText.SetText(fmt.Sprintf("[red]Error: %s[-]", err.Error()))
+		})
+		return
+	}
+
+	prefix, fields := schema.Complete(query, offset)
+	tui.Ui.QueueUpdateDraw(func() {
+		if len(fields) == 0 {
+			tui.Components.StatusText.SetText("No fields to complete here")
+			return
+		}
+		if tui.Components.BodyText.GetText() != query {
+			// edited while the schema was fetched, the offsets no longer hold
+			tui.Components.StatusText.SetText("Query changed, C-space to complete again")
+			return
+		}
+		tui.showGraphQLFields(fields, offset-len(prefix), offset)
+	})
+}
+
+// graphQLSchema returns the schema of the endpoint in the form, kept until
+// the URL changes.
+func (tui *Tui) graphQLSchema() (*domain.GraphQLSchema, error) {
+	if err := tui.getCurrentRequest(); err != nil {
+		return nil, err
+	}
+	req := tui.State.CurrentRequest
+	if tui.State.GraphQLSchema != nil && tui.State.GraphQLSchemaURL == req.URL {
+		return tui.State.GraphQLSchema, nil
+	}
+
+	tui.Ui.QueueUpdateDraw(func() {
+		tui.Components.StatusText.SetText("Fetching schema...")
+	})
+	schema, err := tui.HttpService.IntrospectGraphQL(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+	tui.State.GraphQLSchema, tui.State.GraphQLSchemaURL = schema, req.URL
+	tui.Ui.QueueUpdateDraw(func() {
+		tui.Components.StatusText.SetText(fmt.Sprintf("Schema of %s loaded", req.URL))
+	})
+	return schema, nil
+}
+
+// showGraphQLFields offers fields to replace the query text between start
+// and end, the part of the name already typed.
+func (tui *Tui) showGraphQLFields(fields []domain.GraphQLField, start, end int) {
+	list := tui.Components.GraphQLFieldList
+	list.Clear()
+	for _, field := range fields {
+		name := field.Name
+		list.AddItem(tview.Escape(field.Signature()), tview.Escape(field.Description), 0, func() {
+			tui.hideGraphQLFields()
+			tui.Components.BodyText.Replace(start, end, name)
+		})
+	}
+	tui.Components.Pages.ShowPage(graphQLFieldPage)
+	tui.Ui.SetFocus(list)
+}
+
+func (tui *Tui) hideGraphQLFields() {
+	tui.Components.Pages.HidePage(graphQLFieldPage)
+	tui.restoreFocus()
+}
+
+// graphQLResultString shows the data of a GraphQL response apart from its
+// errors.
+func graphQLResultString(result *domain.GraphQLResult) string {
+	var builder strings.Builder
+	if len(result.Errors) > 0 {
+		fmt.Fprintf(&builder, "[red]Errors (%d):[-]\n", len(result.Errors))
+		for _, gqlErr := range result.Errors {
+			fmt.Fprintf(&builder, "[red]- %s[-]\n", tview.Escape(gqlErr.String()))
+		}
+		builder.WriteString("\n")
+	}
+
+	data := result.DataString()
+	if data == "" {
+		builder.WriteString("[yellow]Data:[-] [blue]null[-]")
+		return builder.String()
+	}
+	if len(data) > maxRenderedBody {
+		data = data[:maxRenderedBody]
+	}
+	builder.WriteString("[yellow]Data:[-]\n")
+	builder.WriteString(tview.Escape(data))
+	return builder.String()
+}
@@ -0,0 +1,168 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func (tui *Tui) setupGRPCKeybindings() {
+	tui.Components.GRPCMethodList.SetChangedFunc(func(index int, _ string, _ string, _ rune) {
+		tui.previewGRPCMethod(index)
+	})
+
+	tui.Components.GRPCMethodList.SetSelectedFunc(func(index int, _ string, _ string, _ rune) {
+		if index < len(tui.State.GRPCMethods) {
+			tui.hideGRPCMethods()
+			tui.useGRPCMethod(tui.State.GRPCMethods[index])
+		}
+	})
+
+	tui.Components.GRPCMethodModal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			tui.hideGRPCMethods()
+			return nil
+		}
+		return event
+	})
+}
+
+// callGRPC calls the method in the URL of the current request, showing the
+// messages of a server stream as they arrive. Without a method the services
+// of the server are listed to pick one.
+func (tui *Tui) callGRPC() {
+	// an invalid or unresolved URL is reported by the call itself
+	if target, err := tui.State.CurrentRequest.GRPCTarget(); err == nil && target.Method == "" {
+		tui.listGRPCMethods()
+		return
+	}
+
+	ctx, finish := tui.beginRequest()
+	buffer := newStreamBuffer("messages")
+	stopStream := tui.showStream(buffer, streamLabels{
+		waiting: "Calling...",
+		running: "Receiving",
+		keys:    "C-k: stop, c: clear",
+	})
+
+	resp, err := tui.HttpService.CallGRPC(ctx, tui.State.CurrentRequest, buffer.addEvent)
+	stopStream()
+	stopped := ctx.Err() != nil
+	if !finish() {
+		return
+	}
+	if err != nil {
+		tui.showStreamError(err)
+		return
+	}
+
+	results := domain.EvaluateAssertions(tui.State.CurrentRequest.Assertions, resp)
+	if resp.Stream == nil {
+		// a unary call is shown like any other response
+		tui.State.CurrentResponse = resp
+		tui.State.CurrentResults = results
+		tui.updateOnReceiveResponse()
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.StatusText.SetText(resp.Status)
+		})
+		return
+	}
+
+	ended := "ended"
+	if stopped {
+		ended = "stopped"
+	}
+	stats := streamStatsString(resp.Stream.Events, "messages", resp.BodySize, resp.Stream.Duration)
+	tui.Ui.QueueUpdateDraw(func() {
+		tui.State.CurrentResponse = resp
+		tui.State.CurrentResults = results
+		fmt.Fprint(tui.Components.ResponseView, buffer.take())
+		fmt.Fprint(tui.Components.ResponseView, grpcStatusString(resp))
+		tui.Components.StatusText.SetText(fmt.Sprintf("%s, stream %s: %s\nTab: response tabs, c: clear", resp.Status, ended, stats))
+	})
+}
+
+// grpcStatusString ends the messages of a stream with the status and the
+// trailers it closed with.
+func grpcStatusString(resp *domain.Response) string {
+	color := "green"
+	if !resp.Success() {
+		color = "red"
+	}
+	text := fmt.Sprintf("[yellow]Status:[-] [%s]%s[-]", color, resp.Status)
+	if resp.GRPC.Message != "" {
+		text += " " + tview.Escape(resp.GRPC.Message)
+	}
+	var trailers strings.Builder
+	writeHeaderLines(&trailers, resp.GRPC.Trailers)
+	return text + "\n" + trailers.String()
+}
+
+func (tui *Tui) listGRPCMethods() {
+	ctx, finish := tui.beginRequest()
+	tui.Ui.QueueUpdateDraw(func() {
+		tui.Components.StatusText.SetText("Listing services...\nC-k: stop")
+	})
+
+	methods, err := tui.HttpService.ListGRPCMethods(ctx, tui.State.CurrentRequest)
+	if !finish() {
+		return
+	}
+	tui.Ui.QueueUpdateDraw(func() {
+		if err != nil {
+			tui.Components.StatusText.SetText(fmt.Sprintf("[red]Error: %s[-]", err.Error()))
+			return
+		}
+		if len(methods) == 0 {
+			tui.Components.StatusText.SetText("The server offers no services")
+			return
+		}
+		tui.Components.StatusText.SetText(fmt.Sprintf("%d methods found", len(methods)))
+		tui.showGRPCMethods(methods)
+	})
+}
+
+func (tui *Tui) showGRPCMethods(methods []domain.GRPCMethod) {
+	tui.State.GRPCMethods = methods
+	list := tui.Components.GRPCMethodList
+	list.Clear()
+	for _, method := range methods {
+		list.AddItem(tview.Escape(method.Path()), method.Kind(), 0, nil)
+	}
+	tui.previewGRPCMethod(0)
+	tui.Components.Pages.ShowPage(grpcMethodsPage)
+	tui.Ui.SetFocus(list)
+}
+
+func (tui *Tui) hideGRPCMethods() {
+	tui.Components.Pages.HidePage(grpcMethodsPage)
+	tui.restoreFocus()
+}
+
+func (tui *Tui) previewGRPCMethod(index int) {
+	preview := tui.Components.GRPCMethodPreview
+	if index < 0 || index >= len(tui.State.GRPCMethods) {
+		preview.SetText("")
+		return
+	}
+	method := tui.State.GRPCMethods[index]
+	text := fmt.Sprintf("[yellow]%s[-] [gray]%s[-]\n\n%s", tview.Escape(method.Path()), method.Kind(), tview.Escape(method.Template))
+	if method.ClientStreaming {
+		text += "\n\n[red]Client streaming methods cannot be called[-]"
+	}
+	preview.SetText(text).ScrollToBeginning()
+}
+
+// useGRPCMethod adds the method to the URL of the form and puts its request
+// message in the Body to be edited.
+func (tui *Tui) useGRPCMethod(method domain.GRPCMethod) {
+	url := strings.TrimRight(tui.State.CurrentRequest.URL, "/") + "/" + method.Path()
+	tui.Components.MethodDropdown.SetCurrentOption(7)
+	tui.Components.URLInput.SetText(url)
+	tui.Components.BodyType.SetCurrentOption(1)
+	tui.Components.BodyText.SetText(method.Template, true)
+	tui.Components.StatusText.SetText(fmt.Sprintf("%s selected, edit the Body and C-s: call", method.Path()))
+}
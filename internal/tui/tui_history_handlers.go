@@ -0,0 +1,204 @@
+package tui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const historyListLimit = 200
+
+func (tui *Tui) setupHistoryKeybindings() {
+	tui.Components.HistorySearch.SetChangedFunc(func(_ string) {
+		tui.loadHistory()
+	})
+
+	tui.Components.HistoryList.SetChangedFunc(func(index int, _ string, _ string, _ rune) {
+		tui.previewHistoryEntry(tui.historyEntryAt(index))
+	})
+
+	tui.Components.HistoryList.SetSelectedFunc(func(index int, _ string, _ string, _ rune) {
+		if entry := tui.historyEntryAt(index); entry != nil {
+			tui.hideHistory()
+			tui.populateRequest(entry.Request)
+			tui.State.CurrentRequest = entry.Request
+			go tui.sendCurrentRequest()
+		}
+	})
+
+	tui.Components.HistoryModal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			tui.hideHistory()
+			return nil
+		case tcell.KeyCtrlO:
+			if entry := tui.historyEntryAt(tui.Components.HistoryList.GetCurrentItem()); entry != nil {
+				tui.hideHistory()
+				tui.populateRequest(entry.Request)
+				tui.Components.StatusText.SetText("History entry loaded")
+			}
+			return nil
+		case tcell.KeyCtrlA:
+			go tui.handlePromoteHistoryEntry()
+			return nil
+		case tcell.KeyCtrlD:
+			if tui.Ui.GetFocus() == tui.Components.HistoryList {
+				go tui.handleDeleteHistoryEntry()
+			}
+			return nil
+		case tcell.KeyCtrlF:
+			tui.Ui.SetFocus(tui.Components.HistorySearch)
+			return nil
+		case tcell.KeyCtrlL:
+			tui.Ui.SetFocus(tui.Components.HistoryList)
+			return nil
+		case tcell.KeyCtrlN:
+			tui.Ui.SetFocus(tui.Components.HistoryNameInput)
+			return nil
+		default:
+			return event
+		}
+	})
+}
+
+func (tui *Tui) showHistory() {
+	tui.Components.HistorySearch.SetText("")
+	tui.Components.HistoryNameInput.SetText("")
+	tui.loadHistory()
+	tui.Components.Pages.ShowPage(historyPage)
+	tui.Ui.SetFocus(tui.Components.HistoryList)
+}
+
+func (tui *Tui) hideHistory() {
+	tui.Components.Pages.HidePage(historyPage)
+	tui.restoreFocus()
+}
+
+func (tui *Tui) historyEntryAt(index int) *domain.HistoryEntry {
+	if index < 0 || index >= len(tui.State.HistoryEntries) {
+		return nil
+	}
+	return tui.State.HistoryEntries[index]
+}
+
+func (tui *Tui) loadHistory() {
+	if tui.HttpService == nil {
+		return
+	}
+	entries, err := tui.HttpService.GetHistory(strings.TrimSpace(tui.Components.HistorySearch.GetText()), historyListLimit)
+	if err != nil {
+		log.Printf("Error loading history: %v", err)
+		return
+	}
+
+	tui.State.HistoryEntries = entries
+
+	tui.Components.HistoryList.Clear()
+	for _, entry := range entries {
+		tui.Components.HistoryList.AddItem(tview.Escape(entry.Summary()), "", 0, nil)
+	}
+	tui.previewHistoryEntry(tui.historyEntryAt(0))
+}
+
+func (tui *Tui) previewHistoryEntry(entry *domain.HistoryEntry) {
+	if entry == nil {
+		tui.Components.HistoryPreview.SetText("")
+		return
+	}
+	tui.Components.HistoryPreview.SetText(historyEntryString(entry)).ScrollToBeginning()
+}
+
+func (tui *Tui) handlePromoteHistoryEntry() {
+	entry := tui.historyEntryAt(tui.Components.HistoryList.GetCurrentItem())
+	if entry == nil {
+		return
+	}
+
+	req, err := entry.Promote(tui.Components.HistoryNameInput.GetText())
+	if err != nil {
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.StatusText.SetText(fmt.Sprintf("[red]Error: %s[-]", err.Error()))
+		})
+		return
+	}
+
+	err = tui.HttpService.SaveRequest(req)
+	if err != nil {
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.StatusText.SetText(fmt.Sprintf("Error: %s", err))
+		})
+		return
+	}
+
+	tui.Ui.QueueUpdateDraw(func() {
+		tui.loadSavedRequests()
+		tui.Components.HistoryNameInput.SetText("")
+		tui.Components.StatusText.SetText(fmt.Sprintf("Saved history entry as %s", req.Name))
+	})
+}
+
+func (tui *Tui) handleDeleteHistoryEntry() {
+	entry := tui.historyEntryAt(tui.Components.HistoryList.GetCurrentItem())
+	if entry == nil {
+		return
+	}
+
+	err := tui.HttpService.DeleteHistoryEntry(entry.ID)
+	if err != nil {
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.StatusText.SetText(fmt.Sprintf("Error %v", err))
+		})
+		return
+	}
+
+	tui.Ui.QueueUpdateDraw(func() {
+		tui.loadHistory()
+		tui.Components.StatusText.SetText("History entry deleted")
+	})
+}
+
+func historyEntryString(entry *domain.HistoryEntry) string {
+	var builder strings.Builder
+
+	req := entry.Request
+	fmt.Fprintf(&builder, "[yellow]Sent:[-] [blue]%s[-]\n", entry.SentAt.Local().Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&builder, "[yellow]Request:[-] [blue]%s %s[-]\n", req.Method, tview.Escape(req.URL))
+	if len(req.Params) > 0 {
+		fmt.Fprintf(&builder, "[yellow]Params:[-] %s\n", tview.Escape(mapToString(req.Params)))
+	}
+	if len(req.Headers) > 0 {
+		fmt.Fprintf(&builder, "[yellow]Headers:[-] %s\n", tview.Escape(mapToString(req.Headers)))
+	}
+	if req.Auth != nil {
+		fmt.Fprintf(&builder, "[yellow]Auth:[-] %s\n", tview.Escape(req.Auth.Summary()))
+	}
+	if req.Options != nil {
+		fmt.Fprintf(&builder, "[yellow]Options:[-] %s\n", tview.Escape(req.Options.String()))
+	}
+	if req.BodyFile != "" {
+		fmt.Fprintf(&builder, "[yellow]Body file:[-] %s\n", tview.Escape(req.BodyFile))
+	}
+	if len(req.Form) > 0 {
+		fmt.Fprintf(&builder, "[yellow]Form:[-]\n%s\n", tview.Escape(domain.FormatForm(req.Form)))
+	}
+	if req.Body != "" {
+		fmt.Fprintf(&builder, "[yellow]Body:[-]\n%s\n", tview.Escape(req.Body))
+	}
+	builder.WriteString("\n")
+
+	if entry.Error != "" {
+		fmt.Fprintf(&builder, "[red]Error: %s[-]\n", tview.Escape(entry.Error))
+		return builder.String()
+	}
+	if entry.Response != nil {
+		if entry.Response.Timing != nil {
+			fmt.Fprintf(&builder, "[yellow]Timing:[-]\n%s\n", timingWaterfallString(entry.Response.Timing))
+		}
+		builder.WriteString(responseStringBuilder(entry.Response, nil))
+	}
+	return builder.String()
+}
@@ -1,64 +1,52 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/ManoloEsS/burrow/internal/service"
+	"github.com/rivo/tview"
 )
 
 func (tui *Tui) handleLoadRequest() {
-	tui.Ui.QueueUpdateDraw(func() {
-		tui.Components.StatusText.SetText("Populating...")
-	})
-
-	if tui.Components.RequestList.GetItemCount() < 1 {
+	_, req := tui.selectedTreeItem()
+	if req == nil {
 		tui.Ui.QueueUpdateDraw(func() {
-			tui.Components.StatusText.SetText("No saved requests")
+			tui.Components.StatusText.SetText("Select a saved request to load")
 		})
 		return
 	}
-	index := tui.Components.RequestList.GetCurrentItem()
-
-	text, _ := tui.Components.RequestList.GetItemText(index)
-
-	textParts := strings.Split(text, "|")
-
-	name := strings.TrimSpace(textParts[0])
-
-	for _, request := range tui.State.SavedRequests {
-		if request.Name == name {
-			tui.Ui.QueueUpdateDraw(func() {
-				tui.populateRequest(request)
-			})
-		}
-	}
 
 	tui.Ui.QueueUpdateDraw(func() {
+		tui.populateRequest(req)
 		tui.Components.StatusText.SetText("Request loaded")
 	})
-
 }
 
 func (tui *Tui) handleDeleteRequest() {
-	tui.Ui.QueueUpdateDraw(func() {
-		tui.Components.StatusText.SetText("Deleting...")
-	})
-
-	if tui.Components.RequestList.GetItemCount() < 1 {
+	collection, req := tui.selectedTreeItem()
+	if collection != nil && collection.Collection != nil {
+		tui.handleDeleteCollection(collection)
+		return
+	}
+	if req == nil {
 		tui.Ui.QueueUpdateDraw(func() {
-			tui.Components.StatusText.SetText("No saved requests")
+			tui.Components.StatusText.SetText("Select a saved request to delete")
 		})
 		return
-
 	}
 
-	index := tui.Components.RequestList.GetCurrentItem()
-	text, _ := tui.Components.RequestList.GetItemText(index)
-	textParts := strings.Split(text, "|")
+	tui.Ui.QueueUpdateDraw(func() {
+		tui.Components.StatusText.SetText("Deleting...")
+	})
 
-	err := tui.HttpService.DeleteRequest(strings.TrimSpace(textParts[0]))
+	err := tui.HttpService.DeleteRequest(req.Name)
 	if err != nil {
 		log.Printf("could not delete request: %v", err)
 		tui.Ui.QueueUpdateDraw(func() {
@@ -84,13 +72,22 @@ func (tui *Tui) handleSaveRequest() {
 		return
 	}
 	if tui.State.CurrentRequest.Name == "" {
-		tui.State.CurrentRequest.Name = fmt.Sprintf("unnamed%d", tui.Components.RequestList.GetItemCount()+1)
+		tui.State.CurrentRequest.Name = fmt.Sprintf("unnamed%d", len(tui.State.SavedRequests)+1)
+	}
+
+	savedIn := ""
+	if collection := tui.selectedCollection(); collection != nil {
+		tui.State.CurrentRequest.CollectionID = collection.ID()
+		savedIn = collection.Path()
 	}
 
 	tui.Ui.QueueUpdateDraw(func() {
 		tui.Components.StatusText.SetText("Saving request...")
 	})
 
+	// the service moves these to the secret store
+	plaintext := tui.State.CurrentRequest.PlaintextCredentials()
+
 	err = tui.HttpService.SaveRequest(tui.State.CurrentRequest)
 	if err != nil {
 		tui.Ui.QueueUpdateDraw(func() {
@@ -104,7 +101,16 @@ func (tui *Tui) handleSaveRequest() {
 	})
 
 	tui.Ui.QueueUpdateDraw(func() {
-		tui.Components.StatusText.SetText("Request Saved")
+		status := "Request Saved"
+		if savedIn != "" {
+			status = fmt.Sprintf("Request Saved in %s", savedIn)
+		}
+		if len(plaintext) > 0 {
+			// show the references that replaced the credentials
+			tui.populateRequest(tui.State.CurrentRequest)
+			status += fmt.Sprintf("\nMoved %s to the secret store", strings.Join(plaintext, ", "))
+		}
+		tui.Components.StatusText.SetText(status)
 	})
 
 }
@@ -118,22 +124,92 @@ func (tui *Tui) handleSendRequest() {
 		return
 	}
 
-	tui.Ui.QueueUpdateDraw(func() {
-		tui.Components.ResponseView.SetText("[yellow]Sending request...[-]")
-	})
+	tui.sendCurrentRequest()
+}
 
-	resp, err := tui.HttpService.SendRequest(tui.State.CurrentRequest)
+func (tui *Tui) sendCurrentRequest() {
+	if tui.State.CurrentRequest.IsGRPC() {
+		tui.callGRPC()
+		return
+	}
+	if tui.State.CurrentRequest.IsWebSocket() {
+		tui.sendWebSocket()
+		return
+	}
+	if tui.State.CurrentRequest.IsStream() {
+		tui.streamCurrentRequest()
+		return
+	}
+
+	ctx, finish := tui.beginRequest()
+	progress := &downloadProgress{}
+	stopElapsed := tui.showElapsed("Sending request...", progress)
+
+	resp, err := tui.HttpService.SendRequest(service.WithProgress(ctx, progress.update), tui.State.CurrentRequest)
+	stopElapsed()
+	if !finish() {
+		// a newer request took over the Response view
+		_ = resp.RemoveBodyFile()
+		return
+	}
+	if errors.Is(err, context.Canceled) {
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.ResponseView.SetText("[yellow]Request cancelled[-]")
+			tui.Components.StatusText.SetText("Request cancelled")
+		})
+		return
+	}
 	if err != nil {
 		tui.Ui.QueueUpdateDraw(func() {
 			tui.Components.ResponseView.SetText(fmt.Sprintf("[red]Error: %s[-]", err.Error()))
 		})
 		return
 	}
+	_ = tui.State.CurrentResponse.RemoveBodyFile()
 	tui.State.CurrentResponse = resp
+	tui.State.CurrentResults = domain.EvaluateAssertions(tui.State.CurrentRequest.Assertions, resp)
 
 	tui.updateOnReceiveResponse()
 }
 
+func (tui *Tui) handleRunSuite() {
+	reqs := slices.Clone(tui.State.SavedRequests)
+	// saved requests are listed newest first, run them in the order they were created
+	slices.Reverse(reqs)
+
+	suiteName := "saved-requests"
+	if collection := tui.selectedCollection(); collection != nil && collection.Collection != nil {
+		reqs = domain.BuildCollectionTree(tui.State.Collections, reqs).Node(collection.ID()).AllRequests()
+		suiteName = collection.Path()
+	}
+
+	if len(reqs) == 0 {
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.StatusText.SetText("No saved requests")
+		})
+		return
+	}
+
+	ctx, finish := tui.beginRequest()
+	stopElapsed := tui.showElapsed(fmt.Sprintf("Running %d requests...", len(reqs)), nil)
+
+	suite := service.RunSuite(ctx, tui.HttpService, suiteName, reqs)
+	stopElapsed()
+	cancelled := ctx.Err() != nil
+	if !finish() {
+		return
+	}
+
+	tui.Ui.QueueUpdateDraw(func() {
+		tui.Components.ResponseView.SetText(suiteStringBuilder(suite)).ScrollToBeginning()
+		if cancelled {
+			tui.Components.StatusText.SetText(fmt.Sprintf("Tests cancelled after %d of %d requests", len(suite.Cases), len(reqs)))
+			return
+		}
+		tui.Components.StatusText.SetText(fmt.Sprintf("Tests: %d passed, %d failed", suite.PassedCount(), suite.FailedCount()))
+	})
+}
+
 func (tui *Tui) getCurrentRequest() error {
 	name := tui.Components.NameInput.GetText()
 
@@ -149,6 +225,12 @@ func (tui *Tui) getCurrentRequest() error {
 
 	body := tui.Components.BodyText.GetText()
 
+	variablesText := tui.Components.VariablesText.GetText()
+
+	assertionsText := tui.Components.AssertionsText.GetText()
+
+	optionsText := tui.Components.OptionsInput.GetText()
+
 	newRequest := *domain.NewRequest()
 
 	err := newRequest.BuildRequest(name, method, url, headersText, paramsText, bodyType, body, tui.Config)
@@ -156,6 +238,27 @@ func (tui *Tui) getCurrentRequest() error {
 		return err
 	}
 
+	err = newRequest.ParseGraphQLVariables(variablesText)
+	if err != nil {
+		return err
+	}
+
+	err = newRequest.ParseAssertions(assertionsText)
+	if err != nil {
+		return err
+	}
+
+	err = newRequest.ParseOptions(optionsText)
+	if err != nil {
+		return err
+	}
+
+	newRequest.Auth = tui.State.Auth
+
+	if newRequest.IsWebSocket() {
+		newRequest.Messages = tui.wsScript()
+	}
+
 	tui.State.CurrentRequest = &newRequest
 
 	return nil
@@ -170,28 +273,24 @@ func (tui *Tui) loadSavedRequests() {
 		log.Printf("Error loading saved requests: %v", err)
 		return
 	}
+	collections, err := tui.HttpService.GetCollections()
+	if err != nil {
+		log.Printf("Error loading collections: %v", err)
+		return
+	}
 
-	if len(savedReqs) > 0 {
-		tui.State.SavedRequests = savedReqs
-
-		tui.Components.RequestList.Clear()
-
-		for _, req := range tui.State.SavedRequests {
-			name := req.Name
-			if name == "" {
-				name = "unnamed"
-			}
-
-			itemText := fmt.Sprintf("%-15s|  %-6s|%s", name, req.Method, req.URL)
-			tui.Components.RequestList.AddItem(itemText, "", 0, nil)
-		}
-	} else {
-		tui.Components.RequestList.Clear()
+	tui.State.SavedRequests = savedReqs
+	tui.State.Collections = collections
+	tui.State.CollectionTree = domain.BuildCollectionTree(collections, savedReqs)
+	if tui.State.CollapsedCollections == nil {
+		tui.State.CollapsedCollections = make(map[int64]bool)
 	}
+
+	tui.renderRequestTree()
 }
 
 func (tui *Tui) updateOnReceiveResponse() {
-	responseText := responseStringBuilder(tui.State.CurrentResponse)
+	responseText := responseTabString(tui.State.CurrentResponse, tui.State.CurrentResults, tui.State.ResponseTab)
 	tui.Ui.QueueUpdateDraw(func() {
 		tui.Components.ResponseView.SetText(responseText)
 	})
@@ -212,13 +311,37 @@ func (tui *Tui) populateRequest(req *domain.Request) {
 		methodIdx = 3
 	case "HEAD":
 		methodIdx = 4
+	case "PATCH":
+		methodIdx = 5
+	case domain.MethodWebSocket:
+		methodIdx = 6
+	case domain.MethodGRPC:
+		methodIdx = 7
 	}
 
-	switch req.ContentType["Content-Type"] {
-	case "Text":
-		bodyTypeIdx = 0
-	case "JSON":
+	body, variables := req.Body, ""
+	switch contentType := req.ContentType["Content-Type"]; {
+	case req.GraphQL != nil:
+		bodyTypeIdx = graphQLBodyType
+		body, variables = req.GraphQL.Query, req.GraphQL.Variables
+	case req.BodyFile != "":
+		bodyTypeIdx = 4
+		body = req.BodyFile
+	case strings.HasPrefix(contentType, "application/json"):
 		bodyTypeIdx = 1
+	case req.IsMultipart():
+		bodyTypeIdx = 3
+		body = domain.FormatForm(req.Form)
+	case strings.HasPrefix(contentType, domain.ContentTypeForm):
+		// bodies imported already encoded are shown as fields when they can be
+		fields, err := domain.FormFromBody(req.Body)
+		if len(req.Form) > 0 {
+			fields, err = req.Form, nil
+		}
+		if err == nil && editableForm(fields) {
+			bodyTypeIdx = 2
+			body = domain.FormatForm(fields)
+		}
 	}
 
 	tui.Components.MethodDropdown.SetCurrentOption(methodIdx)
@@ -226,29 +349,179 @@ func (tui *Tui) populateRequest(req *domain.Request) {
 	tui.Components.NameInput.SetText(req.Name)
 	tui.Components.HeadersText.SetText(mapToString(req.Headers), true)
 	tui.Components.ParamsText.SetText(mapToString(req.Params), true)
+	tui.setAuth(req.Auth)
 	tui.Components.BodyType.SetCurrentOption(bodyTypeIdx)
-	tui.Components.BodyText.SetText(req.Body, true)
+	tui.Components.BodyText.SetText(body, true)
+	tui.Components.VariablesText.SetText(variables, true)
+	tui.Components.AssertionsText.SetText(assertionsToString(req.Assertions), true)
+	tui.Components.OptionsInput.SetText(req.Options.String())
+	tui.State.WSScript = slices.Clone(req.Messages)
 
 }
 
-func responseStringBuilder(resp *domain.Response) string {
+// editableForm reports whether fields survive being edited as key:value
+// lines, which cannot hold line breaks or values starting with @.
+func editableForm(fields []domain.FormField) bool {
+	for _, field := range fields {
+		if strings.ContainsAny(field.Key+field.Value, "\n") || strings.Contains(field.Key, ":") ||
+			(!field.File && strings.HasPrefix(field.Value, "@")) {
+			return false
+		}
+	}
+	return true
+}
+
+func (tui *Tui) showSaveResponseBody() {
+	resp := tui.State.CurrentResponse
+	if resp == nil || resp.BodySize == 0 {
+		tui.Components.StatusText.SetText("No response body to save")
+		return
+	}
+
+	tui.showPrompt("Save response body to", resp.BodyFileName(), nil, func(path string) {
+		tui.handleSaveResponseBody(resp, path)
+	})
+}
+
+func (tui *Tui) handleSaveResponseBody(resp *domain.Response, path string) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return
+	}
+
+	err := resp.SaveBody(path)
+	tui.Ui.QueueUpdateDraw(func() {
+		if err != nil {
+			tui.Components.StatusText.SetText(fmt.Sprintf("Error saving body: %v", err))
+			return
+		}
+		tui.Components.StatusText.SetText(fmt.Sprintf("Saved %s to %s", domain.FormatBytes(resp.BodySize), path))
+	})
+}
+
+// maxRenderedBody caps the text handed to the Response view, which slows
+// down on very long bodies.
+const maxRenderedBody = 256 << 10
+
+func responseStringBuilder(resp *domain.Response, results []domain.AssertionResult) string {
 	var builder strings.Builder
 
+	if len(results) > 0 {
+		fmt.Fprint(&builder, assertionResultsString(results))
+	}
+
 	fmt.Fprintf(&builder, "[yellow]Status:[-] [blue]%s[-]\n", resp.Status)
+	if resp.GRPC != nil && resp.GRPC.Message != "" {
+		fmt.Fprintf(&builder, "[yellow]Message:[-] [red]%s[-]\n", tview.Escape(resp.GRPC.Message))
+	}
 	fmt.Fprintf(&builder, "[yellow]Response time:[-] [blue]%s[-]\n\n", resp.ResponseTime)
 	fmt.Fprintf(&builder, "[yellow]Content-Type:[-] [blue]%s[-]\n", resp.ContentType)
 	fmt.Fprintf(&builder, "[yellow]Content-Length:[-] [blue]%d[-]\n\n", resp.ContentLenght)
+	if resp.Stream != nil {
+		unit := "events"
+		if resp.GRPC != nil {
+			unit = "messages"
+		}
+		fmt.Fprintf(&builder, "[yellow]Stream:[-] [blue]%s[-]\n\n", streamStatsString(resp.Stream.Events, unit, resp.BodySize, resp.Stream.Duration))
+	}
 
-	if resp.Body != "" {
+	switch {
+	case resp.GraphQL != nil:
+		fmt.Fprint(&builder, graphQLResultString(resp.GraphQL))
+	case resp.Binary:
+		fmt.Fprintf(&builder, "[yellow]Body:[-] [blue]binary, %s, hex preview[-]\n", domain.FormatBytes(resp.BodySize))
+		fmt.Fprint(&builder, tview.Escape(resp.HexPreview()))
+	case resp.Body != "":
 		fmt.Fprintf(&builder, "[yellow]Body:[-]\n")
-		fmt.Fprint(&builder, resp.Body)
-	} else {
+		body := resp.Body
+		if len(body) > maxRenderedBody {
+			body = body[:maxRenderedBody]
+		}
+		fmt.Fprint(&builder, tview.Escape(body))
+	default:
 		fmt.Fprint(&builder, "[blue]No body[-]")
 	}
 
+	shown := min(int64(len(resp.Body)), maxRenderedBody)
+	if resp.Binary {
+		shown = min(int64(len(resp.Body)), domain.HexPreviewSize)
+	}
+	if shown < resp.BodySize {
+		fmt.Fprintf(&builder, "\n[gray]Showing %s of %s, C-w: save body[-]", domain.FormatBytes(shown), domain.FormatBytes(resp.BodySize))
+	}
+
+	return builder.String()
+}
+
+func assertionResultsString(results []domain.AssertionResult) string {
+	var builder strings.Builder
+
+	passed := 0
+	for _, r := range results {
+		if r.Passed {
+			passed++
+		}
+	}
+
+	if passed == len(results) {
+		fmt.Fprintf(&builder, "[yellow]Assertions:[-] [green]%d/%d passed[-]\n", passed, len(results))
+	} else {
+		fmt.Fprintf(&builder, "[yellow]Assertions:[-] [red]%d/%d passed[-]\n", passed, len(results))
+	}
+
+	for _, r := range results {
+		if r.Passed {
+			fmt.Fprintf(&builder, "  [green]PASS[-] %s\n", tview.Escape(r.Assertion.String()))
+		} else {
+			fmt.Fprintf(&builder, "  [red]FAIL[-] %s [gray](%s)[-]\n", tview.Escape(r.Assertion.String()), tview.Escape(r.Message))
+		}
+	}
+	builder.WriteString("\n")
+
 	return builder.String()
 }
 
+func suiteStringBuilder(suite *domain.SuiteResult) string {
+	var builder strings.Builder
+
+	if suite.Passed() {
+		fmt.Fprintf(&builder, "[yellow]Test run:[-] [green]%d passed[-], %d failed in %s\n\n", suite.PassedCount(), suite.FailedCount(), suite.Duration.Round(time.Millisecond))
+	} else {
+		fmt.Fprintf(&builder, "[yellow]Test run:[-] %d passed, [red]%d failed[-] in %s\n\n", suite.PassedCount(), suite.FailedCount(), suite.Duration.Round(time.Millisecond))
+	}
+
+	for _, tc := range suite.Cases {
+		if tc.Passed() {
+			fmt.Fprintf(&builder, "[green]PASS[-] %s [gray](%s)[-]\n", tc.Name, tc.Duration.Round(time.Millisecond))
+		} else {
+			fmt.Fprintf(&builder, "[red]FAIL[-] %s [gray](%s)[-]\n", tc.Name, tc.Duration.Round(time.Millisecond))
+		}
+
+		if tc.Error != "" {
+			fmt.Fprintf(&builder, "  [red]%s[-]\n", tview.Escape(tc.Error))
+			continue
+		}
+		for _, r := range tc.Results {
+			if !r.Passed {
+				fmt.Fprintf(&builder, "  [red]FAIL[-] %s [gray](%s)[-]\n", tview.Escape(r.Assertion.String()), tview.Escape(r.Message))
+			}
+		}
+		if len(tc.Results) == 0 && !tc.Passed() {
+			fmt.Fprintf(&builder, "  [red]status %s[-]\n", tc.Response.Status)
+		}
+	}
+
+	return builder.String()
+}
+
+func assertionsToString(assertions []domain.Assertion) string {
+	lines := make([]string, 0, len(assertions))
+	for _, a := range assertions {
+		lines = append(lines, a.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
 func mapToString(m map[string]string) string {
 	if len(m) == 0 {
 		return ""
@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+)
+
+const elapsedInterval = 100 * time.Millisecond
+
+// beginRequest cancels any request still in flight and returns the context
+// for a new one. finish releases it and reports whether no newer request
+// replaced it in the meantime.
+func (tui *Tui) beginRequest() (ctx context.Context, finish func() bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	tui.requestMu.Lock()
+	if tui.cancelRequest != nil {
+		tui.cancelRequest()
+	}
+	tui.requestSeq++
+	seq := tui.requestSeq
+	tui.cancelRequest = cancel
+	tui.requestMu.Unlock()
+
+	return ctx, func() bool {
+		defer cancel()
+		tui.requestMu.Lock()
+		defer tui.requestMu.Unlock()
+		if tui.requestSeq != seq {
+			return false
+		}
+		tui.cancelRequest = nil
+		return true
+	}
+}
+
+func (tui *Tui) handleCancelRequest() {
+	tui.requestMu.Lock()
+	cancel := tui.cancelRequest
+	tui.requestMu.Unlock()
+
+	if cancel == nil {
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.StatusText.SetText("No request in flight")
+		})
+		return
+	}
+	cancel()
+}
+
+// downloadProgress is updated by the request as the response body arrives
+// and read by the elapsed ticker.
+type downloadProgress struct {
+	read  atomic.Int64
+	total atomic.Int64
+}
+
+func (p *downloadProgress) update(read, total int64) {
+	p.read.Store(read)
+	p.total.Store(total)
+}
+
+func (p *downloadProgress) String() string {
+	read := p.read.Load()
+	if read == 0 {
+		return ""
+	}
+	if total := p.total.Load(); total > 0 {
+		return fmt.Sprintf("Downloaded %s of %s (%d%%)", domain.FormatBytes(read), domain.FormatBytes(total), read*100/total)
+	}
+	return "Downloaded " + domain.FormatBytes(read)
+}
+
+// showElapsed keeps the Response view updated with the time spent waiting,
+// and the download progress when there is one, until the returned stop func
+// is called.
+func (tui *Tui) showElapsed(label string, progress *downloadProgress) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Now()
+
+	render := func() {
+		// a tick queued before stop must not overwrite the result
+		if ctx.Err() != nil {
+			return
+		}
+		elapsed := time.Since(start).Truncate(elapsedInterval)
+		text := fmt.Sprintf("[yellow]%s %s[-]\n", label, elapsed)
+		if progress != nil && progress.String() != "" {
+			text += fmt.Sprintf("[blue]%s[-]\n", progress)
+		}
+		tui.Components.ResponseView.SetText(text + "[gray]C-k: cancel[-]")
+	}
+	tui.Ui.QueueUpdateDraw(render)
+
+	go func() {
+		ticker := time.NewTicker(elapsedInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				tui.Ui.QueueUpdateDraw(render)
+			}
+		}
+	}()
+
+	return cancel
+}
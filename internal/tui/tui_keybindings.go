@@ -4,11 +4,18 @@ import "github.com/gdamore/tcell/v2"
 
 func (tui *Tui) setupKeybindings() {
 	tui.Ui.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if tui.modalOpen() {
+			return event
+		}
+
 		switch event.Key() {
 		// handlers
 		case tcell.KeyCtrlS:
 			go tui.handleSendRequest()
 			return nil
+		case tcell.KeyCtrlK:
+			go tui.handleCancelRequest()
+			return nil
 		case tcell.KeyCtrlQ:
 			tui.Ui.Stop()
 			return nil
@@ -21,18 +28,39 @@ func (tui *Tui) setupKeybindings() {
 		case tcell.KeyCtrlX:
 			go tui.handleStopServer()
 			return nil
+		case tcell.KeyF2:
+			tui.showServerLogs()
+			return nil
+		case tcell.KeyF3:
+			tui.showBuildErrors()
+			return nil
 		case tcell.KeyCtrlD:
-			if tui.State.CurrentFocused == tui.Components.RequestList {
+			if tui.State.CurrentFocused == tui.Components.RequestTree {
 				go tui.handleDeleteRequest()
 				return nil
 			}
 			return nil
 		case tcell.KeyCtrlO:
-			if tui.State.CurrentFocused == tui.Components.RequestList {
+			if tui.State.CurrentFocused == tui.Components.RequestTree {
 				go tui.handleLoadRequest()
 				return nil
 			}
 			return nil
+		case tcell.KeyCtrlE:
+			tui.showEnvironments()
+			return nil
+		case tcell.KeyCtrlB:
+			tui.showHistory()
+			return nil
+		case tcell.KeyCtrlV:
+			tui.showCurlImport()
+			return nil
+		case tcell.KeyCtrlY:
+			go tui.handleCopyCurl()
+			return nil
+		case tcell.KeyCtrlW:
+			tui.showSaveResponseBody()
+			return nil
 		case tcell.KeyCtrlU:
 			if tui.State.CurrentFocused == tui.Components.Form {
 				go tui.clear()
@@ -48,7 +76,7 @@ func (tui *Tui) setupKeybindings() {
 			tui.focusServerInput()
 			return nil
 		case tcell.KeyCtrlL:
-			tui.focusRequestList()
+			tui.focusRequestTree()
 			return nil
 		case tcell.KeyCtrlN:
 			if tui.State.CurrentFocused == tui.Components.Form {
@@ -63,20 +91,47 @@ func (tui *Tui) setupKeybindings() {
 				tui.navigateForm(false)
 			}
 			return nil
+		case tcell.KeyTab, tcell.KeyBacktab:
+			if tui.State.CurrentFocused == tui.Components.ResponseView {
+				if event.Key() == tcell.KeyTab {
+					tui.showResponseTab(tui.State.ResponseTab + 1)
+				} else {
+					tui.showResponseTab(tui.State.ResponseTab - 1)
+				}
+				return nil
+			}
+			return event
 		case tcell.KeyRune:
-			switch event.Rune() {
-			case 'j':
-				if tui.State.CurrentFocused == tui.Components.RequestList {
-					tui.navigateList(1)
+			if tui.State.CurrentFocused == tui.Components.ResponseView {
+				if event.Rune() == 'c' {
+					tui.clearResponseView()
 					return nil
 				}
-				return event
-			case 'k':
-				if tui.State.CurrentFocused == tui.Components.RequestList {
-					tui.navigateList(-1)
+				if tab := int(event.Rune() - '1'); tab >= 0 && tab < len(responseTabs) {
+					tui.showResponseTab(tab)
 					return nil
 				}
 				return event
+			}
+			if tui.State.CurrentFocused != tui.Components.RequestTree {
+				return event
+			}
+			switch event.Rune() {
+			case 'r':
+				go tui.handleRunSuite()
+				return nil
+			case 'n':
+				go tui.handleNewCollection()
+				return nil
+			case 'e':
+				go tui.handleRenameTreeItem()
+				return nil
+			case 'm':
+				go tui.handleMoveTreeItem()
+				return nil
+			case 'c':
+				go tui.handleDuplicateRequest()
+				return nil
 			default:
 				return event
 			}
@@ -86,19 +141,38 @@ func (tui *Tui) setupKeybindings() {
 	})
 }
 
+func (tui *Tui) modalOpen() bool {
+	name, _ := tui.Components.Pages.GetFrontPage()
+	return name != mainPage
+}
+
+func (tui *Tui) restoreFocus() {
+	if tui.State.CurrentFocused == nil || tui.State.CurrentFocused == tui.Components.Form {
+		tui.focusForm()
+		return
+	}
+	tui.Ui.SetFocus(tui.State.CurrentFocused)
+}
+
 func (tui *Tui) focusForm() {
 	tui.State.CurrentFocused = tui.Components.Form
 	tui.focusSpecificFormComponent(tui.State.CurrentFormFocusIndex)
 }
 
 func (tui *Tui) focusServerInput() {
+	tui.loadRecentServers()
 	tui.State.CurrentFocused = tui.Components.ServerPath
 	tui.Ui.SetFocus(tui.Components.ServerPath)
 }
 
-func (tui *Tui) focusRequestList() {
-	tui.State.CurrentFocused = tui.Components.RequestList
-	tui.Ui.SetFocus(tui.Components.RequestList)
+func (tui *Tui) focusServerTable() {
+	tui.State.CurrentFocused = tui.Components.ServerTable
+	tui.Ui.SetFocus(tui.Components.ServerTable)
+}
+
+func (tui *Tui) focusRequestTree() {
+	tui.State.CurrentFocused = tui.Components.RequestTree
+	tui.Ui.SetFocus(tui.Components.RequestTree)
 }
 
 func (tui *Tui) focusResponseView() {
@@ -111,32 +185,22 @@ func (tui *Tui) navigateForm(forward bool) {
 	if forward {
 		tui.State.CurrentFormFocusIndex = (tui.State.CurrentFormFocusIndex + 1) % subcompCount
 	} else {
-		tui.State.CurrentFormFocusIndex = (tui.State.CurrentFormFocusIndex - 1 + 7) % subcompCount
+		tui.State.CurrentFormFocusIndex = (tui.State.CurrentFormFocusIndex - 1 + subcompCount) % subcompCount
 	}
 
 	tui.focusSpecificFormComponent(tui.State.CurrentFormFocusIndex)
 }
 
 func (tui *Tui) focusSpecificFormComponent(index int) {
+	// the form loses an item when the Variables editor is hidden
+	index = min(index, tui.Components.Form.GetFormItemCount()-1)
+	tui.State.CurrentFormFocusIndex = index
 	component := tui.Components.Form.GetFormItem(index)
 	if component != nil {
 		tui.Ui.SetFocus(component)
 	}
 }
 
-func (tui *Tui) navigateList(direction int) {
-	currentList := tui.Components.RequestList
-	currentIndex := currentList.GetCurrentItem()
-	itemCount := currentList.GetItemCount()
-
-	if itemCount == 0 {
-		return
-	}
-
-	newItem := (currentIndex + direction + itemCount) % itemCount
-	currentList.SetCurrentItem(newItem)
-}
-
 func (tui *Tui) clear() {
 	tui.Ui.QueueUpdateDraw(func() {
 		tui.Components.MethodDropdown.SetCurrentOption(0)
@@ -144,7 +208,12 @@ func (tui *Tui) clear() {
 		tui.Components.NameInput.SetText("")
 		tui.Components.HeadersText.SetText("", true)
 		tui.Components.ParamsText.SetText("", true)
+		tui.setAuth(nil)
 		tui.Components.BodyType.SetCurrentOption(0)
 		tui.Components.BodyText.SetText("", true)
+		tui.Components.VariablesText.SetText("", true)
+		tui.Components.AssertionsText.SetText("", true)
+		tui.Components.OptionsInput.SetText("")
+		tui.State.WSScript = nil
 	})
 }
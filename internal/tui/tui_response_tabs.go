@@ -0,0 +1,257 @@
+package tui
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/rivo/tview"
+)
+
+const (
+	responseTabBody = iota
+	responseTabHeaders
+	responseTabCookies
+	responseTabTLS
+	responseTabRedirects
+	responseTabTiming
+)
+
+var responseTabs = []string{"Body", "Headers", "Cookies", "TLS", "Redirects", "Timing"}
+
+// certificates expiring sooner than this are highlighted
+const certificateWarning = 30 * 24 * time.Hour
+
+const waterfallWidth = 40
+
+var timingPhaseLabels = map[string]string{
+	domain.PhaseRedirects: "Redirects",
+	domain.PhaseDNS:       "DNS lookup",
+	domain.PhaseConnect:   "TCP connect",
+	domain.PhaseTLS:       "TLS handshake",
+	domain.PhaseTTFB:      "Time to first byte",
+	domain.PhaseTransfer:  "Content transfer",
+}
+
+var timingPhaseColors = map[string]string{
+	domain.PhaseRedirects: "gray",
+	domain.PhaseDNS:       "teal",
+	domain.PhaseConnect:   "yellow",
+	domain.PhaseTLS:       "purple",
+	domain.PhaseTTFB:      "green",
+	domain.PhaseTransfer:  "blue",
+}
+
+func (tui *Tui) showResponseTab(tab int) {
+	tui.State.ResponseTab = (tab + len(responseTabs)) % len(responseTabs)
+	if tui.State.CurrentResponse == nil {
+		return
+	}
+	tui.Components.ResponseView.
+		SetText(responseTabString(tui.State.CurrentResponse, tui.State.CurrentResults, tui.State.ResponseTab)).
+		ScrollToBeginning()
+}
+
+func responseTabString(resp *domain.Response, results []domain.AssertionResult, tab int) string {
+	var builder strings.Builder
+
+	builder.WriteString(responseTabBar(resp, tab))
+
+	if tab == responseTabBody {
+		builder.WriteString(responseStringBuilder(resp, results))
+		return builder.String()
+	}
+
+	fmt.Fprintf(&builder, "[yellow]Status:[-] [blue]%s[-]  [yellow]Protocol:[-] [blue]%s[-]\n", resp.Status, resp.Proto)
+	if resp.URL != "" {
+		fmt.Fprintf(&builder, "[yellow]URL:[-] [blue]%s[-]\n", tview.Escape(resp.URL))
+	}
+	builder.WriteString("\n")
+
+	switch tab {
+	case responseTabHeaders:
+		builder.WriteString(responseHeadersString(resp))
+	case responseTabCookies:
+		builder.WriteString(responseCookiesString(resp))
+	case responseTabTLS:
+		builder.WriteString(responseTLSString(resp.TLS, time.Now()))
+	case responseTabRedirects:
+		builder.WriteString(responseRedirectsString(resp))
+	case responseTabTiming:
+		builder.WriteString(timingWaterfallString(resp.Timing))
+	}
+
+	return builder.String()
+}
+
+func responseTabBar(resp *domain.Response, active int) string {
+	counts := map[int]int{
+		responseTabHeaders:   len(resp.Headers),
+		responseTabCookies:   len(resp.Cookies),
+		responseTabRedirects: len(resp.Redirects),
+	}
+
+	tabs := make([]string, 0, len(responseTabs))
+	for i, name := range responseTabs {
+		label := fmt.Sprintf("%d %s", i+1, name)
+		if counts[i] > 0 {
+			label = fmt.Sprintf("%s (%d)", label, counts[i])
+		}
+		if i == active {
+			tabs = append(tabs, fmt.Sprintf("[black:yellow] %s [-:-]", label))
+		} else {
+			tabs = append(tabs, fmt.Sprintf("[gray] %s [-]", label))
+		}
+	}
+	return strings.Join(tabs, " ") + "\n\n"
+}
+
+func responseHeadersString(resp *domain.Response) string {
+	var builder strings.Builder
+	if len(resp.Headers) == 0 {
+		builder.WriteString("[blue]No headers[-]")
+	}
+	writeHeaderLines(&builder, resp.Headers)
+
+	// gRPC sends trailers after the messages, they are shown below the
+	// headers
+	if resp.GRPC != nil && len(resp.GRPC.Trailers) > 0 {
+		builder.WriteString("\n[yellow]Trailers[-]\n")
+		writeHeaderLines(&builder, resp.GRPC.Trailers)
+	}
+	return builder.String()
+}
+
+func writeHeaderLines(builder *strings.Builder, header http.Header) {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		for _, val := range header[key] {
+			fmt.Fprintf(builder, "[yellow]%s:[-] %s\n", tview.Escape(key), tview.Escape(val))
+		}
+	}
+}
+
+func responseCookiesString(resp *domain.Response) string {
+	if len(resp.Cookies) == 0 {
+		return "[blue]No cookies set[-]"
+	}
+
+	var builder strings.Builder
+	for _, c := range resp.Cookies {
+		fmt.Fprintf(&builder, "[yellow]%s[-] = %s\n", tview.Escape(c.Name), tview.Escape(c.Value))
+
+		var attrs []string
+		if c.Domain != "" {
+			attrs = append(attrs, "Domain="+c.Domain)
+		}
+		if c.Path != "" {
+			attrs = append(attrs, "Path="+c.Path)
+		}
+		if !c.Expires.IsZero() {
+			attrs = append(attrs, "Expires="+c.Expires.Local().Format(time.RFC1123))
+		}
+		if c.MaxAge > 0 {
+			attrs = append(attrs, fmt.Sprintf("Max-Age=%d", c.MaxAge))
+		}
+		if c.Secure {
+			attrs = append(attrs, "Secure")
+		}
+		if c.HttpOnly {
+			attrs = append(attrs, "HttpOnly")
+		}
+		if c.SameSite != "" {
+			attrs = append(attrs, "SameSite="+c.SameSite)
+		}
+		if len(attrs) > 0 {
+			fmt.Fprintf(&builder, "  [gray]%s[-]\n", tview.Escape(strings.Join(attrs, "; ")))
+		}
+	}
+	return builder.String()
+}
+
+func responseTLSString(info *domain.TLSInfo, now time.Time) string {
+	if info == nil {
+		return "[blue]Not a TLS connection[-]"
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "[yellow]Version:[-] [blue]%s[-]\n", info.Version)
+	fmt.Fprintf(&builder, "[yellow]Cipher suite:[-] [blue]%s[-]\n", info.CipherSuite)
+	if info.ServerName != "" {
+		fmt.Fprintf(&builder, "[yellow]Server name:[-] [blue]%s[-]\n", tview.Escape(info.ServerName))
+	}
+	if info.NegotiatedProtocol != "" {
+		fmt.Fprintf(&builder, "[yellow]ALPN:[-] [blue]%s[-]\n", info.NegotiatedProtocol)
+	}
+
+	for i, cert := range info.Certificates {
+		fmt.Fprintf(&builder, "\n[yellow]Certificate %d:[-] %s\n", i, tview.Escape(cert.Subject))
+		fmt.Fprintf(&builder, "  [yellow]Issuer:[-] %s\n", tview.Escape(cert.Issuer))
+		if len(cert.DNSNames) > 0 {
+			fmt.Fprintf(&builder, "  [yellow]DNS names:[-] %s\n", tview.Escape(strings.Join(cert.DNSNames, ", ")))
+		}
+		fmt.Fprintf(&builder, "  [yellow]Valid:[-] %s to %s\n", cert.NotBefore.Local().Format(time.DateOnly), cert.NotAfter.Local().Format(time.DateOnly))
+		fmt.Fprintf(&builder, "  [yellow]Expiry:[-] %s\n", certificateExpiry(cert, now))
+	}
+	return builder.String()
+}
+
+func certificateExpiry(cert domain.Certificate, now time.Time) string {
+	left := cert.NotAfter.Sub(now)
+	switch {
+	case left <= 0:
+		return "[red]expired[-]"
+	case left < certificateWarning:
+		return fmt.Sprintf("[red]expires in %d days[-]", int(left.Hours()/24))
+	default:
+		return fmt.Sprintf("[green]expires in %d days[-]", int(left.Hours()/24))
+	}
+}
+
+func responseRedirectsString(resp *domain.Response) string {
+	if len(resp.Redirects) == 0 {
+		return "[blue]No redirects[-]"
+	}
+
+	var builder strings.Builder
+	for i, r := range resp.Redirects {
+		fmt.Fprintf(&builder, "%d. [blue]%s[-] %s\n   [gray]-> %s[-]\n", i+1, r.Status, tview.Escape(r.URL), tview.Escape(r.Location))
+	}
+	fmt.Fprintf(&builder, "\n[yellow]Final:[-] [blue]%s[-] %s\n", resp.Status, tview.Escape(resp.URL))
+	return builder.String()
+}
+
+// timingWaterfallString draws each phase as a bar placed at its offset
+// within the total response time.
+func timingWaterfallString(timing *domain.Timing) string {
+	if timing == nil || timing.Total <= 0 {
+		return "[blue]No timing recorded[-]"
+	}
+
+	var builder strings.Builder
+	for _, p := range timing.Phases {
+		offset := min(int(p.Start*waterfallWidth/timing.Total), waterfallWidth-1)
+		length := min(max(int(p.Duration*waterfallWidth/timing.Total), 1), waterfallWidth-offset)
+
+		fmt.Fprintf(&builder, "%-19s [gray]%s[-][%s]%s[-]%s %10s\n",
+			timingPhaseLabels[p.Name],
+			strings.Repeat("·", offset),
+			timingPhaseColors[p.Name],
+			strings.Repeat("█", length),
+			strings.Repeat(" ", waterfallWidth-offset-length),
+			p.Duration.Round(time.Microsecond))
+	}
+	fmt.Fprintf(&builder, "[yellow]%-19s[-] %s %10s\n", "Total", strings.Repeat(" ", waterfallWidth), timing.Total.Round(time.Microsecond))
+	if timing.ConnReused {
+		builder.WriteString("[gray]Connection reused, no DNS, connect or TLS phases[-]\n")
+	}
+	return builder.String()
+}
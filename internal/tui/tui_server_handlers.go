@@ -1,51 +1,355 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"strings"
 
+	"github.com/ManoloEsS/burrow/internal/config"
+	"github.com/ManoloEsS/burrow/internal/domain"
 	"github.com/ManoloEsS/burrow/internal/service"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
 )
 
+func (tui *Tui) setupServerKeybindings() {
+	tui.Components.ServerPath.SetAutocompleteFunc(func(current string) []string {
+		var entries []string
+		for _, entry := range tui.State.RecentServers {
+			if current != "" && strings.Contains(entry, current) {
+				entries = append(entries, entry)
+			}
+		}
+		return entries
+	})
+	tui.Components.ServerPath.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			go tui.handleStartServer()
+		case tcell.KeyTab:
+			tui.focusServerTable()
+		}
+	})
+
+	table := tui.Components.ServerTable
+	table.SetSelectedFunc(func(row, _ int) {
+		if name := tui.serverAt(row); name != "" {
+			go tui.handleToggleServer(name)
+		}
+	})
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyTab {
+			tui.focusServerInput()
+			return nil
+		}
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+		row, _ := table.GetSelection()
+		switch event.Rune() {
+		case 'a':
+			go tui.handleStartAllServers()
+			return nil
+		case 'x':
+			go tui.handleStopServer()
+			return nil
+		case 'd':
+			if name := tui.serverAt(row); name != "" {
+				go tui.handleRemoveServer(name)
+			}
+			return nil
+		default:
+			return event
+		}
+	})
+
+	tui.renderServerTable()
+}
+
+// handleStartServer starts the server selected in the table, or the one
+// typed in the input as "path [port] [build flags]". With an empty input it
+// starts every server in the config.
 func (tui *Tui) handleStartServer() {
-	serverPath := tui.Components.ServerPath.GetText()
-	if serverPath == "" {
-		tui.Ui.QueueUpdateDraw(func() {
-			tui.Components.ServerStatus.SetText("[red]Filepath is empty...[-]")
-		})
+	if tui.State.CurrentFocused == tui.Components.ServerTable {
+		row, _ := tui.Components.ServerTable.GetSelection()
+		if name := tui.serverAt(row); name != "" {
+			tui.handleToggleServer(name)
+		}
+		return
+	}
+
+	input := strings.TrimSpace(tui.Components.ServerPath.GetText())
+	if input == "" {
+		if len(tui.Config.Servers) == 0 {
+			tui.showServerError("Filepath is empty...")
+			return
+		}
+		tui.handleStartAllServers()
 		return
 	}
-	tui.Ui.QueueUpdateDraw(func() {
-		tui.Components.ServerStatus.SetText("starting server")
-	})
 
-	err := tui.ServerService.StartServer(serverPath, tui.Config.App.DefaultPort, tui.ServerUpdateChannel)
+	spec, err := parseServerInput(input, tui.Config.App.DefaultPort)
 	if err != nil {
-		tui.Ui.QueueUpdateDraw(func() {
-			tui.Components.ServerStatus.SetText(fmt.Sprintf("[red]Failed to start server: %s[-]", err.Error()))
-		})
+		tui.showServerError(err.Error())
 		return
 	}
+	tui.startServer(spec)
 }
 
-func (tui *Tui) handleStopServer() {
-	err := tui.ServerService.StopServer()
+// parseServerInput reads "path [port]" followed by any of -race, -watch,
+// -tags and -ldflags, quoted like shell words.
+func parseServerInput(input, defaultPort string) (config.ServerConfig, error) {
+	words, err := domain.SplitShellWords(input)
+	if err != nil {
+		return config.ServerConfig{}, err
+	}
+
+	spec := config.ServerConfig{Port: defaultPort}
+	var positional []string
+	for i := 0; i < len(words); i++ {
+		flag, value, hasValue := strings.Cut(words[i], "=")
+		switch flag {
+		case "-race":
+			spec.Race = true
+			continue
+		case "-watch":
+			spec.Watch = true
+			continue
+		case "-tags", "-ldflags":
+		default:
+			positional = append(positional, words[i])
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(words) {
+				return config.ServerConfig{}, fmt.Errorf("%s needs a value", flag)
+			}
+			i++
+			value = words[i]
+		}
+		if flag == "-tags" {
+			spec.Tags = strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+		} else {
+			spec.LDFlags = value
+		}
+	}
+
+	switch len(positional) {
+	case 2:
+		spec.Port = positional[1]
+		fallthrough
+	case 1:
+		spec.Path = positional[0]
+	case 0:
+		return config.ServerConfig{}, errors.New("server path is missing")
+	default:
+		return config.ServerConfig{}, fmt.Errorf("unexpected %q, use path [port] [-race] [-watch] [-tags=...] [-ldflags=...]", positional[2])
+	}
+	return spec, nil
+}
+
+// formatServerInput writes spec the way parseServerInput reads it.
+func formatServerInput(spec config.ServerConfig) string {
+	words := []string{quoteWord(spec.Path)}
+	if spec.Port != "" {
+		words = append(words, spec.Port)
+	}
+	if spec.Race {
+		words = append(words, "-race")
+	}
+	if spec.Watch {
+		words = append(words, "-watch")
+	}
+	if len(spec.Tags) > 0 {
+		words = append(words, "-tags="+strings.Join(spec.Tags, ","))
+	}
+	if spec.LDFlags != "" {
+		words = append(words, "-ldflags="+quoteWord(spec.LDFlags))
+	}
+	return strings.Join(words, " ")
+}
+
+// quoteWord quotes word when it would not be read back as a single word.
+func quoteWord(word string) string {
+	if strings.ContainsAny(word, " \t'\"\\") {
+		return domain.ShellQuote(word)
+	}
+	return word
+}
+
+// loadRecentServers keeps the servers started last to complete the server
+// input with.
+func (tui *Tui) loadRecentServers() {
+	targets, err := tui.ServerService.RecentTargets()
 	if err != nil {
-		tui.Ui.QueueUpdateDraw(func() {
-			tui.Components.ServerStatus.SetText(fmt.Sprintf("[red]Failed to stop server: %s[-]", err.Error()))
-		})
+		log.Printf("Error loading recent servers: %v", err)
 		return
 	}
+	tui.State.RecentServers = tui.State.RecentServers[:0]
+	for _, target := range targets {
+		tui.State.RecentServers = append(tui.State.RecentServers, formatServerInput(target))
+	}
+}
+
+// handleStartAllServers starts the configured servers that are not running.
+func (tui *Tui) handleStartAllServers() {
+	if len(tui.Config.Servers) == 0 {
+		tui.showServerError("No servers in the config")
+		return
+	}
+	for _, row := range tui.serverRows() {
+		if row.configured && !row.State.Active() {
+			tui.startServer(row.ServerConfig)
+		}
+	}
+}
+
+// handleToggleServer stops the named server when it runs and starts it
+// otherwise.
+func (tui *Tui) handleToggleServer(name string) {
+	for _, row := range tui.serverRows() {
+		if row.Name != name {
+			continue
+		}
+		if row.State.Active() {
+			if err := tui.ServerService.StopServer(name); err != nil {
+				tui.showServerError(fmt.Sprintf("Failed to stop server: %s", err.Error()))
+			}
+			return
+		}
+		tui.startServer(row.ServerConfig)
+		return
+	}
+}
+
+func (tui *Tui) handleStopServer() {
+	if err := tui.ServerService.StopAllServers(); err != nil {
+		tui.showServerError(fmt.Sprintf("Failed to stop servers: %s", err.Error()))
+	}
+}
+
+func (tui *Tui) handleRemoveServer(name string) {
+	if err := tui.ServerService.RemoveServer(name); err != nil {
+		tui.showServerError(err.Error())
+		return
+	}
+	tui.Ui.QueueUpdateDraw(tui.renderServerTable)
+}
+
+func (tui *Tui) startServer(spec config.ServerConfig) {
+	if err := tui.ServerService.StartServer(spec, tui.ServerUpdateChannel); err != nil {
+		tui.showServerError(fmt.Sprintf("Failed to start server: %s", err.Error()))
+	}
+}
+
+func (tui *Tui) showServerError(message string) {
+	tui.Ui.QueueUpdateDraw(func() {
+		tui.Components.StatusText.SetText(fmt.Sprintf("[red]%s[-]", tview.Escape(message)))
+	})
+}
+
+// serverRow is a line of the server table.
+type serverRow struct {
+	service.ServerInfo
+	// configured is set for the servers listed in the config
+	configured bool
+}
+
+// serverRows lists the configured servers first, with their state once they
+// have been started, then the servers started from the input.
+func (tui *Tui) serverRows() []serverRow {
+	running := tui.ServerService.Servers()
+	rows := make([]serverRow, 0, len(tui.Config.Servers)+len(running))
+	seen := make(map[string]bool, len(running))
+	for _, spec := range tui.Config.Servers {
+		if spec.Port == "" {
+			spec.Port = tui.Config.App.DefaultPort
+		}
+		row := serverRow{ServerInfo: service.ServerInfo{ServerConfig: spec, State: service.ServerStopped}, configured: true}
+		for _, info := range running {
+			if info.Name == spec.Name {
+				row.ServerInfo = info
+			}
+		}
+		seen[spec.Name] = true
+		rows = append(rows, row)
+	}
+	for _, info := range running {
+		if !seen[info.Name] {
+			rows = append(rows, serverRow{ServerInfo: info})
+		}
+	}
+	return rows
+}
+
+// serverAt returns the name of the server on row of the table.
+func (tui *Tui) serverAt(row int) string {
+	if row < 1 || row >= tui.Components.ServerTable.GetRowCount() {
+		return ""
+	}
+	return tui.Components.ServerTable.GetCell(row, 0).Text
+}
+
+func (tui *Tui) renderServerTable() {
+	table := tui.Components.ServerTable
+	selected, _ := table.GetSelection()
+	table.Clear()
+
+	for col, title := range []string{"Name", "Port", "Status", "Message"} {
+		table.SetCell(0, col, tview.NewTableCell(title).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false))
+	}
+	rows := tui.serverRows()
+	if len(rows) == 0 {
+		table.SetCell(1, 0, tview.NewTableCell("Server not running").
+			SetTextColor(tcell.ColorGray).
+			SetSelectable(false))
+		return
+	}
+	for i, row := range rows {
+		table.SetCell(i+1, 0, tview.NewTableCell(row.Name).SetTextColor(tcell.ColorBlue))
+		table.SetCell(i+1, 1, tview.NewTableCell(row.Port))
+		status := string(row.State)
+		var notes []string
+		if row.Watch && row.State.Active() {
+			notes = append(notes, "watch")
+		}
+		if count := len(row.Diagnostics); count > 0 {
+			notes = append(notes, fmt.Sprintf("%d errors", count))
+		}
+		if len(notes) > 0 {
+			status = fmt.Sprintf("%s (%s)", status, strings.Join(notes, ", "))
+		}
+		table.SetCell(i+1, 2, tview.NewTableCell(status).SetTextColor(serverStateColor(row.State)))
+		table.SetCell(i+1, 3, tview.NewTableCell(row.Message).SetExpansion(1))
+	}
+	table.Select(min(max(selected, 1), len(rows)), 0)
+}
+
+func serverStateColor(state service.ServerState) tcell.Color {
+	switch state {
+	case service.ServerRunning:
+		return tcell.ColorGreen
+	case service.ServerUnhealthy, service.ServerFailed:
+		return tcell.ColorRed
+	case service.ServerStopped:
+		return tcell.ColorGray
+	default:
+		return tcell.ColorYellow
+	}
 }
 
 func (tui *Tui) handleServerEvent(event service.UIEvent) {
 	tui.Ui.QueueUpdateDraw(func() {
-		switch event.Type {
-		case "error":
-			tui.Components.ServerStatus.SetText(fmt.Sprintf("[red]%s[-]", event.Message))
-		case "update":
-			tui.Components.ServerStatus.SetText(fmt.Sprintf("[green]%s[-]", event.Message))
-		default:
-			tui.Components.ServerStatus.SetText(fmt.Sprintf("[yellow]%s[-]", event.Message))
+		tui.renderServerTable()
+		if event.Type == "error" {
+			message := event.Message
+			if tui.hasBuildErrors(event.Server) {
+				message += ", F3 to show"
+			}
+			tui.Components.StatusText.SetText(fmt.Sprintf("[red]%s: %s[-]", tview.Escape(event.Server), tview.Escape(message)))
 		}
 	})
 }
@@ -0,0 +1,171 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/service"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// serverLogRedraw is the shortest time between two redraws of the log pane.
+const serverLogRedraw = 100 * time.Millisecond
+
+func (tui *Tui) setupServerLogKeybindings() {
+	tui.Components.ServerLogSearch.SetChangedFunc(func(_ string) {
+		tui.renderServerLogs()
+	})
+	tui.Components.ServerLogSearch.SetDoneFunc(func(_ tcell.Key) {
+		tui.Ui.SetFocus(tui.Components.ServerLogView)
+	})
+
+	tui.Components.ServerLogModal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			tui.hideServerLogs()
+			return nil
+		case tcell.KeyCtrlF:
+			tui.Ui.SetFocus(tui.Components.ServerLogSearch)
+			return nil
+		case tcell.KeyCtrlL:
+			tui.Ui.SetFocus(tui.Components.ServerLogView)
+			return nil
+		case tcell.KeyTab:
+			if tui.Ui.GetFocus() == tui.Components.ServerLogView {
+				tui.showNextServerLogs()
+				return nil
+			}
+			return event
+		default:
+			return event
+		}
+	})
+}
+
+// showServerLogs opens the log pane on the server selected in the table, or
+// the first one started.
+func (tui *Tui) showServerLogs() {
+	servers := tui.ServerService.Servers()
+	if len(servers) == 0 {
+		tui.Components.StatusText.SetText("No server has been started")
+		return
+	}
+	name := servers[0].Name
+	row, _ := tui.Components.ServerTable.GetSelection()
+	if selected := tui.serverAt(row); selected != "" {
+		for _, server := range servers {
+			if server.Name == selected {
+				name = selected
+			}
+		}
+	}
+
+	tui.State.ServerLogName = name
+	tui.Components.ServerLogSearch.SetText("")
+	tui.renderServerLogs()
+	tui.Components.ServerLogView.ScrollToEnd()
+	tui.Components.Pages.ShowPage(serverLogPage)
+	tui.Ui.SetFocus(tui.Components.ServerLogView)
+}
+
+func (tui *Tui) hideServerLogs() {
+	tui.Components.Pages.HidePage(serverLogPage)
+	tui.restoreFocus()
+}
+
+// showNextServerLogs moves the log pane to the server started after the one
+// shown.
+func (tui *Tui) showNextServerLogs() {
+	servers := tui.ServerService.Servers()
+	for i, server := range servers {
+		if server.Name == tui.State.ServerLogName {
+			tui.State.ServerLogName = servers[(i+1)%len(servers)].Name
+			break
+		}
+	}
+	tui.renderServerLogs()
+	tui.Components.ServerLogView.ScrollToEnd()
+}
+
+func (tui *Tui) serverLogsShown() bool {
+	front, _ := tui.Components.Pages.GetFrontPage()
+	return front == serverLogPage
+}
+
+// serverLogListener redraws the log pane as the servers write, at most once
+// per serverLogRedraw however fast the lines come.
+func (tui *Tui) serverLogListener() {
+	for range tui.ServerService.LogsChanged() {
+		tui.Ui.QueueUpdateDraw(func() {
+			if tui.serverLogsShown() {
+				tui.renderServerLogs()
+			}
+		})
+		time.Sleep(serverLogRedraw)
+	}
+}
+
+// renderServerLogs shows the lines of the server in the log pane that
+// contain the search, with the matches highlighted.
+func (tui *Tui) renderServerLogs() {
+	name := tui.State.ServerLogName
+	lines := tui.ServerService.Logs(name)
+	search := strings.TrimSpace(tui.Components.ServerLogSearch.GetText())
+
+	var builder strings.Builder
+	shown := 0
+	for _, line := range lines {
+		text := line.Text
+		if search != "" && !strings.Contains(strings.ToLower(text), strings.ToLower(search)) {
+			continue
+		}
+		shown++
+		color := logLevelColor(line.Level)
+		fmt.Fprintf(&builder, "[gray]%s[-] [%s]%s[-]\n", line.Time.Format("15:04:05"), color, highlightMatches(text, search, color))
+	}
+
+	view := tui.Components.ServerLogView
+	view.SetText(builder.String())
+	if search == "" {
+		view.SetTitle(fmt.Sprintf("Logs: %s (%d lines)", name, len(lines)))
+	} else {
+		view.SetTitle(fmt.Sprintf("Logs: %s (%d of %d lines)", name, shown, len(lines)))
+	}
+}
+
+// highlightMatches escapes text and marks where it contains search, going
+// back to color after each match.
+func highlightMatches(text, search, color string) string {
+	lower, needle := strings.ToLower(text), strings.ToLower(search)
+	if search == "" || len(lower) != len(text) {
+		return tview.Escape(text)
+	}
+	var builder strings.Builder
+	for {
+		i := strings.Index(lower, needle)
+		if i < 0 {
+			builder.WriteString(tview.Escape(text))
+			return builder.String()
+		}
+		end := i + len(needle)
+		fmt.Fprintf(&builder, "%s[black:yellow]%s[%s:-]", tview.Escape(text[:i]), tview.Escape(text[i:end]), color)
+		text, lower = text[end:], lower[end:]
+	}
+}
+
+func logLevelColor(level service.LogLevel) string {
+	switch level {
+	case service.LogError:
+		return "red"
+	case service.LogWarn:
+		return "yellow"
+	case service.LogInfo:
+		return "green"
+	case service.LogDebug:
+		return "gray"
+	default:
+		return "white"
+	}
+}
@@ -0,0 +1,184 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/rivo/tview"
+)
+
+// the Response view drops its oldest lines past this while streaming
+const streamMaxLines = 5000
+
+// streamBuffer collects what arrives on a stream or WebSocket, the ticker
+// in showStream moves it to the Response view.
+type streamBuffer struct {
+	mu      sync.Mutex
+	pending strings.Builder
+	unit    string
+	count   int
+	bytes   int64
+	started time.Time
+}
+
+func newStreamBuffer(unit string) *streamBuffer {
+	return &streamBuffer{unit: unit, started: time.Now()}
+}
+
+// add queues text for the view, counting one more event or frame of size
+// bytes.
+func (b *streamBuffer) add(text string, size int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.count++
+	b.bytes += int64(size)
+	b.pending.WriteString(text)
+}
+
+func (b *streamBuffer) addEvent(event domain.Event) {
+	b.add(eventString(event), len(event.Data))
+}
+
+// take returns the text of the events received since the last call.
+func (b *streamBuffer) take() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	text := b.pending.String()
+	b.pending.Reset()
+	return text
+}
+
+func (b *streamBuffer) stats() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return streamStatsString(b.count, b.unit, b.bytes, time.Since(b.started))
+}
+
+func streamStatsString(count int, unit string, bytes int64, elapsed time.Duration) string {
+	return fmt.Sprintf("%d %s, %s in %s (%.1f/s)",
+		count, unit, domain.FormatBytes(bytes), elapsed.Truncate(elapsedInterval), domain.EventRate(count, elapsed))
+}
+
+func eventString(event domain.Event) string {
+	if event.IsChunk() {
+		return tview.Escape(event.Data)
+	}
+	header := fmt.Sprintf("[gray]%s[-] [yellow]%s[-]", event.ReceivedAt.Format("15:04:05.000"), tview.Escape(event.Type))
+	if event.ID != "" {
+		header += fmt.Sprintf(" [gray]#%s[-]", tview.Escape(event.ID))
+	}
+	return fmt.Sprintf("%s\n%s\n\n", header, tview.Escape(event.Data))
+}
+
+// streamCurrentRequest sends the current request as a stream, appending what
+// arrives to the Response view until the server ends it or C-k stops it.
+func (tui *Tui) streamCurrentRequest() {
+	ctx, finish := tui.beginRequest()
+	buffer := newStreamBuffer("events")
+	stopStream := tui.showStream(buffer, streamLabels{
+		waiting: "Waiting for the stream...",
+		running: "Streaming",
+		keys:    "C-k: stop, c: clear",
+	})
+
+	resp, err := tui.HttpService.StreamRequest(ctx, tui.State.CurrentRequest, buffer.addEvent)
+	stopStream()
+	stopped := ctx.Err() != nil
+	if !finish() {
+		return
+	}
+	if err != nil {
+		tui.showStreamError(err)
+		return
+	}
+
+	results := domain.EvaluateAssertions(tui.State.CurrentRequest.Assertions, resp)
+	ended := "ended"
+	if stopped {
+		ended = "stopped"
+	}
+	stats := streamStatsString(resp.Stream.Events, "events", resp.BodySize, resp.Stream.Duration)
+	tui.Ui.QueueUpdateDraw(func() {
+		tui.State.CurrentResponse = resp
+		tui.State.CurrentResults = results
+		fmt.Fprint(tui.Components.ResponseView, buffer.take())
+		tui.Components.StatusText.SetText(fmt.Sprintf("%s, stream %s: %s\nTab: response tabs, c: clear", resp.Status, ended, stats))
+	})
+}
+
+func (tui *Tui) showStreamError(err error) {
+	tui.Ui.QueueUpdateDraw(func() {
+		if errors.Is(err, context.Canceled) {
+			tui.Components.ResponseView.SetText("[yellow]Request cancelled[-]")
+			tui.Components.StatusText.SetText("Request cancelled")
+			return
+		}
+		tui.Components.ResponseView.SetText(fmt.Sprintf("[red]Error: %s[-]", err.Error()))
+		tui.Components.StatusText.SetText("")
+	})
+}
+
+// streamLabels are the status shown before anything arrives, the label of
+// the running stats and the keys that apply meanwhile.
+type streamLabels struct {
+	waiting string
+	running string
+	keys    string
+}
+
+// showStream clears the Response view for the stream and moves what arrives
+// in buffer to it with the running stats until the returned stop func is
+// called.
+func (tui *Tui) showStream(buffer *streamBuffer, labels streamLabels) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	tui.Ui.QueueUpdateDraw(func() {
+		// the previous response no longer matches the view
+		_ = tui.State.CurrentResponse.RemoveBodyFile()
+		tui.State.CurrentResponse = nil
+		tui.State.CurrentResults = nil
+		tui.Components.ResponseView.Clear().SetMaxLines(streamMaxLines).ScrollToEnd()
+		tui.Components.StatusText.SetText(labels.waiting + "\nC-k: stop")
+	})
+
+	render := func() {
+		if ctx.Err() != nil {
+			return
+		}
+		if text := buffer.take(); text != "" {
+			fmt.Fprint(tui.Components.ResponseView, text)
+		}
+		tui.Components.StatusText.SetText(fmt.Sprintf("%s: %s\n%s", labels.running, buffer.stats(), labels.keys))
+	}
+
+	go func() {
+		ticker := time.NewTicker(elapsedInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				tui.Ui.QueueUpdateDraw(render)
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.ResponseView.SetMaxLines(0)
+		})
+	}
+}
+
+// clearResponseView empties the Response view, for a stream the events keep
+// arriving below.
+func (tui *Tui) clearResponseView() {
+	tui.Components.ResponseView.Clear()
+}
@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/ManoloEsS/burrow/internal/domain"
+	"github.com/rivo/tview"
+)
+
+// wsSession is an open WebSocket, messages sent on outgoing go out on it
+// until done is closed.
+type wsSession struct {
+	outgoing chan domain.WSMessage
+	done     chan struct{}
+}
+
+func (tui *Tui) webSocket() *wsSession {
+	tui.requestMu.Lock()
+	defer tui.requestMu.Unlock()
+	return tui.ws
+}
+
+func (tui *Tui) setWebSocket(session *wsSession) {
+	tui.requestMu.Lock()
+	defer tui.requestMu.Unlock()
+	tui.ws = session
+}
+
+// sendWebSocket connects the current WebSocket request, or sends the Body as
+// a message when it is already connected. Sent messages are added to the
+// script saved with the request.
+func (tui *Tui) sendWebSocket() {
+	msg, ok, err := tui.State.CurrentRequest.WSMessage()
+	if err != nil {
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.StatusText.SetText(fmt.Sprintf("[red]Error: %s[-]", err.Error()))
+		})
+		return
+	}
+
+	session := tui.webSocket()
+	if session == nil {
+		tui.connectWebSocket()
+		return
+	}
+	if !ok {
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.StatusText.SetText("Type a message in Body to send it")
+		})
+		return
+	}
+
+	select {
+	case session.outgoing <- msg:
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.State.WSScript = append(tui.State.WSScript, msg)
+		})
+	case <-session.done:
+		tui.Ui.QueueUpdateDraw(func() {
+			tui.Components.StatusText.SetText("Connection closed, C-s: reconnect")
+		})
+	}
+}
+
+func (tui *Tui) connectWebSocket() {
+	ctx, finish := tui.beginRequest()
+	session := &wsSession{outgoing: make(chan domain.WSMessage), done: make(chan struct{})}
+	tui.setWebSocket(session)
+
+	buffer := newStreamBuffer("frames")
+	stopStream := tui.showStream(buffer, streamLabels{
+		waiting: "Connecting...",
+		running: "Connected",
+		keys:    "C-s: send Body, C-k: close, c: clear",
+	})
+
+	resp, err := tui.HttpService.ConnectWebSocket(ctx, tui.State.CurrentRequest, session.outgoing, func(frame domain.WSFrame) {
+		buffer.add(frameString(frame), len(frame.Data))
+	})
+	close(session.done)
+	tui.setWebSocket(nil)
+	stopStream()
+	closed := ctx.Err() != nil
+	if !finish() {
+		return
+	}
+	if err != nil {
+		tui.showStreamError(err)
+		return
+	}
+
+	ended := "closed by the server"
+	if closed {
+		ended = "closed"
+	}
+	stats := buffer.stats()
+	tui.Ui.QueueUpdateDraw(func() {
+		tui.State.CurrentResponse = resp
+		tui.State.CurrentResults = domain.EvaluateAssertions(tui.State.CurrentRequest.Assertions, resp)
+		fmt.Fprint(tui.Components.ResponseView, buffer.take())
+		tui.Components.StatusText.SetText(fmt.Sprintf("Connection %s: %s\nC-s: reconnect, c: clear", ended, stats))
+	})
+}
+
+func frameString(frame domain.WSFrame) string {
+	direction := "[green]<-[-]"
+	if frame.Sent {
+		direction = "[blue]->[-]"
+	}
+	text := fmt.Sprintf("[gray]%s[-] %s [yellow]%s[-]\n", frame.Time.Format("15:04:05.000"), direction, tview.Escape(frame.Label()))
+	if payload := frame.Payload(); payload != "" {
+		text += tview.Escape(payload) + "\n"
+	}
+	return text
+}
+
+// wsScript returns the messages the current WebSocket request replays when
+// it connects.
+func (tui *Tui) wsScript() []domain.WSMessage {
+	return slices.Clone(tui.State.WSScript)
+}
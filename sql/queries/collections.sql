@@ -0,0 +1,21 @@
+-- name: CreateCollection :one
+INSERT INTO collections (
+  name, parent_id
+) VALUES (
+    ?, ?
+)
+RETURNING *;
+
+-- name: GetCollection :one
+SELECT * FROM collections WHERE id = ? LIMIT 1;
+
+-- name: ListCollections :many
+SELECT * FROM collections ORDER BY name;
+
+-- name: UpdateCollection :exec
+UPDATE collections
+SET name = ?, parent_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: DeleteCollection :exec
+DELETE FROM collections WHERE id = ?;
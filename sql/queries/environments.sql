@@ -0,0 +1,18 @@
+-- name: UpsertEnvironment :one
+INSERT INTO environments (
+  name, variables_json
+) VALUES (
+    ?, ?
+)
+ON CONFLICT (name) DO UPDATE
+SET variables_json = excluded.variables_json, updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetEnvironment :one
+SELECT * FROM environments WHERE name = ? LIMIT 1;
+
+-- name: ListEnvironments :many
+SELECT * FROM environments ORDER BY name ASC;
+
+-- name: DeleteEnvironment :exec
+DELETE FROM environments WHERE name = ?;
@@ -0,0 +1,40 @@
+-- name: CreateHistoryEntry :one
+INSERT INTO history (
+  sent_at, method, url, status_code, request_json, response_json, error, size
+) VALUES (
+    ?, ?, ?, ?, ?, ?, ?, ?
+)
+RETURNING *;
+
+-- name: GetHistoryEntry :one
+SELECT * FROM history WHERE id = ? LIMIT 1;
+
+-- name: ListHistory :many
+SELECT * FROM history ORDER BY id DESC LIMIT ?;
+
+-- name: SearchHistory :many
+SELECT * FROM history
+WHERE method || ' ' || url || ' ' || status_code LIKE sqlc.arg(pattern) ESCAPE '\'
+ORDER BY id DESC
+LIMIT sqlc.arg(limit);
+
+-- name: DeleteHistoryEntry :exec
+DELETE FROM history WHERE id = ?;
+
+-- name: ClearHistory :exec
+DELETE FROM history;
+
+-- name: DeleteHistoryBefore :exec
+DELETE FROM history WHERE sent_at < ?;
+
+-- name: DeleteHistoryBeyondCount :exec
+DELETE FROM history WHERE id NOT IN (
+  SELECT id FROM history ORDER BY id DESC LIMIT ?
+);
+
+-- name: DeleteHistoryBeyondSize :exec
+DELETE FROM history WHERE id IN (
+  SELECT id FROM (
+    SELECT id, SUM(size) OVER (ORDER BY id DESC) AS running_size FROM history
+  ) WHERE running_size > ? AND id < (SELECT MAX(id) FROM history)
+);
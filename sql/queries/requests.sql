@@ -1,8 +1,8 @@
 -- name: CreateRequest :one
 INSERT INTO request_blobs (
-  name, request_json
+  name, request_json, collection_id
 ) VALUES (
-    ?, ?
+    ?, ?, ?
 )
 RETURNING *;
 
@@ -18,6 +18,15 @@ SET request_json = ?, updated_at = CURRENT_TIMESTAMP
 WHERE name = ?
 RETURNING *;
 
+-- name: MoveRequest :exec
+UPDATE request_blobs
+SET collection_id = ?, updated_at = CURRENT_TIMESTAMP
+WHERE name = ?;
+
+-- name: RenameRequest :exec
+UPDATE request_blobs
+SET name = sqlc.arg(new_name), request_json = sqlc.arg(request_json), updated_at = CURRENT_TIMESTAMP
+WHERE name = sqlc.arg(name);
+
 -- name: DeleteRequest :exec
 DELETE FROM request_blobs WHERE name = ?;
-
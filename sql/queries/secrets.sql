@@ -0,0 +1,17 @@
+-- name: UpsertSecret :exec
+INSERT INTO secrets (
+  name, salt, nonce, ciphertext
+) VALUES (
+    ?, ?, ?, ?
+)
+ON CONFLICT (name) DO UPDATE
+SET salt = excluded.salt, nonce = excluded.nonce, ciphertext = excluded.ciphertext, updated_at = CURRENT_TIMESTAMP;
+
+-- name: GetSecret :one
+SELECT * FROM secrets WHERE name = ? LIMIT 1;
+
+-- name: ListSecretNames :many
+SELECT name FROM secrets ORDER BY name ASC;
+
+-- name: DeleteSecret :execrows
+DELETE FROM secrets WHERE name = ?;
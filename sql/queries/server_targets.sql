@@ -0,0 +1,16 @@
+-- name: UpsertServerTarget :exec
+INSERT INTO server_targets (
+  path, spec_json, used_at
+) VALUES (
+    ?, ?, ?
+)
+ON CONFLICT (path) DO UPDATE
+SET spec_json = excluded.spec_json, used_at = excluded.used_at;
+
+-- name: ListServerTargets :many
+SELECT * FROM server_targets ORDER BY used_at DESC LIMIT ?;
+
+-- name: DeleteServerTargetsBeyondCount :exec
+DELETE FROM server_targets WHERE path NOT IN (
+  SELECT path FROM server_targets ORDER BY used_at DESC LIMIT ?
+);
@@ -9,5 +9,50 @@ CREATE TABLE request_blobs (
   name TEXT PRIMARY KEY,
   created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
   updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-  request_json TEXT NOT_NULL
+  request_json TEXT NOT_NULL,
+  collection_id INTEGER REFERENCES collections (id) ON DELETE CASCADE
+);
+
+CREATE TABLE environments (
+  name TEXT PRIMARY KEY,
+  created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  variables_json TEXT NOT NULL
+);
+
+CREATE TABLE history (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  sent_at DATETIME NOT NULL,
+  method TEXT NOT NULL,
+  url TEXT NOT NULL,
+  status_code INTEGER NOT NULL DEFAULT 0,
+  request_json TEXT NOT NULL,
+  response_json TEXT NOT NULL,
+  error TEXT NOT NULL DEFAULT '',
+  size INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX history_sent_at_idx ON history (sent_at);
+
+CREATE TABLE collections (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  name TEXT NOT NULL,
+  parent_id INTEGER REFERENCES collections (id) ON DELETE CASCADE,
+  created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE secrets (
+  name TEXT PRIMARY KEY,
+  created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+  salt BLOB NOT NULL,
+  nonce BLOB NOT NULL,
+  ciphertext BLOB NOT NULL
+);
+
+CREATE TABLE server_targets (
+  path TEXT PRIMARY KEY,
+  spec_json TEXT NOT NULL,
+  used_at DATETIME NOT NULL
 );
@@ -3,14 +3,21 @@ package main
 import (
 	"log"
 	"net/http"
+	"os"
 	"time"
 )
 
 func main() {
 	mux := http.NewServeMux()
 
+	// Burrow passes the port of the server in PORT
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+
 	s := &http.Server{
-		Addr:         ":8080",
+		Addr:         ":" + port,
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 90 * time.Second,
 		Handler:      mux,